@@ -0,0 +1,234 @@
+// Concurrent Map
+// Go maps are not safe for concurrent use: a write at the same time as another read or write is a data race,
+// and the runtime stops the program with "fatal error: concurrent map writes" (see "map.go").
+// The simplest fix is a map guarded by a mutex, but then all the goroutines wait for the same lock.
+// The concurrent map below is split in shards, each one a map with its own lock, and each key always goes to the
+// same shard (chosen by the hash of the key). So the goroutines using different shards do not wait for each other.
+// The standard library has "sync.Map", which is faster when the keys are written once and read many times
+// (e.g., a cache that only grows), but it is slower for frequent writes, and it has no typed API.
+
+package containers
+
+import (
+	"fmt"
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// Declaring the Concurrent Map
+// Each shard is padded to 64 bytes (the size of a cache line), so the locks of two shards are not in the same
+// cache line. Otherwise, the CPUs would invalidate each other's cache when locking different shards
+// (false sharing).
+// The number of shards is a power of two, so the shard is found with a mask instead of a division.
+// The zero value has no shards and no hash seed, so it is not ready to use: the map must be created with
+// NewConcurrentMap, and it must not be copied after that.
+type ConcurrentMap[K comparable, V any] struct {
+	shards []shard[K, V]
+	mask   uint64
+	seed   maphash.Seed
+}
+
+type shard[K comparable, V any] struct {
+	sync.RWMutex
+	m map[K]V
+	_ [64 - unsafe.Sizeof(sync.RWMutex{}) - unsafe.Sizeof(map[K]V(nil))]byte
+}
+
+// Creating Concurrent Maps
+// The number of shards is rounded up to a power of two. When it is 0 or negative, the map has 4 shards per CPU,
+// which keeps the contention low.
+func NewConcurrentMap[K comparable, V any](shards int) *ConcurrentMap[K, V] {
+	if shards <= 0 {
+		shards = 4 * runtime.GOMAXPROCS(0)
+	}
+	if shards > 1<<16 {
+		panic(fmt.Sprintf("concurrentmap: too many shards %d", shards))
+	}
+	n := 1 << bits.Len(uint(shards-1))
+	m := &ConcurrentMap[K, V]{shards: make([]shard[K, V], n), mask: uint64(n - 1), seed: maphash.MakeSeed()}
+	for i := range m.shards {
+		m.shards[i].m = map[K]V{}
+	}
+	return m
+}
+
+func (m *ConcurrentMap[K, V]) shard(key K) *shard[K, V] {
+	if m.shards == nil {
+		panic("concurrentmap: map not created with NewConcurrentMap")
+	}
+	return &m.shards[maphash.Comparable(m.seed, key)&m.mask]
+}
+
+// Shards returns the number of shards.
+func (m *ConcurrentMap[K, V]) Shards() int {
+	return len(m.shards)
+}
+
+// Loading and Storing
+// The reads take the read lock of the shard, so they can run at the same time, and the writes take the write lock.
+func (m *ConcurrentMap[K, V]) Load(key K) (V, bool) {
+	s := m.shard(key)
+	s.RLock()
+	defer s.RUnlock()
+	v, ok := s.m[key]
+	return v, ok
+}
+func (m *ConcurrentMap[K, V]) Store(key K, value V) {
+	s := m.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	s.m[key] = value
+}
+func (m *ConcurrentMap[K, V]) Delete(key K) {
+	s := m.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	delete(s.m, key)
+}
+
+// Load or Store
+// A Load followed by a Store is not atomic: another goroutine may store the key between them.
+// LoadOrStore does both under the same lock: it returns the existing value and true, or stores the value and
+// returns it with false. This is how a value is created once (e.g., a connection per host).
+func (m *ConcurrentMap[K, V]) LoadOrStore(key K, value V) (V, bool) {
+	s := m.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	if v, ok := s.m[key]; ok {
+		return v, true
+	}
+	s.m[key] = value
+	return value, false
+}
+
+// LoadAndDelete removes the key, and returns its value and whether it existed.
+func (m *ConcurrentMap[K, V]) LoadAndDelete(key K) (V, bool) {
+	s := m.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	v, ok := s.m[key]
+	delete(s.m, key)
+	return v, ok
+}
+
+// Compute
+// The method below updates the value of the key atomically: the function receives the current value (and
+// whether it exists), and returns the new value, and whether to keep it (false deletes the key).
+// Compute returns the new value, and whether the key exists after the update.
+// Example (a counter):
+//
+//	m.Compute(key, func(n int, _ bool) (int, bool) { return n + 1, true })
+//
+// The function runs under the lock of the shard, so it must be fast, and it must not use the map.
+func (m *ConcurrentMap[K, V]) Compute(key K, fn func(old V, loaded bool) (V, bool)) (V, bool) {
+	s := m.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	old, loaded := s.m[key]
+	v, keep := fn(old, loaded)
+	if !keep {
+		delete(s.m, key)
+		var zero V
+		return zero, false
+	}
+	s.m[key] = v
+	return v, true
+}
+
+// Len returns the number of keys. The shards are counted one by one, so the result may be outdated when
+// there are concurrent writes.
+func (m *ConcurrentMap[K, V]) Len() int {
+	n := 0
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.RLock()
+		n += len(s.m)
+		s.RUnlock()
+	}
+	return n
+}
+
+// Ranging
+// Range calls the function for each key and value, until it returns false. Like "sync.Map.Range", it does not
+// lock the whole map: each shard is copied under its lock, and the function is called after unlocking, so it can
+// use the map. The keys written during the iteration may or may not be seen.
+func (m *ConcurrentMap[K, V]) Range(fn func(key K, value V) bool) {
+	type kv struct {
+		k K
+		v V
+	}
+	var entries []kv
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.RLock()
+		entries = entries[:0]
+		for k, v := range s.m {
+			entries = append(entries, kv{k, v})
+		}
+		s.RUnlock()
+		for _, e := range entries {
+			if !fn(e.k, e.v) {
+				return
+			}
+		}
+	}
+}
+
+// Snapshots
+// Snapshot returns a copy of the map at a single point in time: all the shards are locked (always in the same
+// order, so two snapshots cannot deadlock) before any of them is copied.
+// All iterates over a snapshot, so it is consistent, but it copies the whole map first.
+func (m *ConcurrentMap[K, V]) Snapshot() map[K]V {
+	for i := range m.shards {
+		m.shards[i].RLock()
+	}
+	n := 0
+	for i := range m.shards {
+		n += len(m.shards[i].m)
+	}
+	snapshot := make(map[K]V, n)
+	for i := range m.shards {
+		for k, v := range m.shards[i].m {
+			snapshot[k] = v
+		}
+		m.shards[i].RUnlock()
+	}
+	return snapshot
+}
+func (m *ConcurrentMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range m.Snapshot() {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Using the Concurrent Map
+// The function below counts words from several goroutines. With a plain map, it would crash with
+// "fatal error: concurrent map writes".
+func UsingConcurrentMap() {
+	m := NewConcurrentMap[string, int](8)
+	words := []string{"go", "map", "go", "lock", "go", "map"}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, w := range words {
+				m.Compute(w, func(n int, _ bool) (int, bool) { return n + 1, true })
+			}
+		}()
+	}
+	wg.Wait()
+	fmt.Println(m.Len(), m.Shards()) // Output: 3 8
+	n, _ := m.Load("go")
+	fmt.Println("go:", n) // Output: go: 12
+	v, loaded := m.LoadOrStore("go", 0)
+	fmt.Println(v, loaded) // Output: 12 true
+}
@@ -0,0 +1,206 @@
+package containers
+
+import (
+	"maps"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestConcurrentMap(t *testing.T) {
+	m := NewConcurrentMap[string, int](5)
+	if m.Shards() != 8 {
+		t.Errorf("Shards = %d; expected 5 rounded up to 8", m.Shards())
+	}
+	if NewConcurrentMap[int, int](0).Shards() < 4 || NewConcurrentMap[int, int](1).Shards() != 1 {
+		t.Errorf("default or single shard count is wrong")
+	}
+	m.Store("a", 1)
+	m.Store("b", 2)
+	if v, ok := m.Load("a"); !ok || v != 1 {
+		t.Errorf("Load(a) = %v, %v", v, ok)
+	}
+	if _, ok := m.Load("z"); ok {
+		t.Errorf("Load of a missing key must return false")
+	}
+	if v, loaded := m.LoadOrStore("a", 9); !loaded || v != 1 {
+		t.Errorf("LoadOrStore(a) = %v, %v", v, loaded)
+	}
+	if v, loaded := m.LoadOrStore("c", 3); loaded || v != 3 {
+		t.Errorf("LoadOrStore(c) = %v, %v", v, loaded)
+	}
+	if v, ok := m.Compute("a", func(n int, loaded bool) (int, bool) { return n + 10, loaded }); !ok || v != 11 {
+		t.Errorf("Compute(a) = %v, %v", v, ok)
+	}
+	if _, ok := m.Compute("b", func(int, bool) (int, bool) { return 0, false }); ok || m.Len() != 2 {
+		t.Errorf("Compute returning false must delete the key")
+	}
+	if v, ok := m.LoadAndDelete("c"); !ok || v != 3 {
+		t.Errorf("LoadAndDelete(c) = %v, %v", v, ok)
+	}
+	m.Delete("missing")
+	if got := m.Snapshot(); !maps.Equal(got, map[string]int{"a": 11}) {
+		t.Errorf("Snapshot = %v", got)
+	}
+}
+
+// The zero value must fail with a clear message, instead of a nil pointer or an uninitialized seed.
+func TestConcurrentMapZeroValue(t *testing.T) {
+	var m ConcurrentMap[string, int]
+	if m.Len() != 0 || m.Shards() != 0 {
+		t.Errorf("the zero value must be empty")
+	}
+	defer func() {
+		if r := recover(); r != "concurrentmap: map not created with NewConcurrentMap" {
+			t.Errorf("Store on the zero value panicked with %v", r)
+		}
+	}()
+	m.Store("a", 1)
+}
+
+func TestConcurrentMapRange(t *testing.T) {
+	m := NewConcurrentMap[int, int](4)
+	for i := range 100 {
+		m.Store(i, i*i)
+	}
+	seen := map[int]int{}
+	m.Range(func(k, v int) bool {
+		seen[k] = v
+		// The function can use the map, since the shard is not locked.
+		m.Store(k+1000, v)
+		return true
+	})
+	if len(seen) < 100 {
+		t.Errorf("Range visited %d keys", len(seen))
+	}
+	count := 0
+	m.Range(func(int, int) bool {
+		count++
+		return count < 10
+	})
+	if count != 10 {
+		t.Errorf("Range must stop when the function returns false, visited %d", count)
+	}
+	if got := maps.Collect(m.All()); !maps.Equal(got, m.Snapshot()) {
+		t.Errorf("All must iterate over a snapshot")
+	}
+}
+
+// TestConcurrentMapParallel must be run with -race: the goroutines use the same keys at the same time.
+func TestConcurrentMapParallel(t *testing.T) {
+	const goroutines, ops = 8, 2000
+	m := NewConcurrentMap[int, int](4)
+	var created atomic.Int64
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := rand.New(rand.NewPCG(uint64(g), 1))
+			for i := range ops {
+				key := r.IntN(64)
+				m.Compute(-1, func(n int, _ bool) (int, bool) { return n + 1, true })
+				if _, loaded := m.LoadOrStore(1000+key, g); !loaded {
+					created.Add(1)
+				}
+				switch i % 4 {
+				case 0:
+					m.Store(key, i)
+				case 1:
+					m.Load(key)
+				case 2:
+					m.Delete(key)
+				case 3:
+					m.Range(func(int, int) bool { return true })
+					m.Snapshot()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if n, _ := m.Load(-1); n != goroutines*ops {
+		t.Errorf("counter = %d; expected %d (lost updates)", n, goroutines*ops)
+	}
+	if created.Load() > 64 {
+		t.Errorf("LoadOrStore created %d values for 64 keys", created.Load())
+	}
+}
+
+// The benchmarks below compare the sharded map, a mutex-guarded map and "sync.Map", with all the CPUs
+// running a mix of 90% reads and 10% writes, or only writes.
+type benchMap interface {
+	Load(key string) (int, bool)
+	Store(key string, value int)
+}
+
+type mutexMap struct {
+	mu sync.RWMutex
+	m  map[string]int
+}
+
+func (m *mutexMap) Load(key string) (int, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.m[key]
+	return v, ok
+}
+func (m *mutexMap) Store(key string, value int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.m[key] = value
+}
+
+type syncMap struct {
+	m sync.Map
+}
+
+func (m *syncMap) Load(key string) (int, bool) {
+	v, ok := m.m.Load(key)
+	if !ok {
+		return 0, false
+	}
+	return v.(int), true
+}
+func (m *syncMap) Store(key string, value int) {
+	m.m.Store(key, value)
+}
+
+func benchmarkMap(b *testing.B, m benchMap, writes int) {
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		m.Store(keys[i], i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := rand.IntN(len(keys))
+		for pb.Next() {
+			k := keys[i%len(keys)]
+			if i%10 < writes {
+				m.Store(k, i)
+			} else {
+				m.Load(k)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkConcurrentMap(b *testing.B) {
+	for _, mix := range []struct {
+		name   string
+		writes int
+	}{{"Reads", 1}, {"Writes", 10}} {
+		b.Run(mix.name+"/Sharded", func(b *testing.B) {
+			benchmarkMap(b, NewConcurrentMap[string, int](0), mix.writes)
+		})
+		b.Run(mix.name+"/Mutex", func(b *testing.B) {
+			benchmarkMap(b, &mutexMap{m: map[string]int{}}, mix.writes)
+		})
+		b.Run(mix.name+"/SyncMap", func(b *testing.B) {
+			benchmarkMap(b, &syncMap{}, mix.writes)
+		})
+	}
+}
@@ -0,0 +1,357 @@
+// Dense Matrix
+// The matrices of "matrix.go" are slices of slices, where each row is a separate allocation.
+// For numeric work, a matrix is usually stored in a single contiguous slice, row by row (row-major order),
+// so the element (i, j) is at the index i*cols + j. This uses one allocation, and the rows are next to each
+// other in memory, which makes the loops over rows cache friendly.
+// The generic matrix below works with any numeric type, using a constraint like the "Numeric" interface of
+// "syntax/generics.go". The operations that divide (determinant, inverse and solving) return float64 values.
+
+package containers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Numeric Constraint
+// The constraint below allows all the integer and float types, and the types based on them ("~").
+type Numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Matrix Errors
+// The errors below are returned when the operation is not defined for the matrices.
+var (
+	ErrDimensionMismatch = errors.New("matrix: dimensions mismatch")
+	ErrNotSquare         = errors.New("matrix: not square")
+	ErrSingular          = errors.New("matrix: singular")
+)
+
+// Declaring the Matrix Type
+// The data has rows*cols elements, in row-major order.
+type Matrix[T Numeric] struct {
+	rows, cols int
+	data       []T
+}
+
+// Creating Matrices
+// NewMatrix creates a zero matrix, MatrixOf copies the rows of a slice of slices, and Identity creates a square
+// matrix with ones in the diagonal.
+func NewMatrix[T Numeric](rows, cols int) *Matrix[T] {
+	if rows < 0 || cols < 0 {
+		panic(fmt.Sprintf("matrix: negative dimensions %dx%d", rows, cols))
+	}
+	return &Matrix[T]{rows: rows, cols: cols, data: make([]T, rows*cols)}
+}
+func MatrixOf[T Numeric](rows [][]T) (*Matrix[T], error) {
+	cols := 0
+	if len(rows) > 0 {
+		cols = len(rows[0])
+	}
+	m := NewMatrix[T](len(rows), cols)
+	for i, row := range rows {
+		if len(row) != cols {
+			return nil, fmt.Errorf("%w: row %d has %d columns, expected %d", ErrDimensionMismatch, i, len(row), cols)
+		}
+		copy(m.Row(i), row)
+	}
+	return m, nil
+}
+func Identity[T Numeric](n int) *Matrix[T] {
+	m := NewMatrix[T](n, n)
+	for i := range n {
+		m.Set(i, i, 1)
+	}
+	return m
+}
+
+// Accessing Elements
+// The elements are accessed by row and column. Row returns the row as a sub-slice of the data, so changing the
+// row changes the matrix.
+func (m *Matrix[T]) Rows() int {
+	return m.rows
+}
+func (m *Matrix[T]) Cols() int {
+	return m.cols
+}
+func (m *Matrix[T]) At(i, j int) T {
+	m.check(i, j)
+	return m.data[i*m.cols+j]
+}
+func (m *Matrix[T]) Set(i, j int, v T) {
+	m.check(i, j)
+	m.data[i*m.cols+j] = v
+}
+func (m *Matrix[T]) Row(i int) []T {
+	if i < 0 || i >= m.rows {
+		panic(fmt.Sprintf("matrix: row %d out of range %dx%d", i, m.rows, m.cols))
+	}
+	return m.data[i*m.cols : (i+1)*m.cols : (i+1)*m.cols]
+}
+func (m *Matrix[T]) check(i, j int) {
+	// The flat index of an invalid column could be a valid index of another row, so it must be checked.
+	if i < 0 || i >= m.rows || j < 0 || j >= m.cols {
+		panic(fmt.Sprintf("matrix: index (%d, %d) out of range %dx%d", i, j, m.rows, m.cols))
+	}
+}
+
+// Adding and Scaling
+// The sum is defined for matrices of the same dimensions, element by element.
+func (m *Matrix[T]) Add(other *Matrix[T]) (*Matrix[T], error) {
+	if m.rows != other.rows || m.cols != other.cols {
+		return nil, fmt.Errorf("%w: %dx%d + %dx%d", ErrDimensionMismatch, m.rows, m.cols, other.rows, other.cols)
+	}
+	res := NewMatrix[T](m.rows, m.cols)
+	for i := range m.data {
+		res.data[i] = m.data[i] + other.data[i]
+	}
+	return res, nil
+}
+func (m *Matrix[T]) Scale(k T) *Matrix[T] {
+	res := NewMatrix[T](m.rows, m.cols)
+	for i, v := range m.data {
+		res.data[i] = k * v
+	}
+	return res
+}
+
+// Transposing
+// The transpose swaps the rows and the columns.
+func (m *Matrix[T]) Transpose() *Matrix[T] {
+	res := NewMatrix[T](m.cols, m.rows)
+	for i := range m.rows {
+		for j, v := range m.Row(i) {
+			res.data[j*m.rows+i] = v
+		}
+	}
+	return res
+}
+
+// Multiplying
+// The product of an (n x m) matrix and an (m x p) matrix is an (n x p) matrix, where each element is the dot
+// product of a row of the first matrix and a column of the second one.
+// The textbook loop order (i, j, k) reads the second matrix column by column, jumping a whole row in memory at
+// each step. The loop order (i, k, j) below reads both matrices row by row, which is much faster for large
+// matrices, since the values are read from the CPU cache (see the benchmarks).
+func (m *Matrix[T]) Mul(other *Matrix[T]) (*Matrix[T], error) {
+	if m.cols != other.rows {
+		return nil, fmt.Errorf("%w: %dx%d * %dx%d", ErrDimensionMismatch, m.rows, m.cols, other.rows, other.cols)
+	}
+	res := NewMatrix[T](m.rows, other.cols)
+	for i := range m.rows {
+		out := res.Row(i)
+		for k, a := range m.Row(i) {
+			for j, b := range other.Row(k) {
+				out[j] += a * b
+			}
+		}
+	}
+	return res, nil
+}
+
+// mulNaive is the textbook loop order, kept for the benchmarks.
+func (m *Matrix[T]) mulNaive(other *Matrix[T]) *Matrix[T] {
+	res := NewMatrix[T](m.rows, other.cols)
+	for i := range m.rows {
+		for j := range other.cols {
+			var sum T
+			for k := range m.cols {
+				sum += m.data[i*m.cols+k] * other.data[k*other.cols+j]
+			}
+			res.data[i*res.cols+j] = sum
+		}
+	}
+	return res
+}
+
+// LU Decomposition
+// The LU decomposition factors a square matrix as P*A = L*U, where P is a row permutation, L is a lower
+// triangular matrix with ones in the diagonal, and U is an upper triangular matrix.
+// It is computed once in O(n^3), and then the determinant is O(n), and each linear system is solved in O(n^2).
+// The rows are swapped to use the largest pivot of each column (partial pivoting), which avoids dividing by
+// small numbers, and keeps the rounding errors small.
+type LU struct {
+	lu       *Matrix[float64] // L below the diagonal, and U in and above the diagonal.
+	pivot    []int            // The row of A at each row of P*A.
+	sign     float64          // The sign of the permutation, 1 or -1.
+	singular bool
+}
+
+func (m *Matrix[T]) LU() (*LU, error) {
+	if m.rows != m.cols {
+		return nil, fmt.Errorf("%w: %dx%d", ErrNotSquare, m.rows, m.cols)
+	}
+	n := m.rows
+	f := &LU{lu: NewMatrix[float64](n, n), pivot: make([]int, n), sign: 1}
+	// colMax keeps the largest value of each column, the scale of the column.
+	colMax := make([]float64, n)
+	for i, v := range m.data {
+		f.lu.data[i] = float64(v)
+		colMax[i%n] = max(colMax[i%n], math.Abs(float64(v)))
+	}
+	a := f.lu
+	for i := range n {
+		f.pivot[i] = i
+	}
+	for k := range n {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(a.At(i, k)) > math.Abs(a.At(p, k)) {
+				p = i
+			}
+		}
+		if p != k {
+			for j := range n {
+				a.data[p*n+j], a.data[k*n+j] = a.data[k*n+j], a.data[p*n+j]
+			}
+			f.pivot[p], f.pivot[k] = f.pivot[k], f.pivot[p]
+			f.sign = -f.sign
+		}
+		// The pivots smaller than the rounding error of their column are considered zero.
+		// The tolerance is relative to the column, not to the whole matrix, so a badly scaled matrix
+		// (e.g., a column of values near 1e-17) is not considered singular.
+		pivot := a.At(k, k)
+		if pivot == 0 || math.Abs(pivot) <= float64(n)*colMax[k]*0x1p-52 {
+			f.singular = true
+			continue
+		}
+		for i := k + 1; i < n; i++ {
+			rowI, rowK := a.Row(i), a.Row(k)
+			rowI[k] /= pivot
+			for j := k + 1; j < n; j++ {
+				rowI[j] -= rowI[k] * rowK[j]
+			}
+		}
+	}
+	return f, nil
+}
+
+// Determinant
+// The determinant of P*A = L*U is the product of the diagonal of U, with the sign of the permutation.
+// It is not rounded to zero for the singular matrices: a pivot that is only rounding error gives a tiny determinant.
+func (f *LU) Det() float64 {
+	det := f.sign
+	for i := range f.lu.rows {
+		det *= f.lu.At(i, i)
+	}
+	return det
+}
+
+// Solving Linear Systems
+// The system A*x = b is solved in two steps: L*y = P*b by forward substitution, and U*x = y by back substitution.
+func (f *LU) Solve(b []float64) ([]float64, error) {
+	n := f.lu.rows
+	if len(b) != n {
+		return nil, fmt.Errorf("%w: %d values for %d equations", ErrDimensionMismatch, len(b), n)
+	}
+	if f.singular {
+		return nil, ErrSingular
+	}
+	x := make([]float64, n)
+	for i := range n {
+		x[i] = b[f.pivot[i]]
+		for j, l := range f.lu.Row(i)[:i] {
+			x[i] -= l * x[j]
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		row := f.lu.Row(i)
+		for j := i + 1; j < n; j++ {
+			x[i] -= row[j] * x[j]
+		}
+		x[i] /= row[i]
+	}
+	return x, nil
+}
+
+// Determinant, Inverse and Solve
+// The methods below use the LU decomposition of the matrix.
+// The inverse solves A*x = e for each column e of the identity matrix.
+func (m *Matrix[T]) Det() (float64, error) {
+	f, err := m.LU()
+	if err != nil {
+		return 0, err
+	}
+	return f.Det(), nil
+}
+func (m *Matrix[T]) Inverse() (*Matrix[float64], error) {
+	f, err := m.LU()
+	if err != nil {
+		return nil, err
+	}
+	n := m.rows
+	inv := NewMatrix[float64](n, n)
+	e := make([]float64, n)
+	for j := range n {
+		clear(e)
+		e[j] = 1
+		col, err := f.Solve(e)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range col {
+			inv.data[i*n+j] = v
+		}
+	}
+	return inv, nil
+}
+func (m *Matrix[T]) Solve(b []T) ([]float64, error) {
+	f, err := m.LU()
+	if err != nil {
+		return nil, err
+	}
+	fb := make([]float64, len(b))
+	for i, v := range b {
+		fb[i] = float64(v)
+	}
+	return f.Solve(fb)
+}
+
+// Printing Matrices
+// The matrix is printed row by row, with the columns aligned to the right.
+func (m *Matrix[T]) String() string {
+	cells := make([]string, len(m.data))
+	widths := make([]int, m.cols)
+	for i, v := range m.data {
+		cells[i] = fmt.Sprint(v)
+		widths[i%max(m.cols, 1)] = max(widths[i%max(m.cols, 1)], len(cells[i]))
+	}
+	var sb strings.Builder
+	for i := range m.rows {
+		sb.WriteByte('[')
+		for j := range m.cols {
+			if j > 0 {
+				sb.WriteByte(' ')
+			}
+			fmt.Fprintf(&sb, "%*s", widths[j], cells[i*m.cols+j])
+		}
+		sb.WriteString("]\n")
+	}
+	return sb.String()
+}
+
+// Using Matrices
+// The example below solves the linear system:
+//
+//	2x + y = 5
+//	x + 3y = 10
+func UsingMatrix() {
+	a, _ := MatrixOf([][]int{
+		{2, 1},
+		{1, 3},
+	})
+	fmt.Print(a) // Output: [2 1], [1 3]
+
+	det, _ := a.Det()
+	fmt.Println(det) // Output: 5
+
+	x, _ := a.Solve([]int{5, 10})
+	fmt.Printf("%.2f\n", x) // Output: [1.00 3.00]
+
+	p, _ := a.Mul(a.Transpose())
+	fmt.Print(p) // Output: [5  5], [5 10]
+}
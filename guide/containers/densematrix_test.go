@@ -0,0 +1,225 @@
+package containers
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+const tolerance = 1e-9
+
+func approxEqual(a, b *Matrix[float64], tol float64) bool {
+	if a.rows != b.rows || a.cols != b.cols {
+		return false
+	}
+	for i := range a.data {
+		if math.Abs(a.data[i]-b.data[i]) > tol {
+			return false
+		}
+	}
+	return true
+}
+
+func mustMatrix[T Numeric](t testing.TB, rows [][]T) *Matrix[T] {
+	t.Helper()
+	m, err := MatrixOf(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func randomMatrix(r *rand.Rand, rows, cols int) *Matrix[float64] {
+	m := NewMatrix[float64](rows, cols)
+	for i := range m.data {
+		m.data[i] = r.Float64()*20 - 10
+	}
+	return m
+}
+
+func TestMatrixBasics(t *testing.T) {
+	a := mustMatrix(t, [][]int{{1, 2, 3}, {4, 5, 6}})
+	if a.Rows() != 2 || a.Cols() != 3 || a.At(1, 2) != 6 {
+		t.Fatalf("matrix = %v", a)
+	}
+	sum, _ := a.Add(a.Scale(10))
+	if want := mustMatrix(t, [][]int{{11, 22, 33}, {44, 55, 66}}); sum.String() != want.String() {
+		t.Errorf("a + 10a = %v", sum)
+	}
+	if got, want := a.Transpose().String(), "[1 4]\n[2 5]\n[3 6]\n"; got != want {
+		t.Errorf("transpose =\n%s", got)
+	}
+	p, _ := a.Mul(a.Transpose())
+	if got, want := p.String(), "[14 32]\n[32 77]\n"; got != want {
+		t.Errorf("a * aT =\n%s", got)
+	}
+	a.Row(0)[0] = 100
+	if a.At(0, 0) != 100 {
+		t.Errorf("Row must share the data")
+	}
+	if _, err := a.Add(a.Transpose()); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Add = %v", err)
+	}
+	if _, err := a.Mul(a); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Mul = %v", err)
+	}
+	if _, err := MatrixOf([][]int{{1}, {1, 2}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("MatrixOf ragged rows = %v", err)
+	}
+	if _, err := a.Det(); !errors.Is(err, ErrNotSquare) {
+		t.Errorf("Det = %v", err)
+	}
+}
+
+func TestMatrixIndexPanics(t *testing.T) {
+	a := NewMatrix[int](2, 2)
+	for name, fn := range map[string]func(){
+		"At(0, 2)":  func() { a.At(0, 2) },
+		"At(2, 0)":  func() { a.At(2, 0) },
+		"Row(-1)":   func() { a.Row(-1) },
+		"Set(0,-1)": func() { a.Set(0, -1, 1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s must panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+func TestDeterminant(t *testing.T) {
+	for _, tc := range []struct {
+		rows [][]float64
+		want float64
+	}{
+		{[][]float64{{4}}, 4},
+		{[][]float64{{1, 2}, {3, 4}}, -2},
+		{[][]float64{{0, 1}, {1, 0}}, -1},
+		{[][]float64{{2, 0, 1}, {1, 3, 2}, {1, 1, 2}}, 6},
+		{[][]float64{{2, 0, 1}, {1, 3, 2}, {1, 1, 1}}, 0},
+		{[][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}, 0},
+		{[][]float64{{0, 0}, {0, 0}}, 0},
+		{[][]float64{}, 1},
+	} {
+		det, err := mustMatrix(t, tc.rows).Det()
+		if err != nil || math.Abs(det-tc.want) > tolerance {
+			t.Errorf("Det(%v) = %v, %v; expected %v", tc.rows, det, err, tc.want)
+		}
+	}
+}
+
+func TestInverseAndSolve(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, n := range []int{1, 2, 5, 20} {
+		a := randomMatrix(r, n, n)
+		inv, err := a.Inverse()
+		if err != nil {
+			t.Fatalf("Inverse(%dx%d) = %v", n, n, err)
+		}
+		p, _ := a.Mul(inv)
+		if !approxEqual(p, Identity[float64](n), tolerance) {
+			t.Errorf("A * inverse(A) is not the identity:\n%v", p)
+		}
+		x := randomMatrix(r, n, 1)
+		b, _ := a.Mul(x)
+		got, err := a.Solve(b.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !approxEqual(&Matrix[float64]{n, 1, got}, x, tolerance) {
+			t.Errorf("Solve = %v; expected %v", got, x.data)
+		}
+		// The determinant of the inverse is the inverse of the determinant.
+		d, _ := a.Det()
+		di, _ := inv.Det()
+		if math.Abs(d*di-1) > tolerance {
+			t.Errorf("det(A) * det(inverse(A)) = %v", d*di)
+		}
+	}
+}
+
+func TestSingular(t *testing.T) {
+	a := mustMatrix(t, [][]int{{1, 2}, {2, 4}})
+	if _, err := a.Inverse(); !errors.Is(err, ErrSingular) {
+		t.Errorf("Inverse = %v", err)
+	}
+	if _, err := a.Solve([]int{1, 2}); !errors.Is(err, ErrSingular) {
+		t.Errorf("Solve = %v", err)
+	}
+	// A tiny pivot caused by rounding errors is treated as zero, but the determinant is not rounded.
+	b := mustMatrix(t, [][]float64{{0.1, 0.2}, {0.3, 0.6}})
+	if det, _ := b.Det(); math.Abs(det) > tolerance {
+		t.Errorf("Det = %v; expected about 0", det)
+	}
+	if _, err := b.Solve([]float64{1, 2}); !errors.Is(err, ErrSingular) {
+		t.Errorf("Solve = %v", err)
+	}
+	f, _ := Identity[int](2).LU()
+	if _, err := f.Solve([]float64{1}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Solve = %v", err)
+	}
+}
+
+// A badly scaled matrix is not singular: the pivots are compared with the scale of their column.
+func TestBadlyScaled(t *testing.T) {
+	a := mustMatrix(t, [][]float64{{1, 0}, {0, 1e-17}})
+	if det, _ := a.Det(); det != 1e-17 {
+		t.Errorf("Det = %v; expected 1e-17", det)
+	}
+	x, err := a.Solve([]float64{2, 3e-17})
+	if err != nil || math.Abs(x[0]-2) > tolerance || math.Abs(x[1]-3) > tolerance {
+		t.Errorf("Solve = %v, %v; expected [2 3]", x, err)
+	}
+	b := mustMatrix(t, [][]float64{{1e-20, 2}, {3e-20, 1}})
+	if det, _ := b.Det(); math.Abs(det+5e-20) > 1e-32 {
+		t.Errorf("Det = %v; expected -5e-20", det)
+	}
+	if _, err := b.Inverse(); err != nil {
+		t.Errorf("Inverse = %v", err)
+	}
+}
+
+func TestMatrixString(t *testing.T) {
+	a := mustMatrix(t, [][]float64{{1.5, -2}, {10, 0.25}})
+	if got, want := a.String(), "[1.5   -2]\n[ 10 0.25]\n"; got != want {
+		t.Errorf("String =\n%s\nexpected\n%s", got, want)
+	}
+	if NewMatrix[int](0, 0).String() != "" {
+		t.Errorf("empty matrix must print nothing")
+	}
+}
+
+// The benchmarks below compare the loop orders of the multiplication.
+// The cache friendly order is faster, and the gap grows with the size of the matrices.
+
+func benchmarkMul(b *testing.B, mul func(x, y *Matrix[float64]) *Matrix[float64]) {
+	r := rand.New(rand.NewPCG(3, 4))
+	x, y := randomMatrix(r, 256, 256), randomMatrix(r, 256, 256)
+	for b.Loop() {
+		mul(x, y)
+	}
+}
+
+func BenchmarkMulCacheFriendly(b *testing.B) {
+	benchmarkMul(b, func(x, y *Matrix[float64]) *Matrix[float64] {
+		p, _ := x.Mul(y)
+		return p
+	})
+}
+
+func BenchmarkMulNaive(b *testing.B) {
+	benchmarkMul(b, (*Matrix[float64]).mulNaive)
+}
+
+func TestMulNaive(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	x, y := randomMatrix(r, 7, 5), randomMatrix(r, 5, 3)
+	p, _ := x.Mul(y)
+	if !approxEqual(p, x.mulNaive(y), tolerance) {
+		t.Errorf("the loop orders must give the same product")
+	}
+}
@@ -8,7 +8,10 @@
 
 package containers
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+)
 
 // Declaring Maps
 // Maps are declared using the "map" keyword followed by the key and value types.
@@ -107,3 +110,50 @@ func ManipulatingMaps() {
 		fmt.Println(v) // Output: 1, 2, 3
 	}
 }
+
+// Concurrent Access
+// Maps are not safe for concurrent use. Reading from several goroutines is fine, but when a goroutine writes
+// while another one reads or writes the same map, the runtime detects it and stops the whole program with:
+//
+//	fatal error: concurrent map writes
+//	fatal error: concurrent map read and map write
+//
+// This is not a panic, so it cannot be handled with "recover()", and it may only happen under load (e.g., a cache
+// shared by the handlers of an HTTP server). The race detector ("go test -race") finds these bugs earlier.
+// To share a map between goroutines, all the accesses must be synchronized:
+// - a map guarded by a "sync.Mutex" (or a "sync.RWMutex", so the reads do not wait for each other);
+// - a "sync.Map", for keys that are written once and read many times;
+// - a sharded map (see "concurrentmap.go"), for frequent writes from many goroutines.
+func ConcurrentAccess() {
+
+	// Guarding a Map with a Mutex
+	// Each goroutine locks the mutex before using the map, so only one of them uses it at a time.
+	// Without the lock, this loop would crash with "fatal error: concurrent map writes".
+	var mu sync.Mutex
+	x := map[int]int{}
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mu.Lock()
+			defer mu.Unlock()
+			x[i%3]++
+		}()
+	}
+	wg.Wait()
+	fmt.Println("x:", x) // Output: x: map[0:4 1:3 2:3]
+
+	// Using a Sharded Map
+	// The concurrent map of this package does the locking internally.
+	y := NewConcurrentMap[int, int](0)
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			y.Compute(i%3, func(n int, _ bool) (int, bool) { return n + 1, true })
+		}()
+	}
+	wg.Wait()
+	fmt.Println("y:", y.Snapshot()) // Output: y: map[0:4 1:3 2:3]
+}
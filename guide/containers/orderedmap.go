@@ -0,0 +1,166 @@
+// Ordered Map
+// The iteration order of Go maps is random, on purpose, so programs do not depend on it.
+// When the order matters (e.g., to print a configuration in the order it was read), an ordered map can be used.
+// The ordered map below keeps the insertion order, with a map of entries that are also linked in a list.
+// So all the operations are O(1), and the iteration follows the list.
+
+package containers
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// Declaring the Ordered Map
+// Each entry is linked to the previous and the next entries, in insertion order.
+// The map finds the entries by key, so the entries can be removed from the list in O(1).
+// The zero value is an empty map ready to use: the map of entries is created by the first Set.
+type OrderedMap[K comparable, V any] struct {
+	entries     map[K]*entry[K, V]
+	first, last *entry[K, V]
+}
+
+type entry[K comparable, V any] struct {
+	key        K
+	value      V
+	prev, next *entry[K, V]
+}
+
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{entries: map[K]*entry[K, V]{}}
+}
+
+// Setting Values
+// A new key is added at the end, and an existing key keeps its position, only its value is updated.
+func (m *OrderedMap[K, V]) Set(key K, value V) {
+	if e, ok := m.entries[key]; ok {
+		e.value = value
+		return
+	}
+	if m.entries == nil {
+		m.entries = map[K]*entry[K, V]{}
+	}
+	e := &entry[K, V]{key: key, value: value, prev: m.last}
+	if m.last == nil {
+		m.first = e
+	} else {
+		m.last.next = e
+	}
+	m.last = e
+	m.entries[key] = e
+}
+
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	if e, ok := m.entries[key]; ok {
+		return e.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (m *OrderedMap[K, V]) Has(key K) bool {
+	_, ok := m.entries[key]
+	return ok
+}
+
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.entries)
+}
+
+// Deleting Values
+// The entry is unlinked from its neighbors, and the method reports whether the key was found.
+func (m *OrderedMap[K, V]) Delete(key K) bool {
+	e, ok := m.entries[key]
+	if !ok {
+		return false
+	}
+	if e.prev == nil {
+		m.first = e.next
+	} else {
+		e.prev.next = e.next
+	}
+	if e.next == nil {
+		m.last = e.prev
+	} else {
+		e.next.prev = e.prev
+	}
+	delete(m.entries, key)
+	return true
+}
+
+// Iterating an Ordered Map
+// The iterators below yield the entries in insertion order, or in reverse order.
+// The next entry is read before yielding, so the current key can be deleted during the iteration.
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := m.first; e != nil; {
+			next := e.next
+			if !yield(e.key, e.value) {
+				return
+			}
+			e = next
+		}
+	}
+}
+func (m *OrderedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := m.last; e != nil; {
+			prev := e.prev
+			if !yield(e.key, e.value) {
+				return
+			}
+			e = prev
+		}
+	}
+}
+func (m *OrderedMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+func (m *OrderedMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range m.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Printing an Ordered Map
+// The map is printed like a builtin map, but in insertion order.
+func (m *OrderedMap[K, V]) String() string {
+	var sb strings.Builder
+	sb.WriteString("map[")
+	for k, v := range m.All() {
+		if sb.Len() > len("map[") {
+			sb.WriteByte(' ')
+		}
+		fmt.Fprintf(&sb, "%v:%v", k, v)
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+// Using Ordered Maps
+// The example below shows that the insertion order is kept, even when values are updated.
+func UsingOrderedMap() {
+	m := NewOrderedMap[string, int]()
+	m.Set("C", 3)
+	m.Set("A", 1)
+	m.Set("B", 2)
+	m.Set("C", 30)
+	fmt.Println(m) // Output: map[C:30 A:1 B:2]
+
+	m.Delete("A")
+	m.Set("A", 10)
+	for k, v := range m.All() {
+		fmt.Println(k, v) // Output: C 30, B 2, A 10
+	}
+}
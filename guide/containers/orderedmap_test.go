@@ -0,0 +1,93 @@
+package containers
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	for i, k := range []string{"c", "a", "b", "a"} {
+		m.Set(k, i)
+	}
+	if got := slices.Collect(m.Keys()); !slices.Equal(got, []string{"c", "a", "b"}) {
+		t.Errorf("Keys = %v", got)
+	}
+	if got := slices.Collect(m.Values()); !slices.Equal(got, []int{0, 3, 2}) {
+		t.Errorf("Values = %v", got)
+	}
+	if v, ok := m.Get("a"); v != 3 || !ok || m.Has("z") {
+		t.Errorf("Get(a) = %v, %v", v, ok)
+	}
+	var backward []string
+	for k := range m.Backward() {
+		backward = append(backward, k)
+	}
+	if !slices.Equal(backward, []string{"b", "a", "c"}) {
+		t.Errorf("Backward = %v", backward)
+	}
+	// The current key can be deleted while iterating.
+	for k := range m.All() {
+		m.Delete(k)
+	}
+	if m.Len() != 0 || m.String() != "map[]" || m.Delete("a") {
+		t.Errorf("map must be empty, got %v", m)
+	}
+	m.Set("x", 1)
+	if m.String() != "map[x:1]" {
+		t.Errorf("String = %v", m)
+	}
+}
+
+func TestOrderedMapZeroValue(t *testing.T) {
+	var m OrderedMap[string, int]
+	if m.Len() != 0 || m.Has("a") || m.Delete("a") {
+		t.Errorf("the zero value must be an empty map")
+	}
+	m.Set("b", 1)
+	m.Set("a", 2)
+	if got := slices.Collect(m.Keys()); !slices.Equal(got, []string{"b", "a"}) {
+		t.Errorf("Keys = %v", got)
+	}
+}
+
+func TestOrderedMapRandomOperations(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	m := NewOrderedMap[int, int]()
+	values := map[int]int{}
+	var order []int
+	for i := range 5000 {
+		k := r.IntN(50)
+		if r.IntN(3) == 0 {
+			_, ok := values[k]
+			if m.Delete(k) != ok {
+				t.Fatalf("Delete(%d) is wrong", k)
+			}
+			delete(values, k)
+			order = slices.DeleteFunc(order, func(x int) bool { return x == k })
+		} else {
+			if _, ok := values[k]; !ok {
+				order = append(order, k)
+			}
+			values[k] = i
+			m.Set(k, i)
+		}
+	}
+	if got := slices.Collect(m.Keys()); !slices.Equal(got, order) {
+		t.Fatalf("Keys = %v; expected %v", got, order)
+	}
+	if got := maps.Collect(m.All()); !maps.Equal(got, values) {
+		t.Fatalf("entries = %v; expected %v", got, values)
+	}
+}
+
+func BenchmarkOrderedMapSet(b *testing.B) {
+	for b.Loop() {
+		m := NewOrderedMap[int, int]()
+		for i := range 1000 {
+			m.Set(i, i)
+		}
+	}
+}
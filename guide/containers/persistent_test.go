@@ -0,0 +1,233 @@
+package containers
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// The tests below keep all the versions with a copy of their expected contents, and check them all at the end,
+// which proves that the updates do not change the old versions.
+
+func TestPersistentVectorVersions(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	type version struct {
+		v    PersistentVector[int]
+		want []int
+	}
+	var versions []version
+	var v PersistentVector[int]
+	var model []int
+	for i := range 5000 {
+		switch op := r.IntN(10); {
+		case op < 6 || len(model) == 0:
+			v = v.Append(i)
+			model = append(model, i)
+		case op < 8:
+			j := r.IntN(len(model))
+			v = v.Set(j, -i)
+			model[j] = -i
+		default:
+			// Pops many elements, so the trie shrinks across levels too.
+			for range r.IntN(min(len(model), 100)) + 1 {
+				v = v.Pop()
+				model = model[:len(model)-1]
+			}
+		}
+		if i%25 == 0 {
+			versions = append(versions, version{v, slices.Clone(model)})
+		}
+	}
+	// Grow over three levels (32 * 32 * 32 elements), and shrink back.
+	big := VectorOf(make([]int, 40000)...)
+	versions = append(versions, version{big.Set(39999, 1), append(make([]int, 39999), 1)})
+	for range 39990 {
+		big = big.Pop()
+	}
+	versions = append(versions, version{big, make([]int, 10)}, version{v, model})
+	for i, ver := range versions {
+		if ver.v.Len() != len(ver.want) {
+			t.Fatalf("version %d: Len = %d; expected %d", i, ver.v.Len(), len(ver.want))
+		}
+		if got := ver.v.ToSlice(); !slices.Equal(got, ver.want) {
+			t.Fatalf("version %d: values changed", i)
+		}
+		for j, x := range ver.want {
+			if ver.v.Get(j) != x {
+				t.Fatalf("version %d: Get(%d) = %d; expected %d", i, j, ver.v.Get(j), x)
+			}
+		}
+	}
+}
+
+func TestPersistentVectorEdges(t *testing.T) {
+	var empty PersistentVector[string]
+	if empty.Len() != 0 || len(empty.ToSlice()) != 0 {
+		t.Errorf("the zero value must be empty")
+	}
+	one := empty.Append("a")
+	if one.Pop().Len() != 0 || one.Get(0) != "a" {
+		t.Errorf("Pop of a single element vector must be empty")
+	}
+	for name, fn := range map[string]func(){
+		"Get(-1)":      func() { one.Get(-1) },
+		"Get(1)":       func() { one.Get(1) },
+		"Set(1)":       func() { one.Set(1, "x") },
+		"Pop on empty": func() { empty.Pop() },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s must panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+	// Appending to an old version must not overwrite the elements of a newer version.
+	base := VectorOf("a", "b")
+	x, y := base.Append("x"), base.Append("y")
+	if x.Get(2) != "x" || y.Get(2) != "y" {
+		t.Errorf("versions share the tail: %v %v", x.ToSlice(), y.ToSlice())
+	}
+	n := 0
+	for range VectorOf(1, 2, 3).All() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("All must stop after break")
+	}
+}
+
+func TestPersistentMapVersions(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	type version struct {
+		m    PersistentMap[int, int]
+		want map[int]int
+	}
+	var versions []version
+	var m PersistentMap[int, int]
+	model := map[int]int{}
+	for i := range 20000 {
+		k := r.IntN(2000)
+		if r.IntN(3) == 0 {
+			m = m.Delete(k)
+			delete(model, k)
+		} else {
+			m = m.Set(k, i)
+			model[k] = i
+		}
+		if i%100 == 0 {
+			versions = append(versions, version{m, maps.Clone(model)})
+		}
+	}
+	versions = append(versions, version{m, model})
+	for i, ver := range versions {
+		if ver.m.Len() != len(ver.want) {
+			t.Fatalf("version %d: Len = %d; expected %d", i, ver.m.Len(), len(ver.want))
+		}
+		if got := maps.Collect(ver.m.All()); !maps.Equal(got, ver.want) {
+			t.Fatalf("version %d: entries changed", i)
+		}
+		for k := range 2000 {
+			v, ok := ver.m.Get(k)
+			if w, wok := ver.want[k]; v != w || ok != wok {
+				t.Fatalf("version %d: Get(%d) = %d, %v", i, k, v, ok)
+			}
+		}
+	}
+	for k := range model {
+		m = m.Delete(k)
+	}
+	if m.Len() != 0 || m.root != nil {
+		t.Errorf("deleting all the keys must empty the trie")
+	}
+}
+
+func TestPersistentMapCollisions(t *testing.T) {
+	// The keys below have the same hash, so they are stored in a collision node.
+	const h = 0xABCDEF
+	var root *hnode[string, int]
+	for i, k := range []string{"a", "b", "c"} {
+		var added bool
+		root, added = hamtSet(root, 0, hslot[string, int]{hash: h, key: k, value: i})
+		if !added {
+			t.Fatalf("%q must be added", k)
+		}
+	}
+	old := root
+	root, added := hamtSet(root, 0, hslot[string, int]{hash: h, key: "b", value: 10})
+	if added {
+		t.Errorf("updating a key must not add it")
+	}
+	m := PersistentMap[string, int]{root: root, len: 3}
+	if got := maps.Collect(m.All()); !maps.Equal(got, map[string]int{"a": 0, "b": 10, "c": 2}) {
+		t.Errorf("entries = %v", got)
+	}
+	if got := maps.Collect(PersistentMap[string, int]{root: old}.All()); got["b"] != 1 {
+		t.Errorf("the old version changed: %v", got)
+	}
+	for _, k := range []string{"a", "c"} {
+		var removed bool
+		if root, removed = hamtDelete(root, 0, h, k); !removed {
+			t.Fatalf("%q must be removed", k)
+		}
+	}
+	if _, removed := hamtDelete(root, 0, h, "z"); removed {
+		t.Errorf("a missing key must not be removed")
+	}
+	// The single remaining entry moves up to the root.
+	if len(root.slots) != 1 || root.slots[0].node != nil || root.slots[0].key != "b" {
+		t.Errorf("the collision node must be collapsed, got %+v", root.slots)
+	}
+}
+
+// The benchmarks below take a snapshot and update one element, with a persistent structure and with a copy.
+
+const snapshotSize = 10000
+
+func BenchmarkSnapshotPersistentVector(b *testing.B) {
+	v := VectorOf(make([]int, snapshotSize)...)
+	i := 0
+	for b.Loop() {
+		v = v.Set(i%snapshotSize, i)
+		i++
+	}
+}
+
+func BenchmarkSnapshotSliceCopy(b *testing.B) {
+	s := make([]int, snapshotSize)
+	i := 0
+	for b.Loop() {
+		s = slices.Clone(s)
+		s[i%snapshotSize] = i
+		i++
+	}
+}
+
+func BenchmarkSnapshotPersistentMap(b *testing.B) {
+	var m PersistentMap[int, int]
+	for i := range snapshotSize {
+		m = m.Set(i, i)
+	}
+	i := 0
+	for b.Loop() {
+		m = m.Set(i%snapshotSize, i)
+		i++
+	}
+}
+
+func BenchmarkSnapshotMapCopy(b *testing.B) {
+	m := map[int]int{}
+	for i := range snapshotSize {
+		m[i] = i
+	}
+	i := 0
+	for b.Loop() {
+		m = maps.Clone(m)
+		m[i%snapshotSize] = i
+		i++
+	}
+}
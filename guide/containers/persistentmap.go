@@ -0,0 +1,255 @@
+// Persistent Map
+// The persistent map below is a Hash Array Mapped Trie (HAMT), the structure used by the immutable maps of
+// Clojure and Scala. Like the persistent vector, each update returns a new version that shares most of its nodes
+// with the old one.
+// The hash of the key is split in groups of 5 bits, and each group selects a child at each level, so the trie
+// has 32 children per node, and a depth of log32(n).
+// To save memory, the nodes do not have 32 slots: a 32-bit bitmap tells which children exist, and the slots
+// only hold the existing children, in order. The slot of a child is the number of bits set before its bit.
+// Example:
+//   bitmap = 0b1010, so the children 1 and 3 exist, in the slots 0 and 1.
+//   The child 3 is at the slot popcount(0b1010 & 0b0111) = 1.
+
+package containers
+
+import (
+	"fmt"
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+// hamtSeed is the seed of the hash function, shared by all the maps, so all the versions agree on the hashes.
+var hamtSeed = maphash.MakeSeed()
+
+// Declaring the Persistent Map
+// The map is a small struct with a pointer to the shared root, so it is passed by value.
+// The zero value is an empty map ready to use.
+type PersistentMap[K comparable, V any] struct {
+	root *hnode[K, V]
+	len  int
+}
+
+// hnode is a trie node. When all the bits of the hash are used, the keys with the same hash are stored in a
+// collision node, which is searched linearly.
+type hnode[K comparable, V any] struct {
+	bitmap    uint32
+	slots     []hslot[K, V]
+	collision bool
+}
+
+// hslot is a child node, or an entry (when node is nil).
+type hslot[K comparable, V any] struct {
+	node  *hnode[K, V]
+	hash  uint64
+	key   K
+	value V
+}
+
+func (m PersistentMap[K, V]) Len() int {
+	return m.len
+}
+
+// index returns the bit of the child at the level, and its slot.
+func (n *hnode[K, V]) index(h uint64, shift uint) (bit uint32, slot int) {
+	bit = 1 << ((h >> shift) & trieMask)
+	return bit, bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+// Getting Values
+// The search follows the children selected by the hash, until it finds an entry.
+func (m PersistentMap[K, V]) Get(key K) (V, bool) {
+	h := maphash.Comparable(hamtSeed, key)
+	for n, shift := m.root, uint(0); n != nil; shift += trieBits {
+		if n.collision {
+			for _, s := range n.slots {
+				if s.key == key {
+					return s.value, true
+				}
+			}
+			break
+		}
+		bit, i := n.index(h, shift)
+		if n.bitmap&bit == 0 {
+			break
+		}
+		s := n.slots[i]
+		if s.node == nil {
+			if s.hash == h && s.key == key {
+				return s.value, true
+			}
+			break
+		}
+		n = s.node
+	}
+	var zero V
+	return zero, false
+}
+
+func (m PersistentMap[K, V]) Has(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// Setting Values
+// The path from the root to the entry is copied. When the slot already has another entry, both entries are moved
+// to a new child node, using the next 5 bits of their hashes.
+func (m PersistentMap[K, V]) Set(key K, value V) PersistentMap[K, V] {
+	root, added := hamtSet(m.root, 0, hslot[K, V]{hash: maphash.Comparable(hamtSeed, key), key: key, value: value})
+	m.root = root
+	if added {
+		m.len++
+	}
+	return m
+}
+
+// hamtSet returns a copy of the node with the entry, and reports whether the key was added.
+func hamtSet[K comparable, V any](n *hnode[K, V], shift uint, e hslot[K, V]) (*hnode[K, V], bool) {
+	if n == nil {
+		n = &hnode[K, V]{}
+	}
+	c := &hnode[K, V]{bitmap: n.bitmap, slots: slices.Clone(n.slots), collision: n.collision}
+	if n.collision {
+		for i, s := range c.slots {
+			if s.key == e.key {
+				c.slots[i] = e
+				return c, false
+			}
+		}
+		c.slots = append(c.slots, e)
+		return c, true
+	}
+	bit, i := n.index(e.hash, shift)
+	if n.bitmap&bit == 0 {
+		c.bitmap |= bit
+		c.slots = slices.Insert(c.slots, i, e)
+		return c, true
+	}
+	s := c.slots[i]
+	switch {
+	case s.node != nil:
+		child, added := hamtSet(s.node, shift+trieBits, e)
+		c.slots[i] = hslot[K, V]{node: child}
+		return c, added
+	case s.hash == e.hash && s.key == e.key:
+		c.slots[i] = e
+		return c, false
+	}
+	c.slots[i] = hslot[K, V]{node: hamtMerge(shift+trieBits, s, e)}
+	return c, true
+}
+
+// hamtMerge returns a node with the two entries, nesting nodes while the bits of their hashes are the same.
+func hamtMerge[K comparable, V any](shift uint, a, b hslot[K, V]) *hnode[K, V] {
+	if shift >= 64 {
+		return &hnode[K, V]{slots: []hslot[K, V]{a, b}, collision: true}
+	}
+	ia, ib := (a.hash>>shift)&trieMask, (b.hash>>shift)&trieMask
+	if ia == ib {
+		return &hnode[K, V]{bitmap: 1 << ia, slots: []hslot[K, V]{{node: hamtMerge(shift+trieBits, a, b)}}}
+	}
+	if ia > ib {
+		a, b = b, a
+	}
+	return &hnode[K, V]{bitmap: 1<<ia | 1<<ib, slots: []hslot[K, V]{a, b}}
+}
+
+// Deleting Values
+// The path to the entry is copied without it. The nodes left with a single entry are replaced by the entry,
+// so the trie stays as short as possible.
+func (m PersistentMap[K, V]) Delete(key K) PersistentMap[K, V] {
+	root, removed := hamtDelete(m.root, 0, maphash.Comparable(hamtSeed, key), key)
+	if removed {
+		m.root = root
+		m.len--
+	}
+	return m
+}
+
+// hamtDelete returns a copy of the node without the key (or nil if it becomes empty), and reports whether the key
+// was found. The node is returned unchanged when the key is not found.
+func hamtDelete[K comparable, V any](n *hnode[K, V], shift uint, h uint64, key K) (*hnode[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+	if n.collision {
+		i := slices.IndexFunc(n.slots, func(s hslot[K, V]) bool { return s.key == key })
+		if i < 0 {
+			return n, false
+		}
+		return &hnode[K, V]{slots: slices.Delete(slices.Clone(n.slots), i, i+1), collision: true}, true
+	}
+	bit, i := n.index(h, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	s := n.slots[i]
+	var replacement *hslot[K, V]
+	if s.node == nil {
+		if s.hash != h || s.key != key {
+			return n, false
+		}
+	} else {
+		child, removed := hamtDelete(s.node, shift+trieBits, h, key)
+		if !removed {
+			return n, false
+		}
+		switch {
+		case child == nil:
+		case len(child.slots) == 1 && child.slots[0].node == nil:
+			replacement = &child.slots[0]
+		default:
+			replacement = &hslot[K, V]{node: child}
+		}
+	}
+	c := &hnode[K, V]{bitmap: n.bitmap, slots: slices.Clone(n.slots)}
+	if replacement != nil {
+		c.slots[i] = *replacement
+		return c, true
+	}
+	c.bitmap &^= bit
+	c.slots = slices.Delete(c.slots, i, i+1)
+	if len(c.slots) == 0 {
+		return nil, true
+	}
+	return c, true
+}
+
+// Iterating Persistent Maps
+// The iterator below yields the entries in the order of their hashes, which is random, like the builtin maps.
+func (m PersistentMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		hamtAll(m.root, yield)
+	}
+}
+func hamtAll[K comparable, V any](n *hnode[K, V], yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	for _, s := range n.slots {
+		if s.node != nil {
+			if !hamtAll(s.node, yield) {
+				return false
+			}
+		} else if !yield(s.key, s.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Using Persistent Maps
+// The example below keeps a snapshot of a configuration before changing it.
+// The snapshot is a copy of the struct, so it is O(1), and it does not change with the new versions.
+func UsingPersistentMap() {
+	var config PersistentMap[string, int]
+	config = config.Set("port", 8080).Set("workers", 4)
+	snapshot := config
+	config = config.Set("port", 9090).Delete("workers")
+
+	port, _ := snapshot.Get("port")
+	fmt.Println(port, snapshot.Len()) // Output: 8080 2
+	port, _ = config.Get("port")
+	fmt.Println(port, config.Len()) // Output: 9090 1
+}
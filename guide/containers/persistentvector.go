@@ -0,0 +1,233 @@
+// Persistent Vector
+// A persistent data structure never changes: each update returns a new version, and the old versions stay valid.
+// Copying the whole structure at each update would be O(n), so the new version shares most of its memory with the
+// old one (structural sharing), and only copies the path to the changed element.
+// This makes snapshots free (e.g., for undo history, or for the GoF Memento pattern), and the versions can be
+// read by many goroutines without locks, since they are immutable.
+// The vector below is a 32-way trie: the elements are in the leaves, in groups of 32, and each internal node has up
+// to 32 children. The depth is log32(n), so a vector with a million elements has only 4 levels, and each update
+// copies 4 small nodes. The last elements are kept in a separate tail, so most appends only copy the tail.
+
+package containers
+
+import (
+	"fmt"
+	"iter"
+)
+
+const (
+	trieBits  = 5
+	trieWidth = 1 << trieBits
+	trieMask  = trieWidth - 1
+)
+
+// Declaring the Persistent Vector
+// The vector is a small struct with pointers to the shared nodes, so it is passed by value.
+// The zero value is an empty vector ready to use.
+type PersistentVector[T any] struct {
+	len   int
+	shift uint      // The number of bits used by the levels below the root.
+	root  *vnode[T] // The trie with the first elements, in groups of 32.
+	tail  []T       // The last 1 to 32 elements. Its capacity is its length, so appends always copy it.
+}
+
+// vnode is an internal node (children) or a leaf (values). The nodes are never changed after they are shared.
+type vnode[T any] struct {
+	children []*vnode[T]
+	values   []T
+}
+
+// Creating Vectors
+// The function below creates a vector with the values.
+func VectorOf[T any](values ...T) PersistentVector[T] {
+	var v PersistentVector[T]
+	for _, x := range values {
+		v = v.Append(x)
+	}
+	return v
+}
+
+func (v PersistentVector[T]) Len() int {
+	return v.len
+}
+
+// tailOffset returns the index of the first element of the tail.
+func (v PersistentVector[T]) tailOffset() int {
+	return v.len - len(v.tail)
+}
+
+// leaf returns the group of 32 values with the index, from the trie or from the tail.
+func (v PersistentVector[T]) leaf(i int) []T {
+	if i >= v.tailOffset() {
+		return v.tail
+	}
+	n := v.root
+	for level := v.shift; level > 0; level -= trieBits {
+		n = n.children[(i>>level)&trieMask]
+	}
+	return n.values
+}
+
+// Getting Elements
+// The index is split in groups of 5 bits, and each group selects the child at each level.
+func (v PersistentVector[T]) Get(i int) T {
+	v.check(i)
+	return v.leaf(i)[i&trieMask]
+}
+func (v PersistentVector[T]) check(i int) {
+	if i < 0 || i >= v.len {
+		panic(fmt.Sprintf("vector: index %d out of range [0:%d]", i, v.len))
+	}
+}
+
+// Appending Elements
+// When the tail is full, it is moved to the trie as a new leaf, and the path to the leaf is copied.
+// When the trie is full, a new root is added on top of it, with the old root as its first child.
+func (v PersistentVector[T]) Append(x T) PersistentVector[T] {
+	if len(v.tail) < trieWidth {
+		v.tail = append(v.tail[:len(v.tail):len(v.tail)], x)
+		v.len++
+		return v
+	}
+	leaf := &vnode[T]{values: v.tail}
+	switch {
+	case v.root == nil:
+		v.root, v.shift = &vnode[T]{children: []*vnode[T]{leaf}}, trieBits
+	case v.len>>trieBits > 1<<v.shift:
+		v.root = &vnode[T]{children: []*vnode[T]{v.root, newPath(v.shift, leaf)}}
+		v.shift += trieBits
+	default:
+		v.root = pushLeaf(v.shift, v.root, v.len-1, leaf)
+	}
+	v.tail = []T{x}
+	v.len++
+	return v
+}
+
+// newPath returns the chain of nodes from the level down to the leaf.
+func newPath[T any](level uint, leaf *vnode[T]) *vnode[T] {
+	if level == 0 {
+		return leaf
+	}
+	return &vnode[T]{children: []*vnode[T]{newPath(level-trieBits, leaf)}}
+}
+
+// pushLeaf returns a copy of the node with the leaf added at the index, creating the missing nodes.
+func pushLeaf[T any](level uint, n *vnode[T], i int, leaf *vnode[T]) *vnode[T] {
+	sub := (i >> level) & trieMask
+	children := append([]*vnode[T](nil), n.children...)
+	var child *vnode[T]
+	switch {
+	case level == trieBits:
+		child = leaf
+	case sub < len(children):
+		child = pushLeaf(level-trieBits, children[sub], i, leaf)
+	default:
+		child = newPath(level-trieBits, leaf)
+	}
+	if sub < len(children) {
+		children[sub] = child
+	} else {
+		children = append(children, child)
+	}
+	return &vnode[T]{children: children}
+}
+
+// Setting Elements
+// The path from the root to the leaf is copied, and all the other nodes are shared.
+func (v PersistentVector[T]) Set(i int, x T) PersistentVector[T] {
+	v.check(i)
+	if i >= v.tailOffset() {
+		v.tail = append([]T(nil), v.tail...)
+		v.tail[i-v.tailOffset()] = x
+		return v
+	}
+	v.root = setPath(v.shift, v.root, i, x)
+	return v
+}
+func setPath[T any](level uint, n *vnode[T], i int, x T) *vnode[T] {
+	if level == 0 {
+		values := append([]T(nil), n.values...)
+		values[i&trieMask] = x
+		return &vnode[T]{values: values}
+	}
+	children := append([]*vnode[T](nil), n.children...)
+	sub := (i >> level) & trieMask
+	children[sub] = setPath(level-trieBits, children[sub], i, x)
+	return &vnode[T]{children: children}
+}
+
+// Removing the Last Element
+// When the tail becomes empty, the last leaf of the trie becomes the tail, and the root is removed when it has
+// a single child.
+func (v PersistentVector[T]) Pop() PersistentVector[T] {
+	switch {
+	case v.len == 0:
+		panic("vector: Pop on an empty vector")
+	case v.len == 1:
+		return PersistentVector[T]{}
+	case len(v.tail) > 1:
+		v.tail = v.tail[: len(v.tail)-1 : len(v.tail)-1]
+		v.len--
+		return v
+	}
+	v.tail = v.leaf(v.len - 2)
+	v.root = popLeaf(v.shift, v.root, v.len-2)
+	switch {
+	case v.root == nil:
+		v.shift = 0
+	case v.shift > trieBits && len(v.root.children) == 1:
+		v.root = v.root.children[0]
+		v.shift -= trieBits
+	}
+	v.len--
+	return v
+}
+
+// popLeaf returns a copy of the node without the leaf with the index, or nil if the node becomes empty.
+func popLeaf[T any](level uint, n *vnode[T], i int) *vnode[T] {
+	sub := (i >> level) & trieMask
+	var child *vnode[T]
+	if level > trieBits {
+		child = popLeaf(level-trieBits, n.children[sub], i)
+	}
+	if child == nil && sub == 0 {
+		return nil
+	}
+	children := append([]*vnode[T](nil), n.children[:sub]...)
+	if child != nil {
+		children = append(children, child)
+	}
+	return &vnode[T]{children: children}
+}
+
+// Iterating Vectors
+// The iterator below yields the indexes and the elements, reading each leaf once.
+func (v PersistentVector[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; i < v.len; i += trieWidth {
+			for j, x := range v.leaf(i) {
+				if !yield(i+j, x) {
+					return
+				}
+			}
+		}
+	}
+}
+func (v PersistentVector[T]) ToSlice() []T {
+	s := make([]T, 0, v.len)
+	for _, x := range v.All() {
+		s = append(s, x)
+	}
+	return s
+}
+
+// Using Persistent Vectors
+// The example below keeps the versions of a document, each version shares its elements with the previous one.
+func UsingPersistentVector() {
+	v1 := VectorOf("a", "b", "c")
+	v2 := v1.Set(1, "B")
+	v3 := v2.Append("d")
+	fmt.Println(v1.ToSlice(), v2.ToSlice(), v3.ToSlice()) // Output: [a b c] [a B c] [a B c d]
+	fmt.Println(v3.Pop().Len(), v3.Len())                 // Output: 3 4
+}
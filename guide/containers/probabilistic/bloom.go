@@ -0,0 +1,146 @@
+// Bloom Filter
+// A Bloom filter is a set that only answers "maybe present" or "certainly absent", using a few bits per item.
+// It is an array of m bits and k hash functions. Adding an item sets the k bits of its hashes, and an item is
+// maybe present when its k bits are all set. An absent item is reported as present (a false positive) only when
+// its k bits were all set by other items.
+// For n items and a false-positive rate p, the best sizes are:
+//   m = -n*ln(p) / ln(2)^2 bits (about 9.6 bits per item for p = 1%)
+//   k = m/n * ln(2) hashes (about 7 for p = 1%)
+// The items cannot be removed, since their bits may be shared with other items.
+// Bloom filters are used to avoid expensive lookups of absent keys (e.g., databases check them before reading
+// a file from disk).
+
+package probabilistic
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// Declaring the Bloom Filter
+// The bits are stored in 64-bit words. The type parameter only ensures that the same type is added and checked.
+type Bloom[T any] struct {
+	words []uint64
+	m     uint64
+	k     uint64
+}
+
+// Creating Bloom Filters
+// The function below creates a filter for n items with the false-positive rate p (e.g., 0.01 for 1%).
+// Adding more than n items increases the rate.
+func NewBloom[T any](n int, p float64) *Bloom[T] {
+	if n <= 0 || p <= 0 || p >= 1 {
+		panic(fmt.Sprintf("bloom: invalid parameters n=%d p=%v", n, p))
+	}
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := max(1, math.Round(m/float64(n)*math.Ln2))
+	return newBloom[T](uint64(m), uint64(k))
+}
+
+func newBloom[T any](m, k uint64) *Bloom[T] {
+	return &Bloom[T]{words: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+// Bits returns the number of bits m, and Hashes the number of hashes k.
+func (b *Bloom[T]) Bits() uint64 {
+	return b.m
+}
+func (b *Bloom[T]) Hashes() uint64 {
+	return b.k
+}
+
+// Adding and Checking Items
+// Add sets the k bits of the item, and Contains checks them. Contains returns false only when the item was
+// never added.
+func (b *Bloom[T]) Add(v T) {
+	h1, h2 := hashes(Hash(v))
+	for i := range b.k {
+		bit := (h1 + i*h2) % b.m
+		b.words[bit/64] |= 1 << (bit % 64)
+	}
+}
+func (b *Bloom[T]) Contains(v T) bool {
+	h1, h2 := hashes(Hash(v))
+	for i := range b.k {
+		bit := (h1 + i*h2) % b.m
+		if b.words[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Estimates
+// The filter does not store the items, but the number of set bits X gives estimates of:
+// - the number of distinct items added: n = -m/k * ln(1 - X/m);
+// - the current false-positive rate: p = (X/m)^k, the probability that k random bits are set.
+func (b *Bloom[T]) setBits() uint64 {
+	var x int
+	for _, w := range b.words {
+		x += bits.OnesCount64(w)
+	}
+	return uint64(x)
+}
+func (b *Bloom[T]) EstimateCount() float64 {
+	x := float64(b.setBits())
+	m, k := float64(b.m), float64(b.k)
+	if x == m {
+		return math.Inf(1)
+	}
+	return -m / k * math.Log(1-x/m)
+}
+func (b *Bloom[T]) FalsePositiveRate() float64 {
+	return math.Pow(float64(b.setBits())/float64(b.m), float64(b.k))
+}
+
+// Merging
+// The union of two filters with the same sizes is the OR of their bits: it contains the items of both.
+func (b *Bloom[T]) Merge(other *Bloom[T]) error {
+	if b.m != other.m || b.k != other.k {
+		return fmt.Errorf("%w: bloom m=%d k=%d and m=%d k=%d", ErrIncompatible, b.m, b.k, other.m, other.k)
+	}
+	for i, w := range other.words {
+		b.words[i] |= w
+	}
+	return nil
+}
+
+// Maximum Hashes
+// The filters created by NewBloom have less than 1024 hashes, since p cannot be smaller than 2^-1024.
+// UnmarshalBinary rejects the larger values, so crafted data cannot make each Add and Contains loop forever.
+const maxHashes = 1024
+
+// Serialization
+// The filter is saved as the header, m, k and the words. MarshalBinary and UnmarshalBinary implement the
+// "encoding.BinaryMarshaler" and "encoding.BinaryUnmarshaler" interfaces, and UnmarshalBinary works on the zero value.
+func (b *Bloom[T]) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 2+16+8*len(b.words))
+	data = appendHeader(data, magicBloom)
+	data = binary.BigEndian.AppendUint64(data, b.m)
+	data = binary.BigEndian.AppendUint64(data, b.k)
+	for _, w := range b.words {
+		data = binary.BigEndian.AppendUint64(data, w)
+	}
+	return data, nil
+}
+func (b *Bloom[T]) UnmarshalBinary(data []byte) error {
+	r := newReader(data, magicBloom)
+	m, k := r.uint64(), r.uint64()
+	// The sizes are checked without multiplying them, since crafted values (e.g., m near 2^64) would overflow,
+	// and pass the check with no words at all.
+	n := uint64(len(r.data) / 8)
+	if r.err == nil && (m == 0 || k == 0 || k > maxHashes || len(r.data)%8 != 0 || (m-1)/64+1 != n) {
+		return fmt.Errorf("%w: bloom m=%d k=%d with %d bytes", ErrInvalidData, m, k, len(r.data))
+	}
+	words := make([]uint64, 0, len(r.data)/8)
+	for r.err == nil && len(r.data) > 0 {
+		words = append(words, r.uint64())
+	}
+	if err := r.done(); err != nil {
+		return err
+	}
+	*b = Bloom[T]{words: words, m: m, k: k}
+	return nil
+}
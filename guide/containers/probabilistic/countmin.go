@@ -0,0 +1,118 @@
+// Count-Min Sketch
+// A count-min sketch counts the items of a stream, using a fixed table of d rows and w counters.
+// Adding an item increments one counter per row, chosen by a different hash for each row. The count of an item is
+// the minimum of its d counters: each counter may include the counts of other items with the same hash
+// (so the count is never too low), and the minimum is the counter with the fewest collisions.
+// For a stream of N items, with w = e/ε and d = ln(1/δ), the count exceeds the true count by more than ε*N
+// with a probability of at most δ.
+// Example: ε = 0.001 and δ = 0.01 take 2719 x 5 counters, whatever the number of distinct items.
+// Count-min sketches are used to find the frequent items of a stream (e.g., the most requested URLs).
+
+package probabilistic
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Declaring the Count-Min Sketch
+// The counters are stored row by row in a single slice, and total is the number of items added (N).
+type CountMin[T any] struct {
+	counters []uint64
+	width    uint64
+	depth    uint64
+	total    uint64
+}
+
+// Creating Count-Min Sketches
+// The function below creates a sketch with the error ε (epsilon) relative to the total, and the probability δ
+// (delta) that the error is exceeded.
+func NewCountMin[T any](epsilon, delta float64) *CountMin[T] {
+	if epsilon <= 0 || epsilon >= 1 || delta <= 0 || delta >= 1 {
+		panic(fmt.Sprintf("countmin: invalid parameters epsilon=%v delta=%v", epsilon, delta))
+	}
+	w := math.Ceil(math.E / epsilon)
+	d := math.Ceil(math.Log(1 / delta))
+	return newCountMin[T](uint64(w), uint64(d))
+}
+
+func newCountMin[T any](width, depth uint64) *CountMin[T] {
+	return &CountMin[T]{counters: make([]uint64, width*depth), width: width, depth: depth}
+}
+
+// Width returns the number of counters per row, and Depth the number of rows.
+func (s *CountMin[T]) Width() uint64 {
+	return s.width
+}
+func (s *CountMin[T]) Depth() uint64 {
+	return s.depth
+}
+
+// Total returns the number of items added, which bounds the error: Count(v) <= true count + ε*Total.
+func (s *CountMin[T]) Total() uint64 {
+	return s.total
+}
+
+// Counting Items
+// Add adds n occurrences of the item, and Count returns the estimated number of occurrences.
+func (s *CountMin[T]) Add(v T, n uint64) {
+	h1, h2 := hashes(Hash(v))
+	for i := range s.depth {
+		s.counters[i*s.width+(h1+i*h2)%s.width] += n
+	}
+	s.total += n
+}
+func (s *CountMin[T]) Count(v T) uint64 {
+	h1, h2 := hashes(Hash(v))
+	count := uint64(math.MaxUint64)
+	for i := range s.depth {
+		count = min(count, s.counters[i*s.width+(h1+i*h2)%s.width])
+	}
+	return count
+}
+
+// Merging
+// The sketch of two streams is the sum of their counters, when they have the same sizes.
+func (s *CountMin[T]) Merge(other *CountMin[T]) error {
+	if s.width != other.width || s.depth != other.depth {
+		return fmt.Errorf("%w: countmin %dx%d and %dx%d", ErrIncompatible, s.width, s.depth, other.width, other.depth)
+	}
+	for i, c := range other.counters {
+		s.counters[i] += c
+	}
+	s.total += other.total
+	return nil
+}
+
+// Serialization
+// The sketch is saved as the header, the width, the depth, the total and the counters.
+func (s *CountMin[T]) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 2+24+8*len(s.counters))
+	data = appendHeader(data, magicCountMin)
+	data = binary.BigEndian.AppendUint64(data, s.width)
+	data = binary.BigEndian.AppendUint64(data, s.depth)
+	data = binary.BigEndian.AppendUint64(data, s.total)
+	for _, c := range s.counters {
+		data = binary.BigEndian.AppendUint64(data, c)
+	}
+	return data, nil
+}
+func (s *CountMin[T]) UnmarshalBinary(data []byte) error {
+	r := newReader(data, magicCountMin)
+	width, depth, total := r.uint64(), r.uint64(), r.uint64()
+	// The number of counters is divided instead of multiplying width*depth, which can overflow.
+	n := uint64(len(r.data) / 8)
+	if r.err == nil && (width == 0 || depth == 0 || len(r.data)%8 != 0 || n%width != 0 || n/width != depth) {
+		return fmt.Errorf("%w: countmin %dx%d with %d bytes", ErrInvalidData, width, depth, len(r.data))
+	}
+	counters := make([]uint64, 0, len(r.data)/8)
+	for r.err == nil && len(r.data) > 0 {
+		counters = append(counters, r.uint64())
+	}
+	if err := r.done(); err != nil {
+		return err
+	}
+	*s = CountMin[T]{counters: counters, width: width, depth: depth, total: total}
+	return nil
+}
@@ -0,0 +1,177 @@
+// Probabilistic Data Structures
+// The exact collections of the "containers" package grow with the number of items, which is a problem for large
+// streams (e.g., the events of a busy service). The probabilistic structures below use a fixed amount of memory,
+// chosen up front from the expected error, and answer with an approximation:
+// - a Bloom filter tells whether an item was seen (with some false positives, but no false negatives);
+// - a count-min sketch tells how many times an item was seen (with some overcounting, but no undercounting);
+// - a HyperLogLog tells how many distinct items were seen (within a few percent).
+// All three only keep the hashes of the items, so they can be merged (e.g., the sketches of several servers),
+// and saved as bytes (e.g., to a file or a database).
+//
+// Deterministic Hashing
+// The merged and saved structures must hash the items in the same way in every process, so the hash below is
+// deterministic, unlike "hash/maphash", which uses a random seed per process.
+// The bytes are hashed with FNV-1a, and the result is mixed with the finalizer of SplitMix64, which spreads the
+// small differences between the inputs (e.g., "a1" and "a2") over all the bits.
+
+package probabilistic
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Errors
+// ErrIncompatible is returned when merging structures of different sizes, and ErrInvalidData when the bytes are
+// not a valid serialized structure.
+var (
+	ErrIncompatible = errors.New("probabilistic: incompatible structures")
+	ErrInvalidData  = errors.New("probabilistic: invalid data")
+)
+
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+// Hash returns the deterministic 64-bit hash of the value.
+// The strings, byte slices, integers, floats and booleans are hashed by value. The other types are hashed by
+// their "%v" format, so two values must only have the same format when they are equal (the pointers are
+// formatted as addresses, so they are not deterministic across processes).
+func Hash[T any](v T) uint64 {
+	switch v := any(v).(type) {
+	case string:
+		return hashBytes(v)
+	case []byte:
+		return hashBytes(v)
+	case int:
+		return mix64(uint64(v))
+	case int8:
+		return mix64(uint64(v))
+	case int16:
+		return mix64(uint64(v))
+	case int32:
+		return mix64(uint64(v))
+	case int64:
+		return mix64(uint64(v))
+	case uint:
+		return mix64(uint64(v))
+	case uint8:
+		return mix64(uint64(v))
+	case uint16:
+		return mix64(uint64(v))
+	case uint32:
+		return mix64(uint64(v))
+	case uint64:
+		return mix64(v)
+	case float32:
+		return mix64(math.Float64bits(float64(v)))
+	case float64:
+		return mix64(math.Float64bits(v))
+	case bool:
+		if v {
+			return mix64(1)
+		}
+		return mix64(0)
+	default:
+		return hashBytes(fmt.Appendf(nil, "%v", v))
+	}
+}
+
+func hashBytes[B ~string | ~[]byte](b B) uint64 {
+	h := uint64(fnvOffset)
+	for i := 0; i < len(b); i++ {
+		h ^= uint64(b[i])
+		h *= fnvPrime
+	}
+	return mix64(h)
+}
+
+// mix64 is the finalizer of SplitMix64.
+func mix64(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// Double Hashing
+// The Bloom filter and the count-min sketch need several hashes per item. Instead of several hash functions,
+// the k hashes are computed from two: g(i) = h1 + i*h2, which has the same error rates (Kirsch and Mitzenmacher).
+// The two hashes are the halves of the 64-bit hash, and h2 is odd, so it is never 0.
+func hashes(h uint64) (h1, h2 uint64) {
+	return h & math.MaxUint32, h>>32 | 1
+}
+
+// Serialization
+// Each structure is saved as a header and its counters, in big-endian order:
+//
+//	magic (1 byte) | version (1 byte) | parameters | counters
+//
+// The magic byte identifies the structure, so a Bloom filter cannot be loaded as a sketch, and the version allows
+// changing the format later.
+const version = 1
+
+const (
+	magicBloom       byte = 'B'
+	magicCountMin    byte = 'C'
+	magicHyperLogLog byte = 'H'
+)
+
+func appendHeader(b []byte, magic byte) []byte {
+	return append(b, magic, version)
+}
+
+// reader reads the fields of a serialized structure, and keeps the first error.
+type reader struct {
+	data []byte
+	err  error
+}
+
+func newReader(data []byte, magic byte) *reader {
+	r := &reader{data: data}
+	if len(data) < 2 || data[0] != magic || data[1] != version {
+		r.err = fmt.Errorf("%w: bad header", ErrInvalidData)
+		return r
+	}
+	r.data = data[2:]
+	return r
+}
+
+func (r *reader) uint64() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.data) < 8 {
+		r.err = fmt.Errorf("%w: unexpected end", ErrInvalidData)
+		return 0
+	}
+	v := binary.BigEndian.Uint64(r.data)
+	r.data = r.data[8:]
+	return v
+}
+
+func (r *reader) bytes(n uint64) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if uint64(len(r.data)) < n {
+		r.err = fmt.Errorf("%w: unexpected end", ErrInvalidData)
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+// done returns the first error, or an error if some bytes were not read.
+func (r *reader) done() error {
+	if r.err == nil && len(r.data) > 0 {
+		r.err = fmt.Errorf("%w: %d extra bytes", ErrInvalidData, len(r.data))
+	}
+	return r.err
+}
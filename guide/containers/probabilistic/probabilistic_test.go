@@ -0,0 +1,289 @@
+package probabilistic
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	// The hashes must not change between versions, since they are saved with the structures.
+	if Hash("hello") != 0x16fe05a1c75bcd0f || Hash(42) != 0xa759ea27d4727622 {
+		t.Errorf("Hash(hello) = %#x, Hash(42) = %#x; the hashes changed", Hash("hello"), Hash(42))
+	}
+	if got := Hash("hello"); got != Hash([]byte("hello")) {
+		t.Errorf("strings and byte slices must have the same hash")
+	}
+	if Hash(1) == Hash(2) || Hash("a1") == Hash("a2") || Hash(1.5) == Hash(2.5) {
+		t.Errorf("different values must have different hashes")
+	}
+	type point struct{ X, Y int }
+	if Hash(point{1, 2}) != Hash(point{1, 2}) || Hash(point{1, 2}) == Hash(point{2, 1}) {
+		t.Errorf("structs must be hashed by value")
+	}
+	// The bits of the hashes of consecutive integers must be set about half of the time.
+	var ones [64]int
+	for i := range 10000 {
+		h := Hash(i)
+		for b := range 64 {
+			ones[b] += int(h >> b & 1)
+		}
+	}
+	for b, n := range ones {
+		if n < 4700 || n > 5300 {
+			t.Errorf("bit %d is set %d times in 10000 hashes", b, n)
+		}
+	}
+}
+
+func TestBloom(t *testing.T) {
+	const n = 10000
+	for _, p := range []float64{0.1, 0.01, 0.001} {
+		b := NewBloom[string](n, p)
+		for i := range n {
+			b.Add(fmt.Sprint("in-", i))
+		}
+		for i := range n {
+			if !b.Contains(fmt.Sprint("in-", i)) {
+				t.Fatalf("p=%v: false negative for in-%d", p, i)
+			}
+		}
+		fp := 0
+		const queries = 100000
+		for i := range queries {
+			if b.Contains(fmt.Sprint("out-", i)) {
+				fp++
+			}
+		}
+		// The observed rate may exceed p a little, by chance.
+		if rate := float64(fp) / queries; rate > p*1.25 {
+			t.Errorf("p=%v: false-positive rate %v (m=%d k=%d)", p, rate, b.Bits(), b.Hashes())
+		}
+		if est := b.FalsePositiveRate(); math.Abs(est-p)/p > 0.25 {
+			t.Errorf("p=%v: estimated false-positive rate %v", p, est)
+		}
+		if est := b.EstimateCount(); math.Abs(est-n)/n > 0.02 {
+			t.Errorf("p=%v: estimated count %v", p, est)
+		}
+	}
+}
+
+func TestBloomMergeAndMarshal(t *testing.T) {
+	a, b := NewBloom[int](1000, 0.01), NewBloom[int](1000, 0.01)
+	for i := range 500 {
+		a.Add(i)
+		b.Add(i + 500)
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	for i := range 1000 {
+		if !a.Contains(i) {
+			t.Fatalf("merged filter must contain %d", i)
+		}
+	}
+	if err := a.Merge(NewBloom[int](10, 0.01)); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("Merge of different sizes = %v", err)
+	}
+
+	data, _ := a.MarshalBinary()
+	var c Bloom[int]
+	if err := c.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if c.Bits() != a.Bits() || c.Hashes() != a.Hashes() || c.EstimateCount() != a.EstimateCount() || !c.Contains(999) {
+		t.Errorf("UnmarshalBinary must restore the filter")
+	}
+	for _, bad := range [][]byte{nil, data[:len(data)-1], append(data, 0), {'C', version}} {
+		if err := c.UnmarshalBinary(bad); !errors.Is(err, ErrInvalidData) {
+			t.Errorf("UnmarshalBinary(%d bytes) = %v", len(bad), err)
+		}
+	}
+	// Crafted sizes must not overflow the size checks.
+	for _, mk := range [][2]uint64{{math.MaxUint64, 1}, {math.MaxUint64 - 62, 1}, {64, math.MaxUint64}, {64, maxHashes + 1}} {
+		bad := binary.BigEndian.AppendUint64(binary.BigEndian.AppendUint64([]byte{'B', version}, mk[0]), mk[1])
+		if mk[0] == 64 {
+			bad = binary.BigEndian.AppendUint64(bad, 0)
+		}
+		if err := c.UnmarshalBinary(bad); !errors.Is(err, ErrInvalidData) {
+			t.Errorf("UnmarshalBinary(m=%d, k=%d) = %v", mk[0], mk[1], err)
+		}
+	}
+}
+
+// zipf returns a seeded stream where a few items are very frequent, like the URLs of a site.
+func zipf(n int) []uint64 {
+	z := rand.NewZipf(rand.New(rand.NewPCG(1, 2)), 1.1, 1, 100000)
+	stream := make([]uint64, n)
+	for i := range stream {
+		stream[i] = z.Uint64()
+	}
+	return stream
+}
+
+func TestCountMin(t *testing.T) {
+	const epsilon, delta = 0.001, 0.01
+	s := NewCountMin[uint64](epsilon, delta)
+	stream := zipf(200000)
+	exact := map[uint64]uint64{}
+	for _, v := range stream {
+		s.Add(v, 1)
+		exact[v]++
+	}
+	if s.Total() != uint64(len(stream)) {
+		t.Errorf("Total = %d", s.Total())
+	}
+	bound := uint64(epsilon * float64(s.Total()))
+	over := 0
+	for v, n := range exact {
+		c := s.Count(v)
+		if c < n {
+			t.Fatalf("Count(%d) = %d is lower than the true count %d", v, c, n)
+		}
+		if c > n+bound {
+			over++
+		}
+	}
+	if rate := float64(over) / float64(len(exact)); rate > delta {
+		t.Errorf("%d of %d counts exceed the error bound %d", over, len(exact), bound)
+	}
+	// The error bound is small for the frequent items: the most frequent one (0, since the Zipf values start at 0)
+	// is within 0.1%.
+	if c, n := s.Count(0), exact[0]; float64(c-n)/float64(n) > 0.001 {
+		t.Errorf("Count of the most frequent item = %d; expected %d", c, n)
+	}
+}
+
+func TestCountMinMergeAndMarshal(t *testing.T) {
+	a, b := NewCountMin[string](0.01, 0.01), NewCountMin[string](0.01, 0.01)
+	a.Add("x", 3)
+	b.Add("x", 4)
+	b.Add("y", 1)
+	if err := a.Merge(b); err != nil || a.Count("x") < 7 || a.Total() != 8 {
+		t.Errorf("Merge = %v, Count(x) = %d, Total = %d", err, a.Count("x"), a.Total())
+	}
+	if err := a.Merge(NewCountMin[string](0.1, 0.01)); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("Merge of different sizes = %v", err)
+	}
+	data, _ := a.MarshalBinary()
+	var c CountMin[string]
+	if err := c.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if c.Count("x") != a.Count("x") || c.Total() != 8 || c.Width() != a.Width() || c.Depth() != a.Depth() {
+		t.Errorf("UnmarshalBinary must restore the sketch")
+	}
+	if err := c.UnmarshalBinary(data[:len(data)-3]); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("UnmarshalBinary of truncated data = %v", err)
+	}
+	// Crafted sizes must not overflow the size checks: 2^32 x 2^32 counters with no data.
+	bad := []byte{'C', version}
+	for _, v := range []uint64{1 << 32, 1 << 32, 0} {
+		bad = binary.BigEndian.AppendUint64(bad, v)
+	}
+	if err := c.UnmarshalBinary(bad); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("UnmarshalBinary of a 2^32 x 2^32 sketch = %v", err)
+	}
+}
+
+func TestHyperLogLog(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	for _, p := range []uint8{10, 14} {
+		for _, n := range []int{100, 1000, 10000, 100000, 1000000} {
+			h := NewHyperLogLog[uint64](p)
+			for range n {
+				v := r.Uint64()
+				h.Add(v)
+				h.Add(v) // The duplicates must not change the count.
+			}
+			// The estimates are within 3 standard errors with a probability of 99.7%.
+			if err := math.Abs(float64(h.Count())-float64(n)) / float64(n); err > 3*h.StandardError() {
+				t.Errorf("p=%d n=%d: Count = %d, error %.2f%%", p, n, h.Count(), err*100)
+			}
+		}
+	}
+	if NewHyperLogLog[string](MinPrecision).Count() != 0 {
+		t.Errorf("an empty HyperLogLog must count 0")
+	}
+}
+
+func TestHyperLogLogMergeAndMarshal(t *testing.T) {
+	a, b := NewHyperLogLog[int](14), NewHyperLogLog[int](14)
+	// The streams overlap on 50000 items, so the union has 150000.
+	for i := range 100000 {
+		a.Add(i)
+		b.Add(i + 50000)
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := math.Abs(float64(a.Count())-150000) / 150000; err > 3*a.StandardError() {
+		t.Errorf("merged Count = %d", a.Count())
+	}
+	if err := a.Merge(NewHyperLogLog[int](10)); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("Merge of different precisions = %v", err)
+	}
+	data, _ := a.MarshalBinary()
+	var c HyperLogLog[int]
+	if err := c.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if c.Count() != a.Count() || c.Precision() != 14 {
+		t.Errorf("UnmarshalBinary must restore the HyperLogLog")
+	}
+	data[3] = 100
+	if err := c.UnmarshalBinary(data); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("UnmarshalBinary with an invalid register = %v", err)
+	}
+	if err := c.UnmarshalBinary([]byte{'H', version, 30}); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("UnmarshalBinary with an invalid precision = %v", err)
+	}
+}
+
+func BenchmarkBloomAdd(b *testing.B) {
+	f := NewBloom[uint64](1000000, 0.01)
+	i := uint64(0)
+	for b.Loop() {
+		f.Add(i)
+		i++
+	}
+}
+
+func BenchmarkCountMinAdd(b *testing.B) {
+	s := NewCountMin[uint64](0.001, 0.01)
+	i := uint64(0)
+	for b.Loop() {
+		s.Add(i, 1)
+		i++
+	}
+}
+
+func BenchmarkHyperLogLogAdd(b *testing.B) {
+	h := NewHyperLogLog[uint64](14)
+	i := uint64(0)
+	for b.Loop() {
+		h.Add(i)
+		i++
+	}
+}
+
+func Example() {
+	seen := NewBloom[string](1000, 0.01)
+	hits := NewCountMin[string](0.01, 0.01)
+	visitors := NewHyperLogLog[string](14)
+	for _, user := range []string{"ann", "bob", "ann", "cid", "ann"} {
+		seen.Add(user)
+		hits.Add(user, 1)
+		visitors.Add(user)
+	}
+	fmt.Println(seen.Contains("bob"), seen.Contains("dan"))
+	fmt.Println(hits.Count("ann"))
+	fmt.Println(visitors.Count())
+	// Output:
+	// true false
+	// 3
+	// 3
+}
@@ -0,0 +1,154 @@
+// Set
+// A set is a collection of unique values, without order.
+// Go does not have a builtin set type, but a map with empty struct values can be used as a set,
+// since the empty struct "struct{}" uses no memory.
+// The generic type below wraps the map with the usual set operations.
+// Syntax:
+//   map[<type>]struct{}
+
+package containers
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+)
+
+// Declaring the Set Type
+// The set is a named map type, so it can be created with "make", ranged over, and passed to the "maps" functions.
+type Set[T comparable] map[T]struct{}
+
+// Creating a Set
+// The function below creates a set with the values, duplicated values are added once.
+func NewSet[T comparable](values ...T) Set[T] {
+	s := make(Set[T], len(values))
+	s.Add(values...)
+	return s
+}
+
+// Collecting a Set
+// The function below creates a set with the values of an iterator (e.g., "slices.Values" or "maps.Keys").
+func CollectSet[T comparable](seq iter.Seq[T]) Set[T] {
+	s := Set[T]{}
+	for v := range seq {
+		s[v] = struct{}{}
+	}
+	return s
+}
+
+func (s Set[T]) Add(values ...T) {
+	for _, v := range values {
+		s[v] = struct{}{}
+	}
+}
+func (s Set[T]) Remove(values ...T) {
+	for _, v := range values {
+		delete(s, v)
+	}
+}
+func (s Set[T]) Contains(v T) bool {
+	_, ok := s[v]
+	return ok
+}
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Iterating a Set
+// The iterator below yields the values of the set in random order, like the map iteration.
+func (s Set[T]) All() iter.Seq[T] {
+	return maps.Keys(s)
+}
+
+// Set Operations
+// The methods below return new sets, and do not change the receiver or the argument.
+// Union: values in any of the sets.
+// Intersection: values in both sets.
+// Difference: values in the receiver, but not in the argument.
+// Symmetric Difference: values in only one of the sets.
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	res := maps.Clone(s)
+	if res == nil {
+		res = Set[T]{}
+	}
+	maps.Copy(res, other)
+	return res
+}
+func (s Set[T]) Intersection(other Set[T]) Set[T] {
+	small, large := s, other
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	res := Set[T]{}
+	for v := range small {
+		if large.Contains(v) {
+			res[v] = struct{}{}
+		}
+	}
+	return res
+}
+func (s Set[T]) Difference(other Set[T]) Set[T] {
+	res := Set[T]{}
+	for v := range s {
+		if !other.Contains(v) {
+			res[v] = struct{}{}
+		}
+	}
+	return res
+}
+func (s Set[T]) SymmetricDifference(other Set[T]) Set[T] {
+	return s.Difference(other).Union(other.Difference(s))
+}
+
+// Set Relations
+// A set is a subset of another when all its values are in the other set, and a superset when it has all the
+// values of the other set. Two sets are equal when they are subsets of each other.
+func (s Set[T]) IsSubset(other Set[T]) bool {
+	if len(s) > len(other) {
+		return false
+	}
+	for v := range s {
+		if !other.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+func (s Set[T]) IsSuperset(other Set[T]) bool {
+	return other.IsSubset(s)
+}
+func (s Set[T]) Equal(other Set[T]) bool {
+	return len(s) == len(other) && s.IsSubset(other)
+}
+
+// Printing a Set
+// The set is printed with its values sorted as text (e.g., "10" before "2"), so the output is deterministic.
+func (s Set[T]) String() string {
+	values := make([]string, 0, len(s))
+	for v := range s {
+		values = append(values, fmt.Sprint(v))
+	}
+	slices.Sort(values)
+	return fmt.Sprint(values)
+}
+
+// Using Sets
+// The example below shows the set operations.
+func UsingSet() {
+	a := NewSet(1, 2, 3, 3)
+	b := NewSet(3, 4)
+	fmt.Println(a, a.Len())                 // Output: [1 2 3] 3
+	fmt.Println(a.Contains(2))              // Output: true
+	fmt.Println(a.Union(b))                 // Output: [1 2 3 4]
+	fmt.Println(a.Intersection(b))          // Output: [3]
+	fmt.Println(a.Difference(b))            // Output: [1 2]
+	fmt.Println(a.SymmetricDifference(b))   // Output: [1 2 4]
+	fmt.Println(NewSet(1, 2).IsSubset(a))   // Output: true
+	fmt.Println(a.IsSuperset(NewSet(1, 4))) // Output: false
+
+	// Collecting Sets from Iterators
+	// The sets can be created from any iterator, e.g., the unique words of a slice.
+	words := CollectSet(slices.Values([]string{"go", "is", "go"}))
+	fmt.Println(words) // Output: [go is]
+}
@@ -0,0 +1,77 @@
+package containers
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestSetOperations(t *testing.T) {
+	a := NewSet(1, 2, 3)
+	b := NewSet(3, 4)
+	for _, tc := range []struct {
+		name string
+		got  Set[int]
+		want Set[int]
+	}{
+		{"union", a.Union(b), NewSet(1, 2, 3, 4)},
+		{"intersection", a.Intersection(b), NewSet(3)},
+		{"difference", a.Difference(b), NewSet(1, 2)},
+		{"symmetric difference", a.SymmetricDifference(b), NewSet(1, 2, 4)},
+		{"nil union", Set[int](nil).Union(nil), NewSet[int]()},
+		{"empty intersection", a.Intersection(NewSet(9)), NewSet[int]()},
+	} {
+		if !tc.got.Equal(tc.want) {
+			t.Errorf("%s = %v; expected %v", tc.name, tc.got, tc.want)
+		}
+	}
+	if !a.Equal(NewSet(3, 2, 1)) || a.Len() != 3 || !b.Equal(NewSet(4, 3)) {
+		t.Errorf("operations must not change the sets, got %v and %v", a, b)
+	}
+	u := Set[int](nil).Union(a)
+	u.Add(9)
+	if a.Contains(9) {
+		t.Errorf("the union must be a new set")
+	}
+}
+
+func TestSetRelations(t *testing.T) {
+	a := NewSet("a", "b", "c")
+	for _, tc := range []struct {
+		s                Set[string]
+		subset, superset bool
+	}{
+		{NewSet("a", "b"), true, false},
+		{NewSet("a", "b", "c"), true, true},
+		{NewSet("a", "d"), false, false},
+		{NewSet[string](), true, false},
+	} {
+		if got := tc.s.IsSubset(a); got != tc.subset {
+			t.Errorf("%v.IsSubset(%v) = %v", tc.s, a, got)
+		}
+		if got := tc.s.IsSuperset(a); got != tc.superset {
+			t.Errorf("%v.IsSuperset(%v) = %v", tc.s, a, got)
+		}
+	}
+	a.Remove("a", "z")
+	if a.Contains("a") || a.String() != "[b c]" {
+		t.Errorf("Remove = %v", a)
+	}
+	if got := slices.Sorted(a.All()); !slices.Equal(got, []string{"b", "c"}) {
+		t.Errorf("All = %v", got)
+	}
+	if c := CollectSet(maps.Keys(map[int]bool{1: true, 2: false})); !c.Equal(NewSet(1, 2)) {
+		t.Errorf("CollectSet = %v", c)
+	}
+}
+
+func BenchmarkSetIntersection(b *testing.B) {
+	x, y := NewSet[int](), NewSet[int]()
+	for i := range 10000 {
+		x.Add(i)
+		y.Add(i * 3)
+	}
+	for b.Loop() {
+		x.Intersection(y)
+	}
+}
@@ -0,0 +1,327 @@
+// Sorted Map
+// A sorted map keeps its keys in order, so it can be iterated in order, and it can answer range queries
+// (e.g., all the keys between "a" and "c", or the first key after 10), which a hash map cannot do.
+// The sorted map below is a left-leaning red-black tree, a balanced binary search tree.
+// The tree height is at most 2*log2(n), so Get, Put and Delete are O(log n).
+// Red-Black Tree Rules:
+//   - Every node is red or black, and the root is black.
+//   - A red node has no red children, and red nodes are always left children (left-leaning).
+//   - Every path from the root to an empty subtree has the same number of black nodes.
+
+package containers
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+)
+
+// Declaring the Sorted Map
+// The keys must be ordered (e.g., numbers and strings), so they can be compared with "cmp.Compare".
+type SortedMap[K cmp.Ordered, V any] struct {
+	root *rbNode[K, V]
+	len  int
+}
+
+type rbNode[K cmp.Ordered, V any] struct {
+	key         K
+	value       V
+	left, right *rbNode[K, V]
+	red         bool
+}
+
+func NewSortedMap[K cmp.Ordered, V any]() *SortedMap[K, V] {
+	return &SortedMap[K, V]{}
+}
+
+func (m *SortedMap[K, V]) Len() int {
+	return m.len
+}
+
+// Getting Values
+// The search goes left for smaller keys and right for greater keys, like a binary search.
+func (m *SortedMap[K, V]) Get(key K) (V, bool) {
+	for n := m.root; n != nil; {
+		switch c := cmp.Compare(key, n.key); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+func (m *SortedMap[K, V]) Has(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// Putting Values
+// New keys are added as red leaves, and the tree is balanced again on the way up, with rotations and color flips.
+func (m *SortedMap[K, V]) Put(key K, value V) {
+	m.root = m.put(m.root, key, value)
+	m.root.red = false
+}
+func (m *SortedMap[K, V]) put(n *rbNode[K, V], key K, value V) *rbNode[K, V] {
+	if n == nil {
+		m.len++
+		return &rbNode[K, V]{key: key, value: value, red: true}
+	}
+	switch c := cmp.Compare(key, n.key); {
+	case c < 0:
+		n.left = m.put(n.left, key, value)
+	case c > 0:
+		n.right = m.put(n.right, key, value)
+	default:
+		n.value = value
+	}
+	return balance(n)
+}
+
+// Deleting Values
+// The deletion keeps a red node on the search path (moving red links down), so the removed node is never
+// black, and the black height does not change. The method reports whether the key was found.
+func (m *SortedMap[K, V]) Delete(key K) bool {
+	if !m.Has(key) {
+		return false
+	}
+	if !isRed(m.root.left) && !isRed(m.root.right) {
+		m.root.red = true
+	}
+	m.root = deleteKey(m.root, key)
+	if m.root != nil {
+		m.root.red = false
+	}
+	m.len--
+	return true
+}
+func deleteKey[K cmp.Ordered, V any](n *rbNode[K, V], key K) *rbNode[K, V] {
+	if cmp.Less(key, n.key) {
+		if !isRed(n.left) && !isRed(n.left.left) {
+			n = moveRedLeft(n)
+		}
+		n.left = deleteKey(n.left, key)
+		return balance(n)
+	}
+	if isRed(n.left) {
+		n = rotateRight(n)
+	}
+	if cmp.Compare(key, n.key) == 0 && n.right == nil {
+		return nil
+	}
+	if !isRed(n.right) && !isRed(n.right.left) {
+		n = moveRedRight(n)
+	}
+	if cmp.Compare(key, n.key) == 0 {
+		// The node is replaced by its successor, the minimum of the right subtree.
+		successor := n.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		n.key, n.value = successor.key, successor.value
+		n.right = deleteMin(n.right)
+	} else {
+		n.right = deleteKey(n.right, key)
+	}
+	return balance(n)
+}
+func deleteMin[K cmp.Ordered, V any](n *rbNode[K, V]) *rbNode[K, V] {
+	if n.left == nil {
+		return nil
+	}
+	if !isRed(n.left) && !isRed(n.left.left) {
+		n = moveRedLeft(n)
+	}
+	n.left = deleteMin(n.left)
+	return balance(n)
+}
+
+// Balancing the Tree
+// The functions below are the red-black tree operations:
+// Rotations change the parent of two nodes, keeping the order of the keys.
+// Color flips move a red link from the children to the parent (or the reverse).
+func isRed[K cmp.Ordered, V any](n *rbNode[K, V]) bool {
+	return n != nil && n.red
+}
+func rotateLeft[K cmp.Ordered, V any](n *rbNode[K, V]) *rbNode[K, V] {
+	x := n.right
+	n.right, x.left = x.left, n
+	x.red, n.red = n.red, true
+	return x
+}
+func rotateRight[K cmp.Ordered, V any](n *rbNode[K, V]) *rbNode[K, V] {
+	x := n.left
+	n.left, x.right = x.right, n
+	x.red, n.red = n.red, true
+	return x
+}
+func flipColors[K cmp.Ordered, V any](n *rbNode[K, V]) {
+	n.red = !n.red
+	n.left.red = !n.left.red
+	n.right.red = !n.right.red
+}
+func balance[K cmp.Ordered, V any](n *rbNode[K, V]) *rbNode[K, V] {
+	if isRed(n.right) && !isRed(n.left) {
+		n = rotateLeft(n)
+	}
+	if isRed(n.left) && isRed(n.left.left) {
+		n = rotateRight(n)
+	}
+	if isRed(n.left) && isRed(n.right) {
+		flipColors(n)
+	}
+	return n
+}
+func moveRedLeft[K cmp.Ordered, V any](n *rbNode[K, V]) *rbNode[K, V] {
+	flipColors(n)
+	if isRed(n.right.left) {
+		n.right = rotateRight(n.right)
+		n = rotateLeft(n)
+		flipColors(n)
+	}
+	return n
+}
+func moveRedRight[K cmp.Ordered, V any](n *rbNode[K, V]) *rbNode[K, V] {
+	flipColors(n)
+	if isRed(n.left.left) {
+		n = rotateRight(n)
+		flipColors(n)
+	}
+	return n
+}
+
+// Minimum and Maximum
+// The minimum is the leftmost node, and the maximum is the rightmost node.
+func (m *SortedMap[K, V]) Min() (K, V, bool) {
+	n := m.root
+	for n != nil && n.left != nil {
+		n = n.left
+	}
+	return entryOf(n)
+}
+func (m *SortedMap[K, V]) Max() (K, V, bool) {
+	n := m.root
+	for n != nil && n.right != nil {
+		n = n.right
+	}
+	return entryOf(n)
+}
+func entryOf[K cmp.Ordered, V any](n *rbNode[K, V]) (K, V, bool) {
+	if n == nil {
+		var (
+			k K
+			v V
+		)
+		return k, v, false
+	}
+	return n.key, n.value, true
+}
+
+// Floor and Ceiling
+// The floor is the greatest key less than or equal to the key, and the ceiling is the smallest key greater than
+// or equal to the key.
+func (m *SortedMap[K, V]) Floor(key K) (K, V, bool) {
+	var best *rbNode[K, V]
+	for n := m.root; n != nil; {
+		switch c := cmp.Compare(key, n.key); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			best, n = n, n.right
+		default:
+			return entryOf(n)
+		}
+	}
+	return entryOf(best)
+}
+func (m *SortedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	var best *rbNode[K, V]
+	for n := m.root; n != nil; {
+		switch c := cmp.Compare(key, n.key); {
+		case c < 0:
+			best, n = n, n.left
+		case c > 0:
+			n = n.right
+		default:
+			return entryOf(n)
+		}
+	}
+	return entryOf(best)
+}
+
+// Iterating a Sorted Map
+// The iterators below walk the tree in order (left subtree, node, right subtree), so the keys are sorted.
+// The map must not be changed during the iteration.
+func (m *SortedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		ascend(m.root, nil, nil, yield)
+	}
+}
+func (m *SortedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		descend(m.root, yield)
+	}
+}
+func (m *SortedMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Range Queries
+// The iterator below yields the entries with keys in the range [from, to), in order.
+// The subtrees out of the range are skipped, so the query is O(log n + k), where k is the number of entries.
+func (m *SortedMap[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		ascend(m.root, &from, &to, yield)
+	}
+}
+
+// ascend walks the entries in order, within the optional bounds [from, to), and reports whether to continue.
+func ascend[K cmp.Ordered, V any](n *rbNode[K, V], from, to *K, yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	aboveFrom := from == nil || cmp.Compare(n.key, *from) >= 0
+	belowTo := to == nil || cmp.Less(n.key, *to)
+	if aboveFrom && !ascend(n.left, from, to, yield) {
+		return false
+	}
+	if aboveFrom && belowTo && !yield(n.key, n.value) {
+		return false
+	}
+	return !belowTo || ascend(n.right, from, to, yield)
+}
+func descend[K cmp.Ordered, V any](n *rbNode[K, V], yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	return descend(n.right, yield) && yield(n.key, n.value) && descend(n.left, yield)
+}
+
+// Using Sorted Maps
+// The example below shows the ordered iteration and the range queries.
+func UsingSortedMap() {
+	m := NewSortedMap[int, string]()
+	for _, k := range []int{50, 10, 40, 20, 30} {
+		m.Put(k, fmt.Sprint("v", k))
+	}
+	for k, v := range m.All() {
+		fmt.Println(k, v) // Output: 10 v10, 20 v20, 30 v30, 40 v40, 50 v50
+	}
+	for k := range m.Range(20, 40) {
+		fmt.Println(k) // Output: 20, 30
+	}
+	k, _, _ := m.Floor(35)
+	fmt.Println(k) // Output: 30
+	k, _, _ = m.Ceiling(35)
+	fmt.Println(k) // Output: 40
+}
@@ -0,0 +1,191 @@
+package containers
+
+import (
+	"cmp"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// checkTree verifies the order of the keys and the red-black rules, and returns the black height.
+func checkTree[K cmp.Ordered, V any](t *testing.T, n *rbNode[K, V], from, to *K) int {
+	t.Helper()
+	if n == nil {
+		return 1
+	}
+	if (from != nil && !cmp.Less(*from, n.key)) || (to != nil && !cmp.Less(n.key, *to)) {
+		t.Fatalf("key %v is out of order", n.key)
+	}
+	if isRed(n.right) {
+		t.Fatalf("node %v has a red right child", n.key)
+	}
+	if n.red && isRed(n.left) {
+		t.Fatalf("node %v and its left child are red", n.key)
+	}
+	left := checkTree(t, n.left, from, &n.key)
+	right := checkTree(t, n.right, &n.key, to)
+	if left != right {
+		t.Fatalf("node %v has black heights %d and %d", n.key, left, right)
+	}
+	if !n.red {
+		left++
+	}
+	return left
+}
+
+func TestSortedMapRandomOperations(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	m := NewSortedMap[int, int]()
+	model := map[int]int{}
+	for i := range 20000 {
+		k := r.IntN(500)
+		if r.IntN(2) == 0 {
+			_, ok := model[k]
+			if m.Delete(k) != ok {
+				t.Fatalf("Delete(%d) is wrong", k)
+			}
+			delete(model, k)
+		} else {
+			m.Put(k, i)
+			model[k] = i
+		}
+		if v, ok := m.Get(k); v != model[k] || ok != m.Has(k) {
+			t.Fatalf("Get(%d) = %v, %v", k, v, ok)
+		}
+		if i%100 == 0 {
+			if isRed(m.root) {
+				t.Fatalf("the root must be black")
+			}
+			checkTree(t, m.root, nil, nil)
+		}
+	}
+	if m.Len() != len(model) {
+		t.Fatalf("Len = %d; expected %d", m.Len(), len(model))
+	}
+	keys := slices.Sorted(maps.Keys(model))
+	if got := slices.Collect(m.Keys()); !slices.Equal(got, keys) {
+		t.Fatalf("Keys = %v; expected %v", got, keys)
+	}
+	if got := maps.Collect(m.All()); !maps.Equal(got, model) {
+		t.Fatalf("entries are wrong")
+	}
+	var backward []int
+	for k := range m.Backward() {
+		backward = append(backward, k)
+	}
+	slices.Reverse(backward)
+	if !slices.Equal(backward, keys) {
+		t.Fatalf("Backward is wrong")
+	}
+	for from := -1; from <= 501; from += 37 {
+		to := from + 60
+		var want []int
+		for _, k := range keys {
+			if k >= from && k < to {
+				want = append(want, k)
+			}
+		}
+		var got []int
+		for k := range m.Range(from, to) {
+			got = append(got, k)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("Range(%d, %d) = %v; expected %v", from, to, got, want)
+		}
+	}
+	for k := range model {
+		m.Delete(k)
+	}
+	if m.Len() != 0 || m.root != nil {
+		t.Fatalf("the map must be empty")
+	}
+}
+
+func TestSortedMapQueries(t *testing.T) {
+	m := NewSortedMap[string, int]()
+	if _, _, ok := m.Min(); ok {
+		t.Errorf("Min of an empty map must return false")
+	}
+	for i, k := range []string{"m", "c", "x", "a"} {
+		m.Put(k, i)
+	}
+	for _, tc := range []struct {
+		name string
+		fn   func() (string, int, bool)
+		key  string
+		ok   bool
+	}{
+		{"Min", m.Min, "a", true},
+		{"Max", m.Max, "x", true},
+		{"Floor(d)", func() (string, int, bool) { return m.Floor("d") }, "c", true},
+		{"Floor(c)", func() (string, int, bool) { return m.Floor("c") }, "c", true},
+		{"Floor(0)", func() (string, int, bool) { return m.Floor("0") }, "", false},
+		{"Ceiling(d)", func() (string, int, bool) { return m.Ceiling("d") }, "m", true},
+		{"Ceiling(y)", func() (string, int, bool) { return m.Ceiling("y") }, "", false},
+	} {
+		if k, _, ok := tc.fn(); k != tc.key || ok != tc.ok {
+			t.Errorf("%s = %q, %v; expected %q, %v", tc.name, k, ok, tc.key, tc.ok)
+		}
+	}
+	// The iteration stops when the loop breaks.
+	n := 0
+	for range m.Range("b", "z") {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("Range must stop after break")
+	}
+}
+
+func TestSortedMapNaN(t *testing.T) {
+	m := NewSortedMap[float64, string]()
+	m.Put(1, "one")
+	m.Put(math.NaN(), "nan")
+	if v, ok := m.Get(math.NaN()); !ok || v != "nan" {
+		t.Errorf("NaN keys must be found, got %q", v)
+	}
+	if k, _, _ := m.Min(); !math.IsNaN(k) {
+		t.Errorf("NaN must be the smallest key, got %v", k)
+	}
+	if !m.Delete(math.NaN()) || m.Len() != 1 {
+		t.Errorf("NaN keys must be deleted")
+	}
+}
+
+// The benchmarks below compare the sorted map with a builtin map sorted when iterating in order.
+
+func BenchmarkSortedMapPut(b *testing.B) {
+	keys := rand.New(rand.NewPCG(5, 6)).Perm(10000)
+	for b.Loop() {
+		m := NewSortedMap[int, int]()
+		for _, k := range keys {
+			m.Put(k, k)
+		}
+	}
+}
+
+func BenchmarkSortedMapIterate(b *testing.B) {
+	m := NewSortedMap[int, int]()
+	for _, k := range rand.New(rand.NewPCG(5, 6)).Perm(10000) {
+		m.Put(k, k)
+	}
+	for b.Loop() {
+		for range m.All() {
+		}
+	}
+}
+
+func BenchmarkBuiltinMapSortedIterate(b *testing.B) {
+	m := map[int]int{}
+	for _, k := range rand.New(rand.NewPCG(5, 6)).Perm(10000) {
+		m[k] = k
+	}
+	for b.Loop() {
+		for _, k := range slices.Sorted(maps.Keys(m)) {
+			_ = m[k]
+		}
+	}
+}
@@ -0,0 +1,247 @@
+// Application Errors
+// The apperr package provides structured errors for applications.
+// Plain errors only have a message, which is fine for logs, but hard to handle in code.
+// Structured errors also have:
+// - a code, which identifies the kind of error (e.g., "not_found"), and can be checked with "errors.Is";
+// - a category, which tells who must act (the client, the server, or nobody, since it may succeed later);
+// - the operation that failed, and extra fields, which give context to the logs;
+// - the wrapped cause, which can be checked with "errors.Is" and "errors.As";
+// - optionally, the stack trace where the error was created.
+
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// Code
+// The code identifies the kind of error, and it does not change when the message changes.
+// It is safe to show the codes to clients, and to use them in their code.
+type Code string
+
+const (
+	CodeUnknown         Code = "unknown"
+	CodeInvalidArgument Code = "invalid_argument"
+	CodeUnauthenticated Code = "unauthenticated"
+	CodeForbidden       Code = "forbidden"
+	CodeNotFound        Code = "not_found"
+	CodeConflict        Code = "conflict"
+	CodeTimeout         Code = "timeout"
+	CodeUnavailable     Code = "unavailable"
+	CodeInternal        Code = "internal"
+)
+
+// Category
+// The category groups the codes by who must act:
+// - Client errors are caused by the request, which must be fixed by the client;
+// - Server errors are bugs or failures that the client cannot fix;
+// - Transient errors may succeed if the operation is retried later.
+type Category string
+
+const (
+	CategoryClient    Category = "client"
+	CategoryServer    Category = "server"
+	CategoryTransient Category = "transient"
+)
+
+// Code Information
+// The table below defines the category and the HTTP status of each code.
+var codes = map[Code]struct {
+	category Category
+	status   int
+}{
+	CodeUnknown:         {CategoryServer, 500},
+	CodeInvalidArgument: {CategoryClient, 400},
+	CodeUnauthenticated: {CategoryClient, 401},
+	CodeForbidden:       {CategoryClient, 403},
+	CodeNotFound:        {CategoryClient, 404},
+	CodeConflict:        {CategoryClient, 409},
+	CodeTimeout:         {CategoryTransient, 504},
+	CodeUnavailable:     {CategoryTransient, 503},
+	CodeInternal:        {CategoryServer, 500},
+}
+
+// Category Method
+// The method below returns the category of the code, unknown codes are server errors.
+func (c Code) Category() Category {
+	if info, ok := codes[c]; ok {
+		return info.category
+	}
+	return CategoryServer
+}
+
+// Error
+// The struct below is the structured error.
+// The fields are exported, so errors can be created with struct literals, but the constructors are preferred,
+// since they capture the stack trace when it is enabled.
+type Error struct {
+	Code    Code
+	Message string
+	Op      string         // Operation that failed (e.g., "users.Get")
+	Fields  map[string]any // Extra context (e.g., the user ID)
+	Err     error          // Wrapped cause
+	stack   []uintptr
+}
+
+// Sentinel Errors
+// The errors below match any error with the same code, when checked with "errors.Is".
+// For example, "errors.Is(err, apperr.ErrNotFound)" is true for every not found error in the chain.
+var (
+	ErrInvalidArgument = &Error{Code: CodeInvalidArgument}
+	ErrUnauthenticated = &Error{Code: CodeUnauthenticated}
+	ErrForbidden       = &Error{Code: CodeForbidden}
+	ErrNotFound        = &Error{Code: CodeNotFound}
+	ErrConflict        = &Error{Code: CodeConflict}
+	ErrTimeout         = &Error{Code: CodeTimeout}
+	ErrUnavailable     = &Error{Code: CodeUnavailable}
+	ErrInternal        = &Error{Code: CodeInternal}
+)
+
+// Stack Capture
+// Capturing the stack trace is relatively expensive, so it is disabled by default.
+// It can be enabled when the program starts (e.g., in development), or in tests.
+var captureStacks atomic.Bool
+
+func CaptureStacks(enabled bool) {
+	captureStacks.Store(enabled)
+}
+
+// Constructors
+// New creates an error with the code and the message.
+// Wrap creates an error with the code and the message, wrapping the cause.
+// Wrap returns nil when the cause is nil, so it can be used directly in return statements.
+func New(code Code, message string) *Error {
+	return newError(code, message, nil)
+}
+func Newf(code Code, format string, args ...any) *Error {
+	return newError(code, fmt.Sprintf(format, args...), nil)
+}
+func Wrap(err error, code Code, message string) error {
+	if err == nil {
+		return nil
+	}
+	return newError(code, message, err)
+}
+
+func newError(code Code, message string, cause error) *Error {
+	e := &Error{Code: code, Message: message, Err: cause}
+	if captureStacks.Load() {
+		pcs := make([]uintptr, 32)
+		n := runtime.Callers(3, pcs) // Skip runtime.Callers, newError and the constructor
+		e.stack = pcs[:n]
+	}
+	return e
+}
+
+// Chaining Methods
+// The methods below add context to the error, and return a modified copy, so they can be chained:
+// apperr.New(apperr.CodeNotFound, "user not found").WithOp("users.Get").With("id", 42)
+// The receiver is never modified, so the methods are safe to call on the sentinel errors, and from several
+// goroutines (e.g., "apperr.ErrNotFound.WithOp("users.Get")" returns a new error, and the sentinel stays the same).
+func (e *Error) WithOp(op string) *Error {
+	c := *e
+	c.Op = op
+	return &c
+}
+func (e *Error) With(key string, value any) *Error {
+	c := *e
+	c.Fields = make(map[string]any, len(e.Fields)+1)
+	maps.Copy(c.Fields, e.Fields)
+	c.Fields[key] = value
+	return &c
+}
+
+// Error Interface
+// The message has the operation, the message and the cause, separated by colons
+// (e.g., "users.Get: user not found: sql: no rows in result set").
+func (e *Error) Error() string {
+	var parts []string
+	if e.Op != "" {
+		parts = append(parts, e.Op)
+	}
+	if e.Message != "" {
+		parts = append(parts, e.Message)
+	} else if e.Err == nil {
+		parts = append(parts, string(e.Code))
+	}
+	if e.Err != nil {
+		parts = append(parts, e.Err.Error())
+	}
+	return strings.Join(parts, ": ")
+}
+
+// Unwrap
+// The Unwrap method allows "errors.Is" and "errors.As" to check the wrapped cause.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is
+// The Is method is called by "errors.Is" for each error in the chain.
+// An error matches a target with the same code, when the target has no message (e.g., the sentinel errors).
+// Otherwise, the message must also be the same.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.Code == e.Code && (t.Message == "" || t.Message == e.Message)
+}
+
+// Stack Trace
+// The method below returns the frames of the stack trace, or nil when the stack was not captured.
+func (e *Error) StackTrace() []runtime.Frame {
+	if len(e.stack) == 0 {
+		return nil
+	}
+	var res []runtime.Frame
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		f, more := frames.Next()
+		res = append(res, f)
+		if !more {
+			return res
+		}
+	}
+}
+
+// Formatting
+// The Format method implements fmt.Formatter, so "%+v" prints the code, the fields and the stack trace.
+// The other verbs print the message, like any other error.
+func (e *Error) Format(s fmt.State, verb rune) {
+	if verb != 'v' || !s.Flag('+') {
+		io.WriteString(s, e.Error())
+		return
+	}
+	fmt.Fprintf(s, "[%s] %s", e.Code, e.Error())
+	for _, k := range sortedKeys(e.Fields) {
+		fmt.Fprintf(s, "\n\t%s=%v", k, e.Fields[k])
+	}
+	for _, f := range e.StackTrace() {
+		fmt.Fprintf(s, "\n\t%s\n\t\t%s:%d", f.Function, f.File, f.Line)
+	}
+}
+
+// Helper Functions
+// The functions below inspect any error, structured or not.
+// CodeOf returns the code of the first structured error in the chain, or CodeUnknown.
+// Note that multi-errors have more than one code, so CodeOf returns the code of the first one.
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return CodeUnknown
+}
+func CategoryOf(err error) Category {
+	return CodeOf(err).Category()
+}
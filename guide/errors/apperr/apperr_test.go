@@ -0,0 +1,210 @@
+package apperr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestErrorMessage(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want string
+	}{
+		{New(CodeNotFound, "user not found"), "user not found"},
+		{New(CodeNotFound, "user not found").WithOp("users.Get"), "users.Get: user not found"},
+		{&Error{Code: CodeNotFound, Message: "loading config", Op: "config.Load", Err: fs.ErrNotExist}, "config.Load: loading config: file does not exist"},
+		{&Error{Code: CodeTimeout}, "timeout"},
+		{&Error{Code: CodeTimeout, Err: fs.ErrClosed}, "file already closed"},
+	} {
+		if got := tc.err.Error(); got != tc.want {
+			t.Errorf("Error() = %q; expected %q", got, tc.want)
+		}
+	}
+	if Wrap(nil, CodeInternal, "ignored") != nil {
+		t.Errorf("Wrap(nil) must return nil")
+	}
+}
+
+func TestChainingCopies(t *testing.T) {
+	e := ErrNotFound.WithOp("users.Get").With("id", 1)
+	if e == ErrNotFound || ErrNotFound.Op != "" || ErrNotFound.Fields != nil || ErrNotFound.Error() != "not_found" {
+		t.Errorf("the sentinel was modified: %+v", ErrNotFound)
+	}
+	if e.Error() != "users.Get: not_found" || !errors.Is(e, ErrNotFound) {
+		t.Errorf("e = %v", e)
+	}
+	f := e.With("name", "ann")
+	if len(e.Fields) != 1 || len(f.Fields) != 2 {
+		t.Errorf("With must copy the fields, got %v and %v", e.Fields, f.Fields)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ErrNotFound.WithOp("op").With("i", i)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestIsAs(t *testing.T) {
+	cause := fmt.Errorf("reading: %w", fs.ErrNotExist)
+	inner := Wrap(cause, CodeNotFound, "config not found")
+	outer := fmt.Errorf("startup: %w", Wrap(inner, CodeUnavailable, "service unavailable"))
+
+	for _, target := range []error{ErrNotFound, ErrUnavailable, fs.ErrNotExist, New(CodeNotFound, "config not found")} {
+		if !errors.Is(outer, target) {
+			t.Errorf("errors.Is(outer, %v) = false", target)
+		}
+	}
+	for _, target := range []error{ErrForbidden, New(CodeNotFound, "other message"), fs.ErrPermission} {
+		if errors.Is(outer, target) {
+			t.Errorf("errors.Is(outer, %v) = true", target)
+		}
+	}
+	var e *Error
+	if !errors.As(outer, &e) || e.Code != CodeUnavailable {
+		t.Errorf("errors.As must find the outermost structured error, got %v", e)
+	}
+	if CodeOf(outer) != CodeUnavailable || CategoryOf(outer) != CategoryTransient {
+		t.Errorf("CodeOf = %v, CategoryOf = %v", CodeOf(outer), CategoryOf(outer))
+	}
+	if CodeOf(cause) != CodeUnknown || CategoryOf(cause) != CategoryServer || CodeOf(nil) != "" {
+		t.Errorf("plain errors must have the unknown code")
+	}
+}
+
+func TestStackCapture(t *testing.T) {
+	if New(CodeInternal, "no stack").StackTrace() != nil {
+		t.Errorf("stacks must not be captured by default")
+	}
+	CaptureStacks(true)
+	defer CaptureStacks(false)
+	e := New(CodeInternal, "with stack").With("id", 7)
+	frames := e.StackTrace()
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, "TestStackCapture") {
+		t.Fatalf("first frame must be the caller of New, got %+v", frames)
+	}
+	out := fmt.Sprintf("%+v", e)
+	if !strings.HasPrefix(out, "[internal] with stack\n\tid=7\n\t") || !strings.Contains(out, "apperr_test.go:") {
+		t.Errorf("%%+v output = %q", out)
+	}
+	if got := fmt.Sprintf("%v|%s", e, e); got != "with stack|with stack" {
+		t.Errorf("%%v and %%s must print the message, got %q", got)
+	}
+	if w := Wrap(errors.New("x"), CodeInternal, "wrapped").(*Error); !strings.HasSuffix(w.StackTrace()[0].Function, "TestStackCapture") {
+		t.Errorf("Wrap must capture the stack of its caller")
+	}
+}
+
+func TestList(t *testing.T) {
+	var l List
+	if l.Err() != nil {
+		t.Errorf("empty list must return nil")
+	}
+	one := New(CodeInvalidArgument, "name is required")
+	l.Add(nil)
+	l.Add(one)
+	if l.Err() != one {
+		t.Errorf("list with one error must return the error itself")
+	}
+	var other List
+	other.Add(New(CodeInvalidArgument, "age is negative"))
+	other.Add(errors.New("plain"))
+	l.Add(other.Err())
+	err := l.Err()
+	if l.Len() != 3 {
+		t.Errorf("Len = %d; expected 3 (flattened)", l.Len())
+	}
+	if want := "3 errors: name is required; age is negative; plain"; err.Error() != want {
+		t.Errorf("Error() = %q; expected %q", err, want)
+	}
+	if !errors.Is(err, ErrInvalidArgument) || !errors.Is(err, one) {
+		t.Errorf("errors.Is must check all the errors")
+	}
+	l.Add(errors.New("after"))
+	if len(err.(*MultiError).Unwrap()) != 3 {
+		t.Errorf("the returned error must not change when the list changes")
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want int
+	}{
+		{nil, 200},
+		{New(CodeForbidden, ""), 403},
+		{fmt.Errorf("wrapped: %w", New(CodeNotFound, "")), 404},
+		{errors.New("plain"), 500},
+		{New(Code("custom"), ""), 500},
+		{errors.Join(New(CodeNotFound, "a"), New(CodeNotFound, "b")), 404},
+		{errors.Join(New(CodeNotFound, "a"), New(CodeForbidden, "b")), 400},
+		{errors.Join(New(CodeNotFound, "a"), errors.New("b")), 500},
+		{fmt.Errorf("ctx: %w", errors.Join(New(CodeConflict, "a"), New(CodeConflict, "b"))), 409},
+	} {
+		if got := HTTPStatus(tc.err); got != tc.want {
+			t.Errorf("HTTPStatus(%v) = %d; expected %d", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	render := func(err error) string {
+		data, _ := json.Marshal(Render(err))
+		return string(data)
+	}
+	for _, tc := range []struct {
+		err  error
+		want string
+	}{
+		{New(CodeNotFound, "user not found").With("id", 1), `{"code":"not_found","message":"user not found","category":"client"}`},
+		{&Error{Code: CodeTimeout}, `{"code":"timeout","message":"timeout","category":"transient"}`},
+		// Internal details are not rendered.
+		{Wrap(errors.New("sql: connection refused"), CodeInternal, "query failed").(*Error).With("query", "SELECT"), `{"code":"internal","message":"internal error","category":"server"}`},
+		{errors.New("secret"), `{"code":"internal","message":"internal error","category":"server"}`},
+		{errors.Join(New(CodeInvalidArgument, "a"), New(CodeInvalidArgument, "b")),
+			`{"code":"invalid_argument","message":"2 errors","category":"client","errors":[` +
+				`{"code":"invalid_argument","message":"a","category":"client"},{"code":"invalid_argument","message":"b","category":"client"}]}`},
+		{errors.Join(New(CodeNotFound, "a"), errors.New("b")),
+			`{"code":"internal","message":"2 errors","category":"server","errors":[` +
+				`{"code":"not_found","message":"a","category":"client"},{"code":"internal","message":"internal error","category":"server"}]}`},
+	} {
+		if got := render(tc.err); got != tc.want {
+			t.Errorf("Render(%v) =\n%s\nexpected\n%s", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	h := Handler(func(w http.ResponseWriter, r *http.Request) error {
+		if r.URL.Path == "/admin" {
+			return New(CodeForbidden, "admin only")
+		}
+		w.Write([]byte("ok"))
+		return nil
+	})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/admin", nil))
+	if rec.Code != 403 || rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("response = %d %v", rec.Code, rec.Header())
+	}
+	var b Body
+	if err := json.Unmarshal(rec.Body.Bytes(), &b); err != nil || b.Code != CodeForbidden || b.Message != "admin only" {
+		t.Errorf("body = %+v, %v", b, err)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != 200 || rec.Body.String() != "ok" {
+		t.Errorf("response = %d %q", rec.Code, rec.Body)
+	}
+}
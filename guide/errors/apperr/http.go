@@ -0,0 +1,133 @@
+package apperr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// HTTP Status
+// The function below maps the error to an HTTP status code, using the code of the structured error.
+// Errors that are not structured are internal errors (500), since they were not expected.
+// For multi-errors, the status is the common status of all the errors, or 500 if any of them is a server error,
+// or 400 otherwise.
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	status := 0
+	for _, leaf := range leaves(err) {
+		s := statusOf(leaf)
+		switch {
+		case status == 0 || status == s:
+			status = s
+		case s >= 500 || status >= 500:
+			status = http.StatusInternalServerError
+		default:
+			status = http.StatusBadRequest
+		}
+	}
+	return status
+}
+func statusOf(err error) int {
+	var e *Error
+	if errors.As(err, &e) {
+		if info, ok := codes[e.Code]; ok {
+			return info.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// Leaves
+// The function below splits multi-errors (e.g., MultiError, or errors.Join) into their errors.
+// The chain is followed until a structured error or a multi-error is found.
+func leaves(err error) []error {
+	for e := err; e != nil; {
+		if _, ok := e.(*Error); ok {
+			break
+		}
+		if m, ok := e.(interface{ Unwrap() []error }); ok {
+			var res []error
+			for _, child := range m.Unwrap() {
+				res = append(res, leaves(child)...)
+			}
+			return res
+		}
+		u, ok := e.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		e = u.Unwrap()
+	}
+	return []error{err}
+}
+
+// Body
+// The body is the JSON representation of an error in API responses.
+// Server errors are rendered without details, since they may contain internal information
+// (e.g., SQL queries, or file paths), which must only be logged.
+// The fields are never rendered, since they are context for the logs (e.g., user IDs), not for the clients.
+type Body struct {
+	Code     Code     `json:"code"`
+	Message  string   `json:"message"`
+	Category Category `json:"category"`
+	Errors   []Body   `json:"errors,omitempty"`
+}
+
+// Render
+// The function below returns the body of the error.
+// Multi-errors have a body for each error.
+func Render(err error) Body {
+	ls := leaves(err)
+	if len(ls) == 1 {
+		return bodyOf(ls[0])
+	}
+	b := Body{Message: fmt.Sprintf("%d errors", len(ls))}
+	for _, leaf := range ls {
+		b.Errors = append(b.Errors, bodyOf(leaf))
+	}
+	b.Code = b.Errors[0].Code
+	for _, e := range b.Errors[1:] {
+		if e.Code != b.Code {
+			b.Code = CodeInvalidArgument
+			if HTTPStatus(err) >= 500 {
+				b.Code = CodeInternal
+			}
+			break
+		}
+	}
+	b.Category = b.Code.Category()
+	return b
+}
+func bodyOf(err error) Body {
+	var e *Error
+	if !errors.As(err, &e) || e.Code.Category() == CategoryServer {
+		return Body{Code: CodeInternal, Message: "internal error", Category: CategoryServer}
+	}
+	msg := e.Message
+	if msg == "" {
+		msg = string(e.Code)
+	}
+	return Body{Code: e.Code, Message: msg, Category: e.Code.Category()}
+}
+
+// Write JSON
+// The function below writes the error to the response, with the status and the JSON body.
+func WriteJSON(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(HTTPStatus(err))
+	json.NewEncoder(w).Encode(Render(err))
+}
+
+// Handler
+// The handler below is an http.Handler that returns an error, instead of writing it.
+// This way, the handlers do not need to write the errors themselves, which keeps the responses consistent.
+type Handler func(w http.ResponseWriter, r *http.Request) error
+
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h(w, r); err != nil {
+		WriteJSON(w, err)
+	}
+}
@@ -0,0 +1,73 @@
+package apperr
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Multi-Error
+// Some operations find many errors at once (e.g., validating all the fields of a form).
+// The List below collects the errors, and returns them as a single error, so the caller sees all of them.
+// It is similar to "errors.Join", but it is built step by step, ignores nil errors, and prints a summary.
+type List struct {
+	errs []error
+}
+
+// Add
+// The method below adds the error to the list, nil errors are ignored.
+// Multi-errors are flattened, so the list does not have nested lists.
+func (l *List) Add(err error) {
+	if err == nil {
+		return
+	}
+	if m, ok := err.(*MultiError); ok {
+		l.errs = append(l.errs, m.errs...)
+		return
+	}
+	l.errs = append(l.errs, err)
+}
+
+// Len
+// The method below returns the number of errors in the list.
+func (l *List) Len() int {
+	return len(l.errs)
+}
+
+// Err
+// The method below returns nil when the list is empty, the error itself when it has a single error,
+// or a MultiError with a copy of the errors.
+func (l *List) Err() error {
+	switch len(l.errs) {
+	case 0:
+		return nil
+	case 1:
+		return l.errs[0]
+	}
+	return &MultiError{errs: slices.Clone(l.errs)}
+}
+
+// Multi Error
+// The error below holds many errors.
+// It implements "Unwrap() []error", so "errors.Is" and "errors.As" check all the errors.
+type MultiError struct {
+	errs []error
+}
+
+func (m *MultiError) Error() string {
+	msgs := make([]string, len(m.errs))
+	for i, err := range m.errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors: %s", len(m.errs), strings.Join(msgs, "; "))
+}
+func (m *MultiError) Unwrap() []error {
+	return m.errs
+}
+
+// Sorted Keys
+// The function below returns the keys of the map in order, so the output is deterministic.
+func sortedKeys(m map[string]any) []string {
+	return slices.Sorted(maps.Keys(m))
+}
@@ -7,7 +7,13 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"guide/errors/apperr"
 )
 
 // Declaring Function that Raises an Error
@@ -95,19 +101,96 @@ func Request(path string) (string, error) {
 
 // Handling a Custom Error
 // The function below shows how to handle the custom error.
-// We can check the type of the error using type assertion to ensure that the error is of type "CustomError".
+// We could check the type of the error using type assertion, like "err.(*CustomError)".
+// However, the type assertion fails when the error is wrapped, since the wrapper has a different type.
+// Instead, we use the "errors.As" function, which checks every error in the chain, and sets the variable
+// to the first error of the type.
 func HandlingCustomError() {
 
 	// Handling Custom Error
-	// We will call the function above to raise the custom error.
+	// We will call the function above to raise the custom error, and wrap it to add context.
 	_, err := Request("/admin")
-	if err != nil {
-		if _, ok := err.(*CustomError); ok {
-			fmt.Println("Error:", err) // Output: Error: Code: 403, Message: Forbidden
-		} else {
-			fmt.Println("Generic Error")
-		}
+	err = fmt.Errorf("loading page: %w", err)
+	if _, ok := err.(*CustomError); !ok {
+		fmt.Println("Type assertion failed") // Output: Type assertion failed
+	}
+	var customErr *CustomError
+	if errors.As(err, &customErr) {
+		fmt.Println("Error:", customErr.Code, customErr.Message) // Output: Error: 403 Forbidden
 	} else {
-		fmt.Println("Successful")
+		fmt.Println("Generic Error")
+	}
+}
+
+// Sentinel Errors
+// Errors that are checked by identity are declared as package-level variables (sentinel errors).
+// The "errors.Is" function checks if any error in the chain is the sentinel error.
+var ErrDivisionByZero = errors.New("division by zero")
+
+func SafeDivide(x, y int) (int, error) {
+	if y == 0 {
+		return 0, fmt.Errorf("dividing %d: %w", x, ErrDivisionByZero)
+	}
+	return x / y, nil
+}
+
+// Handling Sentinel Errors
+// The wrapped sentinel error is found by "errors.Is", but a comparison with "==" would fail.
+func HandlingSentinelError() {
+	_, err := SafeDivide(4, 0)
+	fmt.Println(err == ErrDivisionByZero)          // Output: false
+	fmt.Println(errors.Is(err, ErrDivisionByZero)) // Output: true
+}
+
+// Structured Errors
+// The custom error above has a numeric code, which is an HTTP status, mixed with the message.
+// The apperr package (see errors/apperr) generalizes this idea with structured errors:
+// the code identifies the kind of error, the HTTP status is derived from the code,
+// and the error can have an operation, fields, a wrapped cause and a stack trace.
+func RequestV2(path string) (string, error) {
+	if strings.HasPrefix(path, "/admin") {
+		return "", apperr.New(apperr.CodeForbidden, "admin area").WithOp("Request").With("path", path)
+	}
+	if path != "/" {
+		return "", apperr.Newf(apperr.CodeNotFound, "page %s not found", path).WithOp("Request")
+	}
+	return "Success", nil
+}
+
+// Handling Structured Errors
+// The structured errors are checked with "errors.Is" (by code) and "errors.As" (by type),
+// and they can be rendered as JSON by an HTTP handler.
+func HandlingStructuredError() {
+	_, err := RequestV2("/admin")
+	fmt.Println(err)                                          // Output: Request: admin area
+	fmt.Println(errors.Is(err, apperr.ErrForbidden))          // Output: true
+	fmt.Println(apperr.CodeOf(err), apperr.HTTPStatus(err))   // Output: forbidden 403
+	fmt.Println(apperr.CategoryOf(fmt.Errorf("other error"))) // Output: server
+
+	// Multi-Errors
+	// The list collects many errors, and they are all checked by "errors.Is".
+	var list apperr.List
+	for _, path := range []string{"/", "/admin", "/missing"} {
+		_, err := RequestV2(path)
+		list.Add(err)
 	}
+	err = list.Err()
+	fmt.Println(err)                                // Output: 2 errors: Request: admin area; Request: page /missing not found
+	fmt.Println(errors.Is(err, apperr.ErrNotFound)) // Output: true
+	fmt.Println(apperr.HTTPStatus(err))             // Output: 400
+
+	// HTTP Handler
+	// The handler returns the error, and the apperr.Handler writes the status and the JSON body.
+	handler := apperr.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		body, err := RequestV2(r.URL.Path)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(w, body)
+		return err
+	})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/admin", nil))
+	fmt.Print(rec.Code, " ", rec.Body.String())
+	// Output: 403 {"code":"forbidden","message":"admin area","category":"client"}
 }
@@ -7,7 +7,14 @@
 
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"guide/errors/recovery"
+)
 
 // Declaring a Function that Raises a Panic
 // The function below raises a panic.
@@ -36,3 +43,42 @@ func HandledPanic() {
 	}()
 	Configuration() // Raises a panic
 }
+
+// Panics in Goroutines
+// A panic can only be recovered in the goroutine that panics.
+// So a deferred recover in the function that starts the goroutine cannot recover the panic of the goroutine,
+// and the program crashes.
+// The recovery package starts goroutines that recover their own panics, and forwards them to a handler.
+func GoroutinePanic() {
+	// The handler runs after the function has returned, so the wait group is done at the end of the function
+	// when it does not panic, or at the end of the handler when it does.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	recovery.Go(func() {
+		Configuration()
+		wg.Done()
+	}, func(p *recovery.Panic) {
+		defer wg.Done()
+		fmt.Println("Recovered in goroutine:", p.Value) // Output: Recovered in goroutine: Configuration error!
+	})
+	wg.Wait()
+}
+
+// Panic as Error
+// The function below converts a panic into an error, so it can be handled as any other error.
+func PanicAsError() {
+	err := recovery.Do(Configuration)
+	fmt.Println(err) // Output: panic: Configuration error!
+}
+
+// Panics in HTTP Handlers
+// The recovery middleware responds with the 500 (Internal Server Error) status when a handler panics,
+// and logs the panic with its stack trace.
+func HTTPPanic() {
+	handler := recovery.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Configuration()
+	}), nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/config", nil))
+	fmt.Println(rec.Code) // Output: 500
+}
@@ -0,0 +1,75 @@
+package recovery
+
+import (
+	"bufio"
+	"log"
+	"net"
+	"net/http"
+)
+
+// Middleware
+// The net/http server already recovers the panics of the handlers, but it only logs them, and closes the connection,
+// so the client receives no response at all.
+// The middleware below recovers the panics, logs them with their stack traces, and responds with the
+// 500 (Internal Server Error) status, when the response was not started yet.
+// The http.ErrAbortHandler panic is used to abort a response on purpose, so it is re-panicked for the server.
+func Middleware(next http.Handler, logger *log.Logger) http.Handler {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseWriter{ResponseWriter: w}
+		defer Handle(func(p *Panic) {
+			if p.Value == http.ErrAbortHandler {
+				p.Repanic()
+			}
+			logger.Printf("%s %s: %v\n%s", r.Method, r.URL.Path, p, p.Stack)
+			if rw.wroteHeader {
+				// The status was already sent, so the response can only be aborted.
+				panic(http.ErrAbortHandler)
+			}
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		})
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// Response Writer
+// The writer below records whether the response was started, so the middleware knows if it can still
+// send the 500 status.
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap
+// The method below allows http.ResponseController to access the original writer (e.g., to flush it).
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flusher and Hijacker
+// Many handlers check the optional interfaces with a type assertion (e.g., w.(http.Flusher) to stream events),
+// which does not see the methods of the embedded writer. So the methods below forward them to the original writer.
+// Flushing sends the status, and a hijacked connection belongs to the handler, so both start the response.
+// When the original writer does not support the operation, Flush does nothing, and Hijack returns an error.
+func (w *responseWriter) Flush() {
+	w.wroteHeader = true
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
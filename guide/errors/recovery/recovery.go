@@ -0,0 +1,153 @@
+// Recovery
+// The recovery package provides reusable tools to recover from panics.
+// A panic can only be recovered by a deferred function in the same goroutine that panics.
+// So a panic in a goroutine started with "go" crashes the whole program, even if the function that started the
+// goroutine recovers from panics.
+// The tools below recover the panics in goroutines and HTTP handlers, and report them with their stack traces.
+
+package recovery
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// Panic
+// The struct below is a recovered panic, with the stack trace of the goroutine that panicked.
+// It implements the error interface, so a panic can be returned as an error.
+// When the panic value is an error, it is wrapped, so it can be checked with "errors.Is" and "errors.As".
+type Panic struct {
+	Value any
+	Stack []byte
+}
+
+func (p *Panic) Error() string {
+	return fmt.Sprintf("panic: %v", p.Value)
+}
+func (p *Panic) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// Re-Panic
+// Some panics must not be recovered (e.g., http.ErrAbortHandler), or can only be handled partially.
+// The method below panics again with the original value, so the outer recovery (or the runtime) handles it.
+func (p *Panic) Repanic() {
+	panic(p.Value)
+}
+
+// Handler
+// The handler receives the recovered panics.
+type Handler func(p *Panic)
+
+// New Panic
+// The function below creates the panic with the current stack trace.
+// It must be called in the deferred function, since the stack still has the frames of the panicking function.
+func newPanic(value any) *Panic {
+	return &Panic{Value: value, Stack: debug.Stack()}
+}
+
+// Handle
+// The function below must be deferred, so it recovers the panic, and calls the handler.
+// Note that "recover" only works when it is called directly by the deferred function, so Handle cannot be called
+// inside another deferred function, it must be deferred itself:
+//
+//	defer recovery.Handle(handler)
+func Handle(h Handler) {
+	if r := recover(); r != nil {
+		h(newPanic(r))
+	}
+}
+
+// Do
+// The function below calls the function, and returns the panic as an error, or nil if it does not panic.
+// The panics caused by runtime.Goexit (e.g., t.FailNow) are not recovered, since they are not panics.
+func Do(fn func()) (err error) {
+	defer Handle(func(p *Panic) { err = p })
+	fn()
+	return nil
+}
+
+// Default Handler
+// The default handler logs the panic and its stack trace.
+// It can be replaced when the program starts (e.g., to write crash reports, see CrashReporter).
+var defaultHandler atomic.Pointer[Handler]
+
+func init() {
+	SetDefaultHandler(LogHandler(nil))
+}
+
+func SetDefaultHandler(h Handler) {
+	defaultHandler.Store(&h)
+}
+
+// Log Handler
+// The handler below logs the panic and its stack trace with the logger (or the default logger, if nil).
+func LogHandler(logger *log.Logger) Handler {
+	return func(p *Panic) {
+		l := logger
+		if l == nil {
+			l = log.Default()
+		}
+		l.Printf("%v\n%s", p, p.Stack)
+	}
+}
+
+// Safe Go
+// The functions below start a goroutine that recovers its panics.
+// SafeGo forwards the panics to the default handler, and Go forwards them to the handler.
+// The goroutine ends after the panic is handled, but the program keeps running.
+func SafeGo(fn func()) {
+	Go(fn, *defaultHandler.Load())
+}
+func Go(fn func(), h Handler) {
+	go func() {
+		defer Handle(h)
+		fn()
+	}()
+}
+
+// Crash Reports
+// The function below writes a crash report: the panic, the stack of the goroutine that panicked,
+// and the stacks of all the goroutines, which helps finding deadlocks and leaks.
+func WriteCrashReport(w io.Writer, p *Panic) error {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	_, err := fmt.Fprintf(w, "%v\n\n%s\n\nAll goroutines (%d):\n\n%s\n", p, p.Stack, runtime.NumGoroutine(), buf)
+	return err
+}
+
+// Crash Reporter
+// The handler below writes the crash report to a new file in the directory, and then calls the next handler
+// (e.g., to log the panic, or to re-panic).
+// If the report cannot be written, the error is logged to the standard error.
+func CrashReporter(dir string, next Handler) Handler {
+	return func(p *Panic) {
+		if err := writeCrashFile(dir, p); err != nil {
+			fmt.Fprintln(os.Stderr, "recovery: writing crash report:", err)
+		}
+		if next != nil {
+			next(p)
+		}
+	}
+}
+func writeCrashFile(dir string, p *Panic) error {
+	f, err := os.CreateTemp(dir, "crash-*.txt")
+	if err != nil {
+		return err
+	}
+	return errors.Join(WriteCrashReport(f, p), f.Close())
+}
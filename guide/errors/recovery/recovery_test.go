@@ -0,0 +1,221 @@
+package recovery
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDo(t *testing.T) {
+	if err := Do(func() {}); err != nil {
+		t.Fatalf("Do without panic = %v", err)
+	}
+	err := Do(func() { panic("boom") })
+	var p *Panic
+	if !errors.As(err, &p) || p.Value != "boom" || err.Error() != "panic: boom" {
+		t.Fatalf("Do = %v", err)
+	}
+	if !bytes.Contains(p.Stack, []byte("TestDo")) {
+		t.Errorf("stack must contain the panicking function:\n%s", p.Stack)
+	}
+	if err := Do(func() { panic(fs.ErrNotExist) }); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("panic with an error must wrap it, got %v", err)
+	}
+	var nilMap map[string]int
+	if err := Do(func() { nilMap["x"] = 1 }); err == nil || !strings.Contains(err.Error(), "nil map") {
+		t.Errorf("runtime errors must be recovered, got %v", err)
+	}
+}
+
+func TestNestedPanics(t *testing.T) {
+	// A deferred function panics while the function panics: the last panic is recovered.
+	err := Do(func() {
+		defer func() { panic("second") }()
+		panic("first")
+	})
+	if p := err.(*Panic); p.Value != "second" {
+		t.Errorf("recovered %v; expected the last panic", p.Value)
+	}
+	// The inner recovery handles the inner panic, and the outer one handles the outer panic.
+	var inner error
+	err = Do(func() {
+		inner = Do(func() { panic("inner") })
+		panic("outer")
+	})
+	if inner.(*Panic).Value != "inner" || err.(*Panic).Value != "outer" {
+		t.Errorf("inner = %v, outer = %v", inner, err)
+	}
+	// The handler panics: the outer recovery receives the panic of the handler.
+	err = Do(func() {
+		defer Handle(func(p *Panic) { panic("handler: " + p.Value.(string)) })
+		panic("work")
+	})
+	if err.(*Panic).Value != "handler: work" {
+		t.Errorf("recovered %v", err)
+	}
+}
+
+func TestRepanic(t *testing.T) {
+	handled := false
+	err := Do(func() {
+		defer Handle(func(p *Panic) {
+			handled = true
+			p.Repanic()
+		})
+		panic(fs.ErrClosed)
+	})
+	if !handled || !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("handled = %v, err = %v", handled, err)
+	}
+}
+
+func TestGo(t *testing.T) {
+	panics := make(chan *Panic)
+	Go(func() { panic("in goroutine") }, func(p *Panic) { panics <- p })
+	if p := <-panics; p.Value != "in goroutine" {
+		t.Errorf("handler received %v", p.Value)
+	}
+
+	defer SetDefaultHandler(LogHandler(nil))
+	SetDefaultHandler(func(p *Panic) { panics <- p })
+	SafeGo(func() { panic(42) })
+	if p := <-panics; p.Value != 42 {
+		t.Errorf("default handler received %v", p.Value)
+	}
+}
+
+func TestCrashReporter(t *testing.T) {
+	dir := t.TempDir()
+	var next *Panic
+	h := CrashReporter(dir, func(p *Panic) { next = p })
+	Do(func() {
+		defer Handle(h)
+		panic("crash")
+	})
+	if next == nil || next.Value != "crash" {
+		t.Fatalf("the next handler must be called, got %v", next)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "crash-*.txt"))
+	if len(files) != 1 {
+		t.Fatalf("crash reports = %v", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	report := string(data)
+	if !strings.HasPrefix(report, "panic: crash\n") || !strings.Contains(report, "All goroutines") ||
+		!strings.Contains(report, "TestCrashReporter") || strings.Count(report, "goroutine ") < 2 {
+		t.Errorf("crash report:\n%s", report)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/panic":
+			panic("handler failed")
+		case "/partial":
+			w.Write([]byte("partial"))
+			panic("after write")
+		case "/abort":
+			panic(http.ErrAbortHandler)
+		}
+		w.Write([]byte("ok"))
+	}), log.New(&logs, "", 0))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != 200 || rec.Body.String() != "ok" {
+		t.Errorf("response = %d %q", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/panic", nil))
+	if rec.Code != 500 || !strings.Contains(rec.Body.String(), "Internal Server Error") {
+		t.Errorf("response = %d %q", rec.Code, rec.Body)
+	}
+	if !strings.HasPrefix(logs.String(), "GET /panic: panic: handler failed\n") || !strings.Contains(logs.String(), "goroutine ") {
+		t.Errorf("logs = %q", logs.String())
+	}
+
+	// The response was started, so it is aborted, and the status is not changed.
+	rec = httptest.NewRecorder()
+	err := Do(func() { h.ServeHTTP(rec, httptest.NewRequest("GET", "/partial", nil)) })
+	if !errors.Is(err, http.ErrAbortHandler) || rec.Code != 200 {
+		t.Errorf("partial response: err = %v, status = %d", err, rec.Code)
+	}
+
+	// The abort panic is re-panicked for the server, and not logged.
+	logs.Reset()
+	err = Do(func() { h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/abort", nil)) })
+	if !errors.Is(err, http.ErrAbortHandler) || logs.Len() != 0 {
+		t.Errorf("abort: err = %v, logs = %q", err, logs.String())
+	}
+}
+
+// The optional interfaces of the original writer are still visible to the handlers.
+func TestMiddlewareOptionalInterfaces(t *testing.T) {
+	srv := httptest.NewServer(Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hijack" {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("Hijack = %v", err)
+				return
+			}
+			conn.Write([]byte("HTTP/1.1 204 No Content\r\nConnection: close\r\n\r\n"))
+			conn.Close()
+			return
+		}
+		w.Write([]byte("streamed"))
+		w.(http.Flusher).Flush()
+		panic("after flush")
+	}), log.New(&bytes.Buffer{}, "", 0)))
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/hijack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 204 {
+		t.Errorf("hijacked status = %d; expected 204", resp.StatusCode)
+	}
+
+	// The flushed response was started, so it is aborted, and the client sees the 200 status.
+	resp, err = http.Get(srv.URL + "/flush")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 200 {
+		t.Errorf("flushed status = %d; expected 200", resp.StatusCode)
+	}
+
+	rec := httptest.NewRecorder()
+	Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+	}), nil).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if !rec.Flushed {
+		t.Errorf("Flush was not forwarded to the recorder")
+	}
+}
+
+func TestMiddlewareServer(t *testing.T) {
+	srv := httptest.NewServer(Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("handler failed")
+	}), log.New(&bytes.Buffer{}, "", 0)))
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 500 {
+		t.Errorf("status = %d; expected 500", resp.StatusCode)
+	}
+}
@@ -2,51 +2,263 @@
 // Abstract Factory is a creational design pattern that lets you produce families of related objects without
 // specifying their concrete classes.
 // Since Go does not support OOP, this pattern looks similar to the factory method pattern.
+// In the example below, each factory creates a family of terminal widgets (buttons, inputs, checkboxes and panels)
+// with the same theme, so a form never mixes widgets from different themes.
 
 package creational
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Frame
+// The frame defines the characters used to draw the borders of the widgets.
+type Frame struct {
+	TopLeft, TopRight, BottomLeft, BottomRight rune
+	Horizontal, Vertical                       rune
+}
+
+// Frames
+// The frames below are used by the themes: ASCII, rounded and double-line box drawing characters.
+var (
+	FlatFrame       = Frame{'+', '+', '+', '+', '-', '|'}
+	RoundedFrame    = Frame{'╭', '╮', '╰', '╯', '─', '│'}
+	DoubleLineFrame = Frame{'╔', '╗', '╚', '╝', '═', '║'}
+)
 
-// Model
-// The model below is a struct that represents a button.
-// It contains a Markup field that represents the button's markup.
-// This button can have different styles, such as flat or rounded.
-type Button struct {
-	Markup string
+// Models
+// The models below are the widgets created by the factories.
+// Each one contains the Markup field, which is the styled part of the widget.
+// All of them implement the Widget interface (see widgets.go), so they can be rendered in a terminal.
+// The Input model is defined in the factory method example (see factorymethod.go).
+type (
+	Button struct {
+		Label  string
+		Markup string
+	}
+	Checkbox struct {
+		Label   string
+		Checked bool
+		Markup  string
+	}
+	Panel struct {
+		Title    string
+		Frame    Frame
+		Color    string // ANSI escape sequence for the border, empty for no color
+		Children []Widget
+	}
+)
+
+// Widget Implementations
+// The buttons and checkboxes are rendered in a single line.
+// The panel draws its frame around its children, which are stacked vertically.
+func (b *Button) Lines() []string {
+	return []string{b.Markup}
+}
+func (c *Checkbox) Lines() []string {
+	return []string{c.Markup + " " + c.Label}
+}
+func (p *Panel) Lines() []string {
+	body := VStack(0, p.Children...).Lines()
+	width := visibleWidth(p.Title) + 2
+	for _, line := range body {
+		width = max(width, visibleWidth(line))
+	}
+	color := func(s string) string {
+		if p.Color == "" {
+			return s
+		}
+		return p.Color + s + ansiReset
+	}
+	h := string(p.Frame.Horizontal)
+	v := color(string(p.Frame.Vertical))
+	top := h + h
+	if p.Title != "" {
+		top = h + " " + p.Title + " "
+	}
+	top += strings.Repeat(h, width+2-visibleWidth(top))
+	lines := []string{color(string(p.Frame.TopLeft) + top + string(p.Frame.TopRight))}
+	for _, line := range body {
+		lines = append(lines, v+" "+pad(line, width)+" "+v)
+	}
+	bottom := string(p.Frame.BottomLeft) + strings.Repeat(h, width+2) + string(p.Frame.BottomRight)
+	return append(lines, color(bottom))
 }
 
 // Factory
-// The interface below defines the factory for creating buttons.
-// It contains a method CreateButton that returns a pointer to a Button.
-type ButtonFactory interface {
-	CreateButton() *Button
+// The interface below defines the factory for creating a family of widgets.
+// The client depends only on this interface, so it does not know which theme is used.
+// Note that CreateInput is the factory method of the Form interface, so every widget factory is also a form.
+type WidgetFactory interface {
+	Form
+	CreateButton(label string) *Button
+	CreateCheckbox(label string, checked bool) *Checkbox
+	CreatePanel(title string, children ...Widget) *Panel
 }
 
 // Concrete Factories
-// The structs below are concrete factories that implement the ButtonFactory interface.
-// The FlatButtonFactory creates flat buttons, while the RoundedButtonFactory creates rounded buttons.
+// The structs below are concrete factories that implement the WidgetFactory interface.
+// Each one embeds the form with the same theme, which provides the CreateInput method.
+// The ANSIFactory decorates another factory, adding ANSI colors to its widgets.
 type (
-	FlatButtonFactory    struct{}
-	RoundedButtonFactory struct{}
+	FlatFactory       struct{ FlatForm }
+	RoundedFactory    struct{ RoundedForm }
+	DoubleLineFactory struct{ DoubleLineForm }
+	ANSIFactory       struct{ Base WidgetFactory }
+)
+
+// Flat Factory
+// The flat theme uses only ASCII characters, so it works in any terminal.
+func (f *FlatFactory) CreateButton(label string) *Button {
+	return &Button{Label: label, Markup: "[" + label + "]"}
+}
+func (f *FlatFactory) CreateCheckbox(label string, checked bool) *Checkbox {
+	return &Checkbox{Label: label, Checked: checked, Markup: "[" + mark(checked) + "]"}
+}
+func (f *FlatFactory) CreatePanel(title string, children ...Widget) *Panel {
+	return &Panel{Title: title, Frame: FlatFrame, Children: children}
+}
+
+// Rounded Factory
+// The rounded theme uses parentheses and rounded box drawing characters.
+func (f *RoundedFactory) CreateButton(label string) *Button {
+	return &Button{Label: label, Markup: "(" + label + ")"}
+}
+func (f *RoundedFactory) CreateCheckbox(label string, checked bool) *Checkbox {
+	return &Checkbox{Label: label, Checked: checked, Markup: "(" + mark(checked) + ")"}
+}
+func (f *RoundedFactory) CreatePanel(title string, children ...Widget) *Panel {
+	return &Panel{Title: title, Frame: RoundedFrame, Children: children}
+}
+
+// Double-Line Factory
+// The double-line theme uses double brackets and double-line box drawing characters.
+func (f *DoubleLineFactory) CreateButton(label string) *Button {
+	return &Button{Label: label, Markup: "⟦" + label + "⟧"}
+}
+func (f *DoubleLineFactory) CreateCheckbox(label string, checked bool) *Checkbox {
+	return &Checkbox{Label: label, Checked: checked, Markup: "⟦" + mark(checked) + "⟧"}
+}
+func (f *DoubleLineFactory) CreatePanel(title string, children ...Widget) *Panel {
+	return &Panel{Title: title, Frame: DoubleLineFrame, Children: children}
+}
+
+// ANSI Escape Sequences
+// The sequences below change the style of the text in the terminal, until the reset sequence.
+const (
+	ansiReset     = "\x1b[0m"
+	ansiReverse   = "\x1b[1;7m"
+	ansiUnderline = "\x1b[4m"
+	ansiGreen     = "\x1b[32m"
+	ansiCyan      = "\x1b[36m"
 )
 
-// Factory Implementations
-// The functions below are implementations for the factories.
-// The FlatButtonFactory creates a button with flat markup.
-// The RoundedButtonFactory creates a button with rounded markup.
-func (d *FlatButtonFactory) CreateButton() *Button {
-	return &Button{Markup: "[Button]"}
+// ANSI Factory
+// The ANSI factory creates the widgets with the base factory, and adds colors to their markup.
+// Since it only depends on the WidgetFactory interface, it can color any theme.
+func (f *ANSIFactory) CreateButton(label string) *Button {
+	b := f.Base.CreateButton(label)
+	b.Markup = ansiReverse + b.Markup + ansiReset
+	return b
 }
-func (d *RoundedButtonFactory) CreateButton() *Button {
-	return &Button{Markup: "(Button)"}
+func (f *ANSIFactory) CreateInput(label string, width int) *Input {
+	i := f.Base.CreateInput(label, width)
+	i.Markup = ansiUnderline + i.Markup + ansiReset
+	return i
+}
+func (f *ANSIFactory) CreateCheckbox(label string, checked bool) *Checkbox {
+	c := f.Base.CreateCheckbox(label, checked)
+	if checked {
+		c.Markup = ansiGreen + c.Markup + ansiReset
+	}
+	return c
+}
+func (f *ANSIFactory) CreatePanel(title string, children ...Widget) *Panel {
+	p := f.Base.CreatePanel(title, children...)
+	p.Color = ansiCyan
+	return p
+}
+
+// Mark
+// The function below returns the mark of a checkbox.
+func mark(checked bool) string {
+	if checked {
+		return "x"
+	}
+	return " "
+}
+
+// Themes
+// The themes can be selected by name (e.g., from a flag or a configuration file).
+// The function below returns the factory for the theme, so the rest of the application does not reference
+// the concrete factories.
+var ErrUnknownTheme = errors.New("unknown theme")
+
+func NewWidgetFactory(theme string) (WidgetFactory, error) {
+	switch theme {
+	case "flat":
+		return &FlatFactory{}, nil
+	case "rounded":
+		return &RoundedFactory{}, nil
+	case "double":
+		return &DoubleLineFactory{}, nil
+	case "ansi":
+		return &ANSIFactory{Base: &RoundedFactory{}}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownTheme, theme)
+}
+
+// Client
+// The client below depends only on the WidgetFactory interface, not on the concrete factories.
+// The factory is received in the constructor, so the container can inject it.
+type Toolbar struct {
+	Buttons []*Button
+}
+
+// Client Constructor
+// The toolbar uses the factory to create its buttons.
+func NewToolbar(factory WidgetFactory) *Toolbar {
+	return &Toolbar{Buttons: []*Button{factory.CreateButton("New"), factory.CreateButton("Open")}}
+}
+
+// Widgets
+// The toolbar buttons are placed side by side.
+func (t *Toolbar) Lines() []string {
+	widgets := make([]Widget, len(t.Buttons))
+	for i, b := range t.Buttons {
+		widgets[i] = b
+	}
+	return HStack(1, widgets...).Lines()
 }
 
 // Test Factory
 // The function below is a test for the abstract factory pattern.
+// The same login form is rendered with each theme, and the widgets always match each other.
+// The concrete factory can also be chosen when the container is configured, and injected into the toolbar.
+// Changing the style of the whole application only requires registering another factory.
 func TestFactory() {
-	flatFactory := &FlatButtonFactory{}
-	roundFactory := &RoundedButtonFactory{}
-	b1 := flatFactory.CreateButton()
-	b2 := roundFactory.CreateButton()
-	fmt.Println("b1:", b1.Markup, "b2:", b2.Markup) // Output: b1: [Button] b2: (Button)
+	for _, theme := range []string{"flat", "rounded", "double"} {
+		factory, _ := NewWidgetFactory(theme)
+		fmt.Print(RenderWidget(LoginForm(factory)))
+	}
+	// Output:
+	// +- Login ----------------+
+	// | Username: [__________] |
+	// | Password: [__________] |
+	// | [x] Remember me        |
+	// | [ ] Stay signed in     |
+	// |                        |
+	// | [Sign in] [Cancel]     |
+	// +------------------------+
+	// ╭─ Login ────────────────╮
+	// │ Username: (__________) │
+	// ...
+
+	c := NewContainer()
+	c.MustProvide(func() WidgetFactory { return &RoundedFactory{} }, Singleton)
+	c.MustProvide(NewToolbar, Transient)
+	toolbar := MustResolve[*Toolbar](c)
+	fmt.Print(RenderWidget(toolbar)) // Output: (New) (Open)
 }
@@ -5,13 +5,47 @@
 
 package creational
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+)
 
 // Model
 // The model below will be used to demonstrate the Builder pattern.
+// The struct tags are read by the buildergen tool (see tools/buildergen), which generates a builder for the struct.
+// The fields start with their default values. The ID and the name are checked by Validate, not marked as required,
+// so a missing and an invalid value are reported with the same rule, only once.
 type Person struct {
-	ID   int
-	Name string
+	ID    int
+	Name  string
+	Email string
+	Role  string `builder:"default=user"`
+}
+
+// Generated Builder
+// The directive below generates the GeneratedPersonBuilder in the person_builder.go file.
+// The generated builder has the same fluent API as the DefaultPersonBuilder below, without the boilerplate.
+//
+//go:generate buildergen -type=Person -name=GeneratedPersonBuilder
+
+// Validate
+// The method below validates the person. All the rules of the fields are here, in a single place.
+// Both the hand-written and the generated builders call it when building the person.
+func (p *Person) Validate() error {
+	var errs []error
+	if p.ID <= 0 {
+		errs = append(errs, fmt.Errorf("id must be positive, got %d", p.ID))
+	}
+	if p.Name == "" {
+		errs = append(errs, errors.New("name cannot be empty"))
+	}
+	if p.Email != "" {
+		if _, err := mail.ParseAddress(p.Email); err != nil {
+			errs = append(errs, fmt.Errorf("invalid email %q", p.Email))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // Director
@@ -23,48 +57,85 @@ type PersonDirector struct {
 
 // Director Function
 // The function below is an implementation for the director.
-func (d *PersonDirector) Build(id int, name string) *Person {
-	pb := d.Builder
-	pb.WithID(id)
-	pb.WithName(name)
-	return pb.Build()
+// Since the builder is fluent, the steps can be chained.
+func (d *PersonDirector) Build(id int, name string) (*Person, error) {
+	return d.Builder.WithID(id).WithName(name).Build()
 }
 
 // Builder Interface
 // The builder interface defines the methods for constructing the object.
 // It allows for different implementations of the builder to create different representations of the object.
 // The builder interface is used by the director to construct the object step by step.
+// Each step returns the builder itself, so the steps can be chained (fluent interface).
+// The Build method returns an error when the object is not valid.
 type PersonBuilder interface {
-	WithID(id int)
-	WithName(name string)
-	Build() *Person
+	WithID(id int) PersonBuilder
+	WithName(name string) PersonBuilder
+	WithEmail(email string) PersonBuilder
+	WithRole(role string) PersonBuilder
+	Build() (*Person, error)
 }
 
 // Builder
 // The default builder is a concrete implementation of the builder interface.
 // It provides the methods implementation for constructing the object step by step.
+// The steps do not check the values: the person is validated by the Build method, which reports all the
+// invalid fields together. This way, the consumer can chain all the steps, and check the errors only once.
 type DefaultPersonBuilder struct {
-	Person *Person
+	person *Person
+}
+
+// Builder Constructor
+// The constructor creates the builder with the default values.
+func NewDefaultPersonBuilder() *DefaultPersonBuilder {
+	return &DefaultPersonBuilder{person: &Person{Role: "user"}}
 }
 
 // Builder Functions
 // The functions below are implementations for the builder.
-func (b *DefaultPersonBuilder) WithID(id int) {
-	b.Person.ID = id
+func (b *DefaultPersonBuilder) WithID(id int) PersonBuilder {
+	b.person.ID = id
+	return b
 }
-func (b *DefaultPersonBuilder) WithName(name string) {
-	b.Person.Name = name
+func (b *DefaultPersonBuilder) WithName(name string) PersonBuilder {
+	b.person.Name = name
+	return b
 }
-func (b *DefaultPersonBuilder) Build() *Person {
-	return b.Person
+func (b *DefaultPersonBuilder) WithEmail(email string) PersonBuilder {
+	b.person.Email = email
+	return b
+}
+func (b *DefaultPersonBuilder) WithRole(role string) PersonBuilder {
+	b.person.Role = role
+	return b
+}
+func (b *DefaultPersonBuilder) Build() (*Person, error) {
+	if err := b.person.Validate(); err != nil {
+		return nil, err
+	}
+	p := *b.person
+	return &p, nil
 }
 
 // Test Builder
 // The test function below demonstrates the usage of the builder pattern.
 // It creates a new person using the builder and prints the result.
 func TestBuilder() {
-	pb := &DefaultPersonBuilder{Person: &Person{}}
-	pd := &PersonDirector{Builder: pb}
-	p := pd.Build(1, "John Doe")
-	fmt.Println(p) // Output: &{1 John Doe}
+	pd := &PersonDirector{Builder: NewDefaultPersonBuilder()}
+	p, _ := pd.Build(1, "John Doe")
+	fmt.Println(p) // Output: &{1 John Doe  user}
+
+	// Accumulated Errors
+	// All the invalid fields are reported at once.
+	_, err := NewDefaultPersonBuilder().WithID(-1).WithName("").WithEmail("invalid").Build()
+	fmt.Println(err)
+	// Output:
+	// id must be positive, got -1
+	// name cannot be empty
+	// invalid email "invalid"
+
+	// Generated Builder
+	// The generated builder validates the person with the same rules, so a missing ID is reported as invalid.
+	_, err = NewGeneratedPersonBuilder().WithName("Jane").Build()
+	fmt.Println(err) // Output: id must be positive, got 0
 }
@@ -0,0 +1,52 @@
+package creational
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPersonBuilder(t *testing.T) {
+	p, err := NewDefaultPersonBuilder().WithID(1).WithName("John").WithEmail("john@test.com").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Person{ID: 1, Name: "John", Email: "john@test.com", Role: "user"}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("Build = %+v; expected %+v", p, want)
+	}
+
+	_, err = NewDefaultPersonBuilder().WithID(-1).WithName("").WithEmail("invalid").Build()
+	want2 := "id must be positive, got -1\nname cannot be empty\ninvalid email \"invalid\""
+	if err == nil || err.Error() != want2 {
+		t.Errorf("Build error = %v; expected %q", err, want2)
+	}
+}
+
+func TestGeneratedPersonBuilder(t *testing.T) {
+	p, err := NewGeneratedPersonBuilder().WithID(2).WithName("Jane").WithRole("admin").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&Person{ID: 2, Name: "Jane", Role: "admin"}); !reflect.DeepEqual(p, want) {
+		t.Errorf("Build = %+v; expected %+v", p, want)
+	}
+
+	b := NewGeneratedPersonBuilder().WithEmail("bad")
+	_, err = b.Build()
+	want := "id must be positive, got 0\nname cannot be empty\ninvalid email \"bad\""
+	if err == nil || err.Error() != want {
+		t.Errorf("Build error = %v; expected %q", err, want)
+	}
+
+	// Each Build returns a new copy, so the builder can be reused.
+	if _, err := NewGeneratedPersonBuilder().WithID(-1).WithName("Jo").Build(); err == nil || err.Error() != "id must be positive, got -1" {
+		t.Errorf("Build error = %v; each invalid field must be reported once", err)
+	}
+
+	b.WithID(3).WithName("Joe").WithEmail("joe@test.com")
+	p1, _ := b.Build()
+	p2, _ := b.Build()
+	if p1 == p2 || *p1 != *p2 {
+		t.Errorf("Build must return equal copies: %p %p", p1, p2)
+	}
+}
@@ -0,0 +1,246 @@
+// Deep Clone
+// The Payment.Clone method from the Prototype example copies each field by hand.
+// This works for flat structs, but when a struct has pointers, slices or maps, copying the fields only copies
+// the references (shallow copy), so the clone and the prototype share the same memory.
+// The DeepClone function below uses reflection to copy any value recursively (deep copy).
+// It handles pointers, slices, maps, arrays, interfaces, nested structs (including unexported fields) and cycles.
+
+package creational
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// Cloner
+// Types can implement the Cloner interface to override the deep copy.
+// When DeepClone finds a value that implements "Clone() T", where T is the type of the value itself,
+// it calls the method instead of copying the value by reflection (e.g., Payment.Clone returns *Payment).
+// Note that the Clone method must not call DeepClone on its own receiver, since it would loop forever.
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// Visit Key
+// The key below identifies a reference (pointer, slice, or map) that was already copied.
+// Slices also need the length, since two slices can share the same backing array with different lengths.
+type visitKey struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+// Cloner State
+// The state keeps the references already copied, so shared references are copied once, and cycles terminate.
+type cloneState struct {
+	visited map[visitKey]reflect.Value
+}
+
+// Types
+// The types below are handled in a special way by DeepClone.
+// Time values are copied as they are, since their location pointer must be kept (e.g., time.UTC).
+var timeType = reflect.TypeFor[time.Time]()
+
+// Deep Clone
+// The function below returns a deep copy of the value.
+// The copy shares no memory with the original value, except for strings (which are immutable),
+// functions, channels and unsafe pointers (which cannot be copied).
+func DeepClone[T any](v T) T {
+	s := &cloneState{visited: map[visitKey]reflect.Value{}}
+	src := reflect.ValueOf(&v).Elem() // Addressable, so unexported fields can be read
+	res, _ := s.clone(src).Interface().(T)
+	return res
+}
+
+// Clone Implementation
+// The method below copies the value according to its kind.
+func (s *cloneState) clone(src reflect.Value) reflect.Value {
+	t := src.Type()
+	if c, ok := s.cloner(src); ok {
+		return c
+	}
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return reflect.Zero(t)
+		}
+		key := visitKey{typ: t, ptr: src.Pointer()}
+		if dst, ok := s.visited[key]; ok {
+			return dst
+		}
+		dst := reflect.New(t.Elem())
+		s.visited[key] = dst
+		dst.Elem().Set(s.clone(src.Elem()))
+		return dst
+	case reflect.Slice:
+		if src.IsNil() {
+			return reflect.Zero(t)
+		}
+		key := visitKey{typ: t, ptr: src.Pointer(), len: src.Len()}
+		if dst, ok := s.visited[key]; ok {
+			return dst
+		}
+		dst := reflect.MakeSlice(t, src.Len(), src.Cap())
+		s.visited[key] = dst
+		for i := range src.Len() {
+			dst.Index(i).Set(s.clone(src.Index(i)))
+		}
+		return dst
+	case reflect.Map:
+		if src.IsNil() {
+			return reflect.Zero(t)
+		}
+		key := visitKey{typ: t, ptr: src.Pointer()}
+		if dst, ok := s.visited[key]; ok {
+			return dst
+		}
+		dst := reflect.MakeMapWithSize(t, src.Len())
+		s.visited[key] = dst
+		iter := src.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(s.clone(addressable(iter.Key())), s.clone(addressable(iter.Value())))
+		}
+		return dst
+	case reflect.Array:
+		dst := reflect.New(t).Elem()
+		src = addressable(src)
+		for i := range src.Len() {
+			dst.Index(i).Set(s.clone(src.Index(i)))
+		}
+		return dst
+	case reflect.Struct:
+		if t == timeType {
+			return src
+		}
+		dst := reflect.New(t).Elem()
+		src = addressable(src)
+		for i := range src.NumField() {
+			exposed(dst.Field(i)).Set(s.clone(exposed(src.Field(i))))
+		}
+		return dst
+	case reflect.Interface:
+		if src.IsNil() {
+			return reflect.Zero(t)
+		}
+		dst := reflect.New(t).Elem()
+		dst.Set(s.clone(addressable(src.Elem())))
+		return dst
+	}
+	return src
+}
+
+// Cloner Check
+// The method below calls the Clone method of the value, if it implements the Cloner interface.
+// Looking up methods by name is slow, so the result of the lookup is cached for each type.
+var clonerMethods sync.Map // map[reflect.Type]int (method index, or -1)
+
+func (s *cloneState) cloner(src reflect.Value) (reflect.Value, bool) {
+	t := src.Type()
+	idx, ok := clonerMethods.Load(t)
+	if !ok {
+		idx = -1
+		m, found := t.MethodByName("Clone")
+		if found && m.Type.NumOut() == 1 && m.Type.Out(0) == t &&
+			((t.Kind() == reflect.Interface && m.Type.NumIn() == 0) || (t.Kind() != reflect.Interface && m.Type.NumIn() == 1)) {
+			idx = m.Index
+		}
+		clonerMethods.Store(t, idx)
+	}
+	// A nil pointer or interface has no value to call the method on, so it is copied as nil.
+	if idx.(int) < 0 || ((src.Kind() == reflect.Pointer || src.Kind() == reflect.Interface) && src.IsNil()) {
+		return reflect.Value{}, false
+	}
+	return src.Method(idx.(int)).Call(nil)[0], true
+}
+
+// Helper Functions
+// The addressable function copies a value into a new variable, when it is not addressable.
+// The exposed function allows reading and writing unexported fields, which reflection does not allow by default.
+// It uses the unsafe package to create a new reflect.Value pointing to the same memory.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	return c
+}
+func exposed(v reflect.Value) reflect.Value {
+	if v.CanSet() {
+		return v
+	}
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+// Prototype Registry
+// The registry keeps named prototypes (templates), which are cloned when requested.
+// The clones can be customized, without changing the prototype.
+// It is safe for concurrent use.
+type PrototypeRegistry[T any] struct {
+	mu         sync.RWMutex
+	prototypes map[string]T
+}
+
+// Prototype Errors
+// The error below is returned when the prototype is not registered.
+var ErrPrototypeNotFound = errors.New("prototype not found")
+
+// Prototype Registry Constructor
+// The constructor creates an empty registry.
+func NewPrototypeRegistry[T any]() *PrototypeRegistry[T] {
+	return &PrototypeRegistry[T]{prototypes: map[string]T{}}
+}
+
+// Register
+// The method below registers a copy of the prototype, so later changes to the original do not affect the registry.
+func (r *PrototypeRegistry[T]) Register(name string, prototype T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prototypes[name] = DeepClone(prototype)
+}
+
+// Clone
+// The method below returns a deep copy of the named prototype, after applying the customizations.
+func (r *PrototypeRegistry[T]) Clone(name string, customize ...func(*T)) (T, error) {
+	r.mu.RLock()
+	prototype, ok := r.prototypes[name]
+	r.mu.RUnlock()
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("%w: %s", ErrPrototypeNotFound, name)
+	}
+	c := DeepClone(prototype)
+	for _, fn := range customize {
+		fn(&c)
+	}
+	return c, nil
+}
+
+// Test Deep Clone
+// The function below clones an invoice with nested payments.
+// Changing the clone does not change the prototype, since they share no memory.
+func TestDeepClone() {
+	type Invoice struct {
+		Number   string
+		Payments []*Payment
+		Tags     map[string]string
+	}
+	registry := NewPrototypeRegistry[*Invoice]()
+	registry.Register("default", &Invoice{
+		Number:   "0000",
+		Payments: []*Payment{{Amount: 100, Tax: 10}},
+		Tags:     map[string]string{"currency": "USD"},
+	})
+	i1, _ := registry.Clone("default", func(i **Invoice) {
+		(*i).Number = "0001"
+		(*i).Payments[0].Amount = 200
+		(*i).Tags["currency"] = "EUR"
+	})
+	i2, _ := registry.Clone("default")
+	fmt.Println(i1.Number, i1.Payments[0].Amount, i1.Tags["currency"]) // Output: 0001 200 EUR
+	fmt.Println(i2.Number, i2.Payments[0].Amount, i2.Tags["currency"]) // Output: 0000 100 USD
+}
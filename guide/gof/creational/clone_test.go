@@ -0,0 +1,286 @@
+package creational
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type (
+	cloneAddress struct {
+		Street string
+		Lines  []string
+	}
+	cloneCustomer struct {
+		Name    string
+		Address *cloneAddress
+		Tags    map[string][]int
+		Scores  [3]*int
+		Extra   any
+		Created time.Time
+		secret  *string
+	}
+	cloneShape interface {
+		Clone() cloneShape
+	}
+	cloneDrawing struct {
+		Shape cloneShape
+	}
+	cloneNode struct {
+		Value int
+		Next  *cloneNode
+	}
+	countingCloner struct {
+		calls *int
+		Data  []int
+	}
+)
+
+func (c *countingCloner) Clone() *countingCloner {
+	*c.calls++
+	return &countingCloner{calls: c.calls, Data: []int{-1}}
+}
+
+// References
+// The function below collects the addresses of all the memory reachable from a value (pointers, slices and maps).
+// Two values share no memory when their sets of addresses do not intersect.
+func references(v reflect.Value, seen map[uintptr]bool) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || seen[v.Pointer()] {
+			return
+		}
+		seen[v.Pointer()] = true
+		references(v.Elem(), seen)
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		if v.Cap() > 0 {
+			seen[v.Pointer()] = true
+		}
+		for i := range v.Len() {
+			references(v.Index(i), seen)
+		}
+	case reflect.Map:
+		if v.IsNil() || seen[v.Pointer()] {
+			return
+		}
+		seen[v.Pointer()] = true
+		iter := v.MapRange()
+		for iter.Next() {
+			references(iter.Key(), seen)
+			references(iter.Value(), seen)
+		}
+	case reflect.Array:
+		for i := range v.Len() {
+			references(v.Index(i), seen)
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return
+		}
+		for i := range v.NumField() {
+			references(v.Field(i), seen)
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			references(v.Elem(), seen)
+		}
+	}
+}
+
+func assertNoSharedMemory(t *testing.T, a, b any) {
+	t.Helper()
+	ra, rb := map[uintptr]bool{}, map[uintptr]bool{}
+	references(reflect.ValueOf(a), ra)
+	references(reflect.ValueOf(b), rb)
+	if len(ra) == 0 {
+		t.Fatalf("no references found in %#v", a)
+	}
+	for p := range ra {
+		if rb[p] {
+			t.Errorf("clone shares memory with the original at %#x", p)
+		}
+	}
+}
+
+func newCloneCustomer() *cloneCustomer {
+	one, two, secret := 1, 2, "s3cr3t"
+	return &cloneCustomer{
+		Name:    "John",
+		Address: &cloneAddress{Street: "Main St", Lines: []string{"apt 1", "floor 2"}},
+		Tags:    map[string][]int{"a": {1, 2}, "b": nil},
+		Scores:  [3]*int{&one, &two, nil},
+		Extra:   &cloneAddress{Street: "Other St"},
+		Created: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		secret:  &secret,
+	}
+}
+
+func TestDeepCloneNoSharedMemory(t *testing.T) {
+	original := newCloneCustomer()
+	clone := DeepClone(original)
+	if !reflect.DeepEqual(original, clone) {
+		t.Fatalf("clone differs from the original:\n%#v\n%#v", original, clone)
+	}
+	assertNoSharedMemory(t, original, clone)
+
+	clone.Address.Lines[0] = "changed"
+	clone.Tags["a"][0] = 99
+	*clone.Scores[0] = 99
+	*clone.secret = "changed"
+	clone.Extra.(*cloneAddress).Street = "changed"
+	if !reflect.DeepEqual(original, newCloneCustomer()) {
+		t.Errorf("changing the clone changed the original: %#v", original)
+	}
+	if clone.Created.Location() != time.UTC {
+		t.Errorf("time location was not preserved")
+	}
+}
+
+func TestDeepCloneCycles(t *testing.T) {
+	a := &cloneNode{Value: 1}
+	b := &cloneNode{Value: 2, Next: a}
+	a.Next = b
+	c := DeepClone(a)
+	if c == a || c.Next == b {
+		t.Fatalf("cycle was not copied")
+	}
+	if c.Next.Next != c || c.Next.Value != 2 {
+		t.Errorf("cycle structure was not preserved")
+	}
+
+	// A slice that contains itself (through an interface) must also terminate.
+	s := make([]any, 1)
+	s[0] = s
+	cs := DeepClone(s)
+	if reflect.ValueOf(cs).Pointer() == reflect.ValueOf(s).Pointer() {
+		t.Errorf("self-referencing slice was not copied")
+	}
+}
+
+func TestDeepCloneSharedReferences(t *testing.T) {
+	shared := &cloneAddress{Street: "Shared"}
+	pair := [2]*cloneAddress{shared, shared}
+	c := DeepClone(pair)
+	if c[0] != c[1] || c[0] == shared {
+		t.Errorf("shared references must be copied once, and kept shared in the clone")
+	}
+}
+
+func TestDeepCloneCloner(t *testing.T) {
+	calls := 0
+	original := []*countingCloner{{calls: &calls, Data: []int{1}}, nil}
+	c := DeepClone(original)
+	if calls != 1 || c[0].Data[0] != -1 || c[1] != nil {
+		t.Errorf("Clone override not used: calls = %d, data = %v", calls, c[0].Data)
+	}
+
+	p := &Payment{Amount: 10, Tax: 1}
+	if cp := DeepClone(p); cp == p || *cp != *p {
+		t.Errorf("DeepClone(Payment) = %v; expected a copy of %v", cp, p)
+	}
+}
+
+func TestDeepCloneNil(t *testing.T) {
+	var m map[string]int
+	var s []int
+	var p *cloneNode
+	var i any
+	if DeepClone(m) != nil || DeepClone(s) != nil || DeepClone(p) != nil || DeepClone(i) != nil {
+		t.Errorf("nil values must be cloned as nil")
+	}
+	// A nil interface field whose type has a Clone method.
+	if d := DeepClone(cloneDrawing{}); d.Shape != nil {
+		t.Errorf("nil interface fields must be cloned as nil, got %v", d.Shape)
+	}
+	if DeepClone(42) != 42 || DeepClone("abc") != "abc" {
+		t.Errorf("basic values must be copied")
+	}
+}
+
+func TestPrototypeRegistry(t *testing.T) {
+	r := NewPrototypeRegistry[cloneCustomer]()
+	proto := newCloneCustomer()
+	r.Register("vip", *proto)
+	proto.Address.Street = "changed after register"
+
+	c, err := r.Clone("vip", func(c *cloneCustomer) {
+		c.Name = "Jane"
+		c.Address.Street = "Second St"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "Jane" || c.Address.Street != "Second St" {
+		t.Errorf("customization not applied: %+v", c)
+	}
+	c2, _ := r.Clone("vip")
+	if c2.Name != "John" || c2.Address.Street != "Main St" {
+		t.Errorf("prototype was changed: %+v", c2)
+	}
+	if _, err := r.Clone("missing"); !errors.Is(err, ErrPrototypeNotFound) {
+		t.Errorf("Clone(missing) = %v; expected %v", err, ErrPrototypeNotFound)
+	}
+}
+
+// Clone Benchmarks
+// The benchmarks below compare the reflection-based DeepClone with a hand-written deep copy.
+func handCloneCustomer(c *cloneCustomer) *cloneCustomer {
+	n := &cloneCustomer{Name: c.Name, Created: c.Created}
+	if c.Address != nil {
+		n.Address = &cloneAddress{Street: c.Address.Street, Lines: append([]string(nil), c.Address.Lines...)}
+	}
+	if c.Tags != nil {
+		n.Tags = make(map[string][]int, len(c.Tags))
+		for k, v := range c.Tags {
+			n.Tags[k] = append([]int(nil), v...)
+		}
+	}
+	for i, s := range c.Scores {
+		if s != nil {
+			v := *s
+			n.Scores[i] = &v
+		}
+	}
+	if a, ok := c.Extra.(*cloneAddress); ok {
+		n.Extra = &cloneAddress{Street: a.Street, Lines: append([]string(nil), a.Lines...)}
+	}
+	if c.secret != nil {
+		s := *c.secret
+		n.secret = &s
+	}
+	return n
+}
+
+func BenchmarkDeepClone(b *testing.B) {
+	c := newCloneCustomer()
+	b.ReportAllocs()
+	for b.Loop() {
+		DeepClone(c)
+	}
+}
+
+func BenchmarkHandClone(b *testing.B) {
+	c := newCloneCustomer()
+	b.ReportAllocs()
+	for b.Loop() {
+		handCloneCustomer(c)
+	}
+}
+
+func BenchmarkPaymentClone(b *testing.B) {
+	p := &Payment{Amount: 100, Tax: 10}
+	b.Run("DeepClone", func(b *testing.B) {
+		for b.Loop() {
+			DeepClone(p)
+		}
+	})
+	b.Run("Clone", func(b *testing.B) {
+		for b.Loop() {
+			p.Clone()
+		}
+	})
+}
@@ -0,0 +1,296 @@
+// Dependency Injection Container
+// A dependency injection (DI) container generalizes the Singleton and the Factory patterns.
+// Instead of each type deciding how its dependencies are created, the constructors (providers) are registered in
+// a container, and the container builds the objects on demand, passing the dependencies to the constructors.
+// The lifetime of each provider defines how many instances are created:
+// - Singleton: a single instance is created, on the first request, and shared by everyone;
+// - Transient: a new instance is created on every request;
+// - Scoped: a single instance is created per scope (e.g., per HTTP request), and shared inside the scope.
+
+package creational
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Lifetime
+// The enumeration below defines the lifetimes supported by the container.
+type Lifetime int
+
+const (
+	Transient Lifetime = iota
+	Singleton
+	Scoped
+)
+
+// String Method
+// The lifetime is printed by name in the error messages.
+func (l Lifetime) String() string {
+	switch l {
+	case Transient:
+		return "transient"
+	case Singleton:
+		return "singleton"
+	case Scoped:
+		return "scoped"
+	}
+	return fmt.Sprintf("Lifetime(%d)", int(l))
+}
+
+// Container Errors
+// The errors below are returned by the container, wrapped with more details.
+// They can be checked with "errors.Is".
+var (
+	ErrInvalidProvider = errors.New("invalid provider")
+	ErrMissingProvider = errors.New("missing provider")
+	ErrDependencyCycle = errors.New("dependency cycle")
+	ErrScopeRequired   = errors.New("scoped provider resolved outside of a scope")
+)
+
+// Error Type
+// The error interface type is used to check if the constructor returns an error.
+var errorType = reflect.TypeFor[error]()
+
+// Lazy Instance
+// The lazy instance is the lazily constructed value of a singleton or scoped provider.
+// The mutex ensures that the constructor is called only once, even when many goroutines resolve it at once.
+// If the constructor fails, the error is not kept, so the next request tries again (e.g., after the missing
+// dependency is registered, or a temporary failure is over).
+type lazyInstance struct {
+	mu    sync.Mutex
+	done  bool
+	value reflect.Value
+}
+
+// Provider
+// The provider holds the constructor of a type, and the types of its parameters (dependencies).
+type provider struct {
+	typ       reflect.Type
+	ctor      reflect.Value
+	deps      []reflect.Type
+	withError bool
+	lifetime  Lifetime
+	singleton lazyInstance
+}
+
+// Container
+// The container keeps the providers indexed by the type they construct.
+type Container struct {
+	mu        sync.RWMutex
+	providers map[reflect.Type]*provider
+}
+
+// Container Constructor
+// The constructor creates an empty container.
+func NewContainer() *Container {
+	return &Container{providers: map[reflect.Type]*provider{}}
+}
+
+// Provide
+// The method below registers a constructor in the container.
+// The constructor must be a function that returns the constructed value, and optionally an error.
+// Its parameters are the dependencies, which are resolved by type when the value is constructed.
+// To register an implementation of an interface, the constructor must return the interface type.
+// Examples:
+//
+//	c.Provide(func() *Config { ... }, Singleton)
+//	c.Provide(func(cfg *Config) (Database, error) { ... }, Scoped)
+func (c *Container) Provide(ctor any, lifetime Lifetime) error {
+	v := reflect.ValueOf(ctor)
+	if !v.IsValid() || (v.Kind() == reflect.Func && v.IsNil()) {
+		return fmt.Errorf("%w: nil constructor", ErrInvalidProvider)
+	}
+	t := v.Type()
+	if t.Kind() != reflect.Func || t.IsVariadic() || t.NumOut() < 1 || t.NumOut() > 2 ||
+		(t.NumOut() == 2 && t.Out(1) != errorType) {
+		return fmt.Errorf("%w: %s must be a function returning a value and optionally an error", ErrInvalidProvider, t)
+	}
+	if lifetime < Transient || lifetime > Scoped {
+		return fmt.Errorf("%w: unknown lifetime %v", ErrInvalidProvider, lifetime)
+	}
+	p := &provider{typ: t.Out(0), ctor: v, withError: t.NumOut() == 2, lifetime: lifetime}
+	for i := range t.NumIn() {
+		p.deps = append(p.deps, t.In(i))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.providers[p.typ] = p
+	return nil
+}
+
+// Must Provide
+// The method below registers a constructor, and panics if it is invalid.
+// It is useful when the providers are registered at startup, where an invalid provider is a programming error.
+func (c *Container) MustProvide(ctor any, lifetime Lifetime) {
+	if err := c.Provide(ctor, lifetime); err != nil {
+		panic(err)
+	}
+}
+
+// Scope
+// The scope keeps the instances of the scoped providers.
+// It is usually created for a unit of work, such as an HTTP request, and discarded at the end of it.
+type Scope struct {
+	container *Container
+	mu        sync.Mutex
+	instances map[reflect.Type]*lazyInstance
+}
+
+// New Scope
+// The method below creates a new scope, that resolves the providers of the container.
+func (c *Container) NewScope() *Scope {
+	return &Scope{container: c, instances: map[reflect.Type]*lazyInstance{}}
+}
+
+// Resolver
+// The resolver interface is implemented by the container and by the scopes.
+// The scoped providers can only be resolved by a scope.
+type Resolver interface {
+	resolve(t reflect.Type, path []reflect.Type) (reflect.Value, error)
+}
+
+// Resolver Implementation
+// The container resolves the types without a scope, and the scope resolves the types within itself.
+func (c *Container) resolve(t reflect.Type, path []reflect.Type) (reflect.Value, error) {
+	return c.build(t, nil, path)
+}
+func (s *Scope) resolve(t reflect.Type, path []reflect.Type) (reflect.Value, error) {
+	return s.container.build(t, s, path)
+}
+
+// Build
+// The method below constructs a value of the given type.
+// The path holds the types being constructed, from the requested type to the current dependency.
+// If the type is already in the path, the dependencies form a cycle, and the container reports it.
+// Note that the singletons are always built without a scope, so they cannot capture scoped instances.
+func (c *Container) build(t reflect.Type, scope *Scope, path []reflect.Type) (reflect.Value, error) {
+	for i, p := range path {
+		if p == t {
+			return reflect.Value{}, fmt.Errorf("%w: %s", ErrDependencyCycle, formatPath(append(path[i:], t)))
+		}
+	}
+	path = append(path, t)
+	c.mu.RLock()
+	p, ok := c.providers[t]
+	c.mu.RUnlock()
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("%w for %s (resolving %s)", ErrMissingProvider, t, formatPath(path))
+	}
+	switch p.lifetime {
+	case Singleton:
+		return c.construct(p, &p.singleton, nil, path)
+	case Scoped:
+		if scope == nil {
+			return reflect.Value{}, fmt.Errorf("%w: %s (resolving %s)", ErrScopeRequired, t, formatPath(path))
+		}
+		scope.mu.Lock()
+		inst, ok := scope.instances[t]
+		if !ok {
+			inst = &lazyInstance{}
+			scope.instances[t] = inst
+		}
+		scope.mu.Unlock()
+		return c.construct(p, inst, scope, path)
+	}
+	return c.call(p, scope, path)
+}
+
+// Construct
+// The method below constructs the instance once, and returns the same value afterward.
+// Only the successful constructions are kept.
+func (c *Container) construct(p *provider, inst *lazyInstance, scope *Scope, path []reflect.Type) (reflect.Value, error) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	if inst.done {
+		return inst.value, nil
+	}
+	v, err := c.call(p, scope, path)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	inst.value, inst.done = v, true
+	return v, nil
+}
+
+// Call
+// The method below resolves the dependencies of the provider, and calls its constructor.
+func (c *Container) call(p *provider, scope *Scope, path []reflect.Type) (reflect.Value, error) {
+	args := make([]reflect.Value, len(p.deps))
+	for i, dep := range p.deps {
+		v, err := c.build(dep, scope, path)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		args[i] = v
+	}
+	out := p.ctor.Call(args)
+	if p.withError && !out[1].IsNil() {
+		return reflect.Value{}, fmt.Errorf("construct %s: %w", p.typ, out[1].Interface().(error))
+	}
+	return out[0], nil
+}
+
+// Format Path
+// The function below formats the resolution path, e.g.: "*App -> Database -> *Config".
+func formatPath(path []reflect.Type) string {
+	names := make([]string, len(path))
+	for i, t := range path {
+		names[i] = t.String()
+	}
+	return strings.Join(names, " -> ")
+}
+
+// Resolve
+// The generic function below resolves a value of type T from a container or a scope.
+// Go does not allow type parameters in methods, so it is declared as a function.
+func Resolve[T any](r Resolver) (T, error) {
+	var zero T
+	v, err := r.resolve(reflect.TypeFor[T](), nil)
+	if err != nil {
+		return zero, err
+	}
+	res, _ := v.Interface().(T) // The assertion fails only for nil interfaces, returning the zero value
+	return res, nil
+}
+
+// Must Resolve
+// The function below resolves a value of type T, and panics if it cannot be resolved.
+func MustResolve[T any](r Resolver) T {
+	v, err := Resolve[T](r)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Test Container
+// The function below registers a small application in the container.
+// The application depends on a repository, which depends on the configuration.
+func TestContainer() {
+	type (
+		Config     struct{ DSN string }
+		Repository struct{ Config *Config }
+		App        struct{ Repository *Repository }
+	)
+	c := NewContainer()
+	c.MustProvide(func() *Config { return &Config{DSN: "memory://"} }, Singleton)
+	c.MustProvide(func(cfg *Config) *Repository { return &Repository{Config: cfg} }, Scoped)
+	c.MustProvide(func(repo *Repository) *App { return &App{Repository: repo} }, Transient)
+
+	// Resolving in a Scope
+	// Both applications share the repository, since they were resolved in the same scope.
+	scope := c.NewScope()
+	app1 := MustResolve[*App](scope)
+	app2 := MustResolve[*App](scope)
+	fmt.Println(app1 == app2, app1.Repository == app2.Repository) // Output: false true
+	fmt.Println(app1.Repository.Config.DSN)                       // Output: memory://
+
+	// Resolving Outside a Scope
+	// The repository is scoped, so it cannot be resolved by the container itself.
+	_, err := Resolve[*App](c)
+	fmt.Println(errors.Is(err, ErrScopeRequired)) // Output: true
+}
@@ -0,0 +1,196 @@
+package creational
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type (
+	testConfig struct{ Name string }
+	testRepo   struct{ Config *testConfig }
+	testApp    struct{ Repo *testRepo }
+	testCycleA struct{}
+	testCycleB struct{}
+	testCycleC struct{}
+)
+
+func TestContainerLifetimes(t *testing.T) {
+	c := NewContainer()
+	c.MustProvide(func() *testConfig { return &testConfig{Name: "cfg"} }, Singleton)
+	c.MustProvide(func(cfg *testConfig) *testRepo { return &testRepo{Config: cfg} }, Scoped)
+	c.MustProvide(func(repo *testRepo) *testApp { return &testApp{Repo: repo} }, Transient)
+
+	s1, s2 := c.NewScope(), c.NewScope()
+	a1 := MustResolve[*testApp](s1)
+	a2 := MustResolve[*testApp](s1)
+	a3 := MustResolve[*testApp](s2)
+	if a1 == a2 {
+		t.Errorf("transient providers must return a new instance on each request")
+	}
+	if a1.Repo != a2.Repo {
+		t.Errorf("scoped providers must return the same instance inside a scope")
+	}
+	if a1.Repo == a3.Repo {
+		t.Errorf("scoped providers must return different instances for different scopes")
+	}
+	if a1.Repo.Config != a3.Repo.Config || a1.Repo.Config != MustResolve[*testConfig](c) {
+		t.Errorf("singleton providers must return the same instance everywhere")
+	}
+}
+
+func TestContainerSingletonConcurrent(t *testing.T) {
+	var calls atomic.Int32
+	c := NewContainer()
+	c.MustProvide(func() *testConfig {
+		calls.Add(1)
+		return &testConfig{}
+	}, Singleton)
+	var wg sync.WaitGroup
+	results := make([]*testConfig, 100)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = MustResolve[*testConfig](c)
+		}()
+	}
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Errorf("constructor called %d times; expected 1", calls.Load())
+	}
+	for _, r := range results {
+		if r != results[0] {
+			t.Fatalf("goroutines resolved different singleton instances")
+		}
+	}
+}
+
+func TestContainerMissingProvider(t *testing.T) {
+	c := NewContainer()
+	c.MustProvide(func(repo *testRepo) *testApp { return &testApp{Repo: repo} }, Transient)
+	c.MustProvide(func(cfg *testConfig) *testRepo { return &testRepo{Config: cfg} }, Transient)
+	_, err := Resolve[*testApp](c)
+	if !errors.Is(err, ErrMissingProvider) {
+		t.Fatalf("Resolve = %v; expected %v", err, ErrMissingProvider)
+	}
+	want := "missing provider for *creational.testConfig (resolving *creational.testApp -> *creational.testRepo -> *creational.testConfig)"
+	if err.Error() != want {
+		t.Errorf("error = %q; expected %q", err, want)
+	}
+}
+
+func TestContainerCycle(t *testing.T) {
+	c := NewContainer()
+	c.MustProvide(func(*testCycleB) *testCycleA { return &testCycleA{} }, Singleton)
+	c.MustProvide(func(*testCycleC) *testCycleB { return &testCycleB{} }, Transient)
+	c.MustProvide(func(*testCycleA) *testCycleC { return &testCycleC{} }, Transient)
+	_, err := Resolve[*testCycleB](c)
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("Resolve = %v; expected %v", err, ErrDependencyCycle)
+	}
+	if !strings.Contains(err.Error(), "*creational.testCycleB -> *creational.testCycleC -> *creational.testCycleA -> *creational.testCycleB") {
+		t.Errorf("error = %q; expected the cycle path", err)
+	}
+}
+
+func TestContainerScopeRequired(t *testing.T) {
+	c := NewContainer()
+	c.MustProvide(func() *testConfig { return &testConfig{} }, Scoped)
+	c.MustProvide(func(cfg *testConfig) *testRepo { return &testRepo{Config: cfg} }, Singleton)
+	if _, err := Resolve[*testConfig](c); !errors.Is(err, ErrScopeRequired) {
+		t.Errorf("Resolve = %v; expected %v", err, ErrScopeRequired)
+	}
+	// Singletons must not capture scoped instances, even when resolved from a scope.
+	if _, err := Resolve[*testRepo](c.NewScope()); !errors.Is(err, ErrScopeRequired) {
+		t.Errorf("Resolve = %v; expected %v", err, ErrScopeRequired)
+	}
+}
+
+func TestContainerConstructorError(t *testing.T) {
+	errBoom := errors.New("boom")
+	calls := 0
+	c := NewContainer()
+	c.MustProvide(func() (*testConfig, error) {
+		calls++
+		if calls < 3 {
+			return nil, errBoom
+		}
+		return &testConfig{}, nil
+	}, Singleton)
+	for range 2 {
+		if _, err := Resolve[*testConfig](c); !errors.Is(err, errBoom) {
+			t.Errorf("Resolve = %v; expected %v", err, errBoom)
+		}
+	}
+	a, err := Resolve[*testConfig](c)
+	b, _ := Resolve[*testConfig](c)
+	if err != nil || a != b || calls != 3 {
+		t.Errorf("failed singletons must be constructed again: %v, calls = %d", err, calls)
+	}
+}
+
+// A singleton that failed because of a missing dependency is resolved after the dependency is registered.
+func TestContainerLateDependency(t *testing.T) {
+	c := NewContainer()
+	c.MustProvide(func(cfg *testConfig) *testRepo { return &testRepo{Config: cfg} }, Singleton)
+	if _, err := Resolve[*testRepo](c); !errors.Is(err, ErrMissingProvider) {
+		t.Fatalf("Resolve = %v; expected %v", err, ErrMissingProvider)
+	}
+	c.MustProvide(func() *testConfig { return &testConfig{Name: "late"} }, Singleton)
+	if repo, err := Resolve[*testRepo](c); err != nil || repo.Config.Name != "late" {
+		t.Errorf("Resolve = %v, %v; expected the repository", repo, err)
+	}
+}
+
+func TestContainerInvalidProvider(t *testing.T) {
+	c := NewContainer()
+	for _, ctor := range []any{
+		42,
+		func() {},
+		func() (int, int) { return 0, 0 },
+		func(...int) int { return 0 },
+		nil,
+		(func() int)(nil),
+	} {
+		if err := c.Provide(ctor, Singleton); !errors.Is(err, ErrInvalidProvider) {
+			t.Errorf("Provide(%T) = %v; expected %v", ctor, err, ErrInvalidProvider)
+		}
+	}
+	if err := c.Provide(func() int { return 0 }, Lifetime(9)); !errors.Is(err, ErrInvalidProvider) {
+		t.Errorf("Provide with unknown lifetime = %v; expected %v", err, ErrInvalidProvider)
+	}
+}
+
+func TestContainerInterfaceBinding(t *testing.T) {
+	c := NewContainer()
+	c.MustProvide(func() WidgetFactory { return &FlatFactory{} }, Singleton)
+	c.MustProvide(NewToolbar, Transient)
+	if got := MustResolve[*Toolbar](c).Buttons[0].Markup; got != "[New]" {
+		t.Errorf("Markup = %q; expected %q", got, "[New]")
+	}
+	c.MustProvide(func() Form { return nil }, Transient)
+	if f, err := Resolve[Form](c); f != nil || err != nil {
+		t.Errorf("Resolve = %v, %v; expected a nil interface", f, err)
+	}
+}
+
+func TestNewServiceManager(t *testing.T) {
+	var wg sync.WaitGroup
+	managers := make([]*ServiceManager, 10)
+	for i := range managers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			managers[i] = NewServiceManager()
+		}()
+	}
+	wg.Wait()
+	for _, m := range managers {
+		if m != managers[0] || m.ID != 1 {
+			t.Fatalf("NewServiceManager returned different instances")
+		}
+	}
+}
@@ -5,52 +5,85 @@
 
 package creational
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Model
 // The model below is a struct that represents an Input.
-// It contains a Markup field that represents the input's markup.
+// It contains a Markup field that represents the input's markup (the text box).
 // This input can have different styles, such as flat or rounded.
+// The input is a Widget (see widgets.go), so it can be rendered in a terminal.
 type Input struct {
+	Label  string
 	Markup string
 }
 
+// Widget Implementation
+// The input is rendered in a single line, with the label before the text box.
+func (i *Input) Lines() []string {
+	return []string{i.Label + ": " + i.Markup}
+}
+
 // Interface
 // The interface below defines what consists of a form.
 // It looks similar to the abstract factory, but it is a component itself, not a factory.
 // The CreateInput method is called "Factory Method".
 type Form interface {
-	CreateInput() *Input
+	CreateInput(label string, width int) *Input
 }
 
 // Concrete Forms
 // The structs below are concrete forms that implement the Form interface.
+// They are also embedded by the widget factories (see abstractfactory.go), which reuse their factory method.
 type (
-	FlatForm    struct{}
-	RoundedForm struct{}
+	FlatForm       struct{}
+	RoundedForm    struct{}
+	DoubleLineForm struct{}
 )
 
 // Form Implementations
 // The functions below are implementations of a form.
 // Note that the factory method is implemented with different logic for each form.
-func (f *FlatForm) CreateInput() *Input {
-	return &Input{Markup: "[_____]"}
+func (f *FlatForm) CreateInput(label string, width int) *Input {
+	return &Input{Label: label, Markup: "[" + inputField(width) + "]"}
 }
-func (f *RoundedForm) CreateInput() *Input {
-	return &Input{Markup: "(_____)"}
+func (f *RoundedForm) CreateInput(label string, width int) *Input {
+	return &Input{Label: label, Markup: "(" + inputField(width) + ")"}
+}
+func (f *DoubleLineForm) CreateInput(label string, width int) *Input {
+	return &Input{Label: label, Markup: "⟦" + inputField(width) + "⟧"}
+}
+
+// inputField returns the empty field of an input. A negative width is treated as zero.
+func inputField(width int) string {
+	return strings.Repeat("_", max(0, width))
 }
 
 // Render
 // This function renders the form.
-// It takes a Form interface as an argument and calls the CreateInput method to get the input markup.
-func RenderForm(form Form) {
-	input := form.CreateInput()
-	println("Enter your name:", input.Markup)
+// It takes a Form interface as an argument and calls the CreateInput method to get an input for each label.
+// The function does not know which concrete input is created, only the form does.
+func RenderForm(form Form, labels ...string) string {
+	var sb strings.Builder
+	for _, label := range labels {
+		sb.WriteString(strings.Join(form.CreateInput(label, 5).Lines(), "\n"))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
 }
 
 // Test Factory Method
 // The function below is a test for the factory method pattern.
+// The form can also be provided by the container, so the consumer does not reference the concrete forms.
 func TestFactoryMethod() {
 	flatForm := &FlatForm{}
 	roundedForm := &RoundedForm{}
-	RenderForm(flatForm)    // Output: Enter your name: [_____]
-	RenderForm(roundedForm) // Output: Enter your name: (_____)
+	fmt.Print(RenderForm(flatForm, "Name"))    // Output: Name: [_____]
+	fmt.Print(RenderForm(roundedForm, "Name")) // Output: Name: (_____)
+
+	c := NewContainer()
+	c.MustProvide(func() Form { return &DoubleLineForm{} }, Transient)
+	fmt.Print(RenderForm(MustResolve[Form](c), "Name")) // Output: Name: ⟦_____⟧
 }
@@ -0,0 +1,56 @@
+// Code generated by "buildergen -type=Person -name=GeneratedPersonBuilder"; DO NOT EDIT.
+
+package creational
+
+import "errors"
+
+// GeneratedPersonBuilder builds Person values.
+// Each With method sets a field and returns the builder, so the calls can be chained.
+type GeneratedPersonBuilder struct {
+	value Person
+}
+
+// NewGeneratedPersonBuilder returns a builder with the default values.
+func NewGeneratedPersonBuilder() *GeneratedPersonBuilder {
+	b := &GeneratedPersonBuilder{}
+	b.value.Role = "user"
+	return b
+}
+
+// WithID sets the ID field.
+func (b *GeneratedPersonBuilder) WithID(id int) *GeneratedPersonBuilder {
+	b.value.ID = id
+	return b
+}
+
+// WithName sets the Name field.
+func (b *GeneratedPersonBuilder) WithName(name string) *GeneratedPersonBuilder {
+	b.value.Name = name
+	return b
+}
+
+// WithEmail sets the Email field.
+func (b *GeneratedPersonBuilder) WithEmail(email string) *GeneratedPersonBuilder {
+	b.value.Email = email
+	return b
+}
+
+// WithRole sets the Role field.
+func (b *GeneratedPersonBuilder) WithRole(role string) *GeneratedPersonBuilder {
+	b.value.Role = role
+	return b
+}
+
+// Build returns a copy of the built Person.
+// It reports all the missing required fields and validation errors together.
+func (b *GeneratedPersonBuilder) Build() (*Person, error) {
+	var errs []error
+	v := b.value
+	if err := v.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
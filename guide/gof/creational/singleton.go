@@ -14,20 +14,40 @@ type ServiceManager struct {
 	ID int
 }
 
-// Singleton Instance
-// Declaring a package-level variable to hold the single instance of the Singleton struct.
+// Naive Singleton
+// The simplest implementation uses a package-level variable, and creates the instance if it is nil.
+// However, this is not safe for concurrent use: two goroutines can see the nil instance at the same time,
+// and both will create their own instance (data race).
+var _ = `
 var instance *ServiceManager
 
-// Singleton Constructor
-// Since we need only one instance of the ServiceManager struct, we will create a constructor function
-// that returns the instance.
-// If the instance is nil, we will create a new instance of the ServiceManager struct.
 func NewServiceManager() *ServiceManager {
 	if instance == nil {
 		instance = &ServiceManager{ID: 1}
 	}
 	return instance
 }
+`
+
+// Services
+// Instead, we will register the ServiceManager constructor in a dependency injection container (see container.go)
+// with the Singleton lifetime.
+// The container creates the instance lazily, on the first request, and it is safe for concurrent use.
+var services = NewContainer()
+
+// Registering the Singleton
+// The init function registers the constructor before the package is used.
+func init() {
+	services.MustProvide(func() *ServiceManager { return &ServiceManager{ID: 1} }, Singleton)
+}
+
+// Singleton Constructor
+// Since we need only one instance of the ServiceManager struct, the constructor returns the instance
+// resolved from the container.
+// The instance is the same for every call, and for every goroutine.
+func NewServiceManager() *ServiceManager {
+	return MustResolve[*ServiceManager](services)
+}
 
 // Test Singleton
 // We can check if the instance is the same by comparing the IDs of the instances returned by GetInstance.
@@ -41,4 +61,5 @@ func TestSingleton() {
 	// Check
 	// We can see that both instances have the same ID, which means they are indeed the same instance.
 	fmt.Println("Singleton Instance IDs:", sm1.ID, sm2.ID) // Output: Singleton Instance IDs: 1 1
+	fmt.Println("Same Instance:", sm1 == sm2)              // Output: Same Instance: true
 }
@@ -0,0 +1,221 @@
+// Widgets
+// The widgets created by the factories (see abstractfactory.go) are rendered as lines of text.
+// The layout helpers below place widgets side by side or stacked, and the terminal buffer draws them at
+// any position of the screen, keeping their ANSI colors.
+
+package creational
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Widget
+// The interface below is implemented by all the widgets.
+// The lines can contain ANSI escape sequences, which do not take space on the screen.
+type Widget interface {
+	Lines() []string
+}
+
+// Text
+// The text is the simplest widget, with one line per line of the string.
+// It is the same for every theme, so it is not created by the factories.
+type Text string
+
+func (t Text) Lines() []string {
+	return strings.Split(string(t), "\n")
+}
+
+// Stack
+// The stack is a layout widget, which places its children vertically or horizontally.
+// The gap is the number of empty lines (or columns) between the children.
+type Stack struct {
+	Horizontal bool
+	Gap        int
+	Children   []Widget
+}
+
+// Stack Constructors
+// The functions below create vertical and horizontal stacks.
+func VStack(gap int, children ...Widget) *Stack {
+	return &Stack{Gap: gap, Children: children}
+}
+func HStack(gap int, children ...Widget) *Stack {
+	return &Stack{Horizontal: true, Gap: gap, Children: children}
+}
+
+// Stack Implementation
+// Vertical stacks append the lines of the children.
+// Horizontal stacks join the lines of the children, padding each one to its width.
+func (s *Stack) Lines() []string {
+	var lines []string
+	if !s.Horizontal {
+		for i, c := range s.Children {
+			if i > 0 {
+				lines = append(lines, make([]string, s.Gap)...)
+			}
+			lines = append(lines, c.Lines()...)
+		}
+		return lines
+	}
+	sep := strings.Repeat(" ", s.Gap)
+	for i, c := range s.Children {
+		child := c.Lines()
+		width := 0
+		for _, line := range lines {
+			width = max(width, visibleWidth(line))
+		}
+		for len(lines) < len(child) {
+			lines = append(lines, "")
+		}
+		for j := range child {
+			if i > 0 {
+				lines[j] = pad(lines[j], width) + sep
+			}
+			lines[j] += child[j]
+		}
+	}
+	return lines
+}
+
+// Visible Width
+// The function below returns the number of columns used by the text, ignoring the ANSI escape sequences.
+func visibleWidth(s string) int {
+	return utf8.RuneCountInString(stripANSI(s))
+}
+
+// Strip ANSI
+// The function below removes the ANSI escape sequences (e.g., "\x1b[32m") from the text.
+func stripANSI(s string) string {
+	var sb strings.Builder
+	for {
+		i := strings.Index(s, "\x1b[")
+		if i < 0 {
+			break
+		}
+		sb.WriteString(s[:i])
+		end := strings.IndexByte(s[i:], 'm')
+		if end < 0 {
+			return sb.String()
+		}
+		s = s[i+end+1:]
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
+
+// Pad
+// The function below appends spaces to the text until it has the width.
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-visibleWidth(s)))
+}
+
+// Terminal Buffer
+// The buffer below represents the terminal screen, with a fixed number of rows and columns.
+// Each cell keeps its rune and its style (ANSI escape sequences), so widgets can be drawn over each other.
+type TerminalBuffer struct {
+	Width, Height int
+	cells         []cell
+}
+type cell struct {
+	r     rune
+	style string
+}
+
+// Buffer Constructor
+// The constructor creates a buffer filled with spaces.
+func NewTerminalBuffer(width, height int) *TerminalBuffer {
+	cells := make([]cell, width*height)
+	for i := range cells {
+		cells[i].r = ' '
+	}
+	return &TerminalBuffer{Width: width, Height: height, cells: cells}
+}
+
+// Draw
+// The method below draws the widget with its top-left corner at the column x and row y.
+// The parts of the widget outside the buffer are clipped.
+func (b *TerminalBuffer) Draw(x, y int, w Widget) {
+	for row, line := range w.Lines() {
+		col, style := x, ""
+		for len(line) > 0 {
+			if strings.HasPrefix(line, "\x1b[") {
+				end := strings.IndexByte(line, 'm')
+				if end < 0 {
+					break
+				}
+				if seq := line[:end+1]; seq == ansiReset {
+					style = ""
+				} else {
+					style += seq
+				}
+				line = line[end+1:]
+				continue
+			}
+			r, size := utf8.DecodeRuneInString(line)
+			line = line[size:]
+			if cy := y + row; col >= 0 && col < b.Width && cy >= 0 && cy < b.Height {
+				b.cells[cy*b.Width+col] = cell{r: r, style: style}
+			}
+			col++
+		}
+	}
+}
+
+// String Method
+// The method below returns the content of the buffer, with a line per row.
+// The style is written only when it changes, and it is reset at the end of each row.
+// The trailing spaces of each row are removed.
+func (b *TerminalBuffer) String() string {
+	var sb strings.Builder
+	for y := range b.Height {
+		row := b.cells[y*b.Width : (y+1)*b.Width]
+		end := len(row)
+		for end > 0 && row[end-1] == (cell{r: ' '}) {
+			end--
+		}
+		style := ""
+		for _, c := range row[:end] {
+			if c.style != style {
+				if style != "" {
+					sb.WriteString(ansiReset)
+				}
+				sb.WriteString(c.style)
+				style = c.style
+			}
+			sb.WriteRune(c.r)
+		}
+		if style != "" {
+			sb.WriteString(ansiReset)
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// Render Widget
+// The function below draws the widget in a buffer with the same size, and returns its content.
+func RenderWidget(w Widget) string {
+	lines := w.Lines()
+	width := 0
+	for _, line := range lines {
+		width = max(width, visibleWidth(line))
+	}
+	b := NewTerminalBuffer(width, len(lines))
+	b.Draw(0, 0, w)
+	return b.String()
+}
+
+// Login Form
+// The function below creates a login form with the factory.
+// It does not reference any concrete widget factory, so the same form can be rendered with any theme.
+func LoginForm(f WidgetFactory) Widget {
+	return f.CreatePanel("Login",
+		f.CreateInput("Username", 10),
+		f.CreateInput("Password", 10),
+		f.CreateCheckbox("Remember me", true),
+		f.CreateCheckbox("Stay signed in", false),
+		Text(""),
+		HStack(1, f.CreateButton("Sign in"), f.CreateButton("Cancel")),
+	)
+}
@@ -0,0 +1,73 @@
+package creational
+
+import (
+	"errors"
+	"testing"
+
+	"guide/internal/golden"
+)
+
+func TestWidgetThemes(t *testing.T) {
+	for _, theme := range []string{"flat", "rounded", "double", "ansi"} {
+		t.Run(theme, func(t *testing.T) {
+			f, err := NewWidgetFactory(theme)
+			if err != nil {
+				t.Fatal(err)
+			}
+			screen := NewTerminalBuffer(40, 12)
+			screen.Draw(0, 0, NewToolbar(f))
+			screen.Draw(2, 2, LoginForm(f))
+			golden.Assert(t, "widgets_"+theme, []byte(screen.String()))
+		})
+	}
+	if _, err := NewWidgetFactory("neon"); !errors.Is(err, ErrUnknownTheme) {
+		t.Errorf("NewWidgetFactory(neon) = %v; expected %v", err, ErrUnknownTheme)
+	}
+}
+
+// Widgets from the ANSI factory must have the same layout as the widgets from its base factory.
+func TestANSIFactoryLayout(t *testing.T) {
+	for _, base := range []WidgetFactory{&FlatFactory{}, &RoundedFactory{}, &DoubleLineFactory{}} {
+		plain := RenderWidget(LoginForm(base))
+		colored := RenderWidget(LoginForm(&ANSIFactory{Base: base}))
+		if colored == plain {
+			t.Errorf("%T: ANSI factory did not add colors", base)
+		}
+		if stripANSI(colored) != plain {
+			t.Errorf("%T: layout mismatch:\n%s\n%s", base, stripANSI(colored), plain)
+		}
+	}
+}
+
+func TestTerminalBufferClipping(t *testing.T) {
+	b := NewTerminalBuffer(4, 2)
+	b.Draw(-1, -1, Text("abcdef\n\x1b[32mghijkl\x1b[0mmn\nopq"))
+	b.Draw(3, 1, Text("xyz"))
+	want := "\x1b[32mhijk\x1b[0m\n" + "pq x\n"
+	if got := b.String(); got != want {
+		t.Errorf("String = %q; expected %q", got, want)
+	}
+}
+
+func TestStack(t *testing.T) {
+	w := HStack(2, VStack(1, Text("a"), Text("bb")), Text("c\nd"))
+	want := []string{"a   c", "    d", "bb"}
+	got := w.Lines()
+	if len(got) != len(want) {
+		t.Fatalf("Lines = %q; expected %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Lines = %q; expected %q", got, want)
+		}
+	}
+}
+
+// A negative width is treated as zero, instead of panicking.
+func TestCreateInputNegativeWidth(t *testing.T) {
+	for _, f := range []WidgetFactory{&FlatFactory{}, &RoundedFactory{}, &DoubleLineFactory{}} {
+		if got, want := f.CreateInput("Name", -3).Markup, f.CreateInput("Name", 0).Markup; got != want {
+			t.Errorf("%T: CreateInput(-3) = %q; expected %q", f, got, want)
+		}
+	}
+}
@@ -0,0 +1,497 @@
+// Caching Proxy
+// The CachedDataService from the Proxy example stores every result forever in an unbounded map.
+// Real caching proxies need to limit their size, expire old entries, avoid querying the same key
+// many times when concurrent requests arrive together, and report how well the cache is doing.
+// The generic proxy below keeps the same idea (same protocol for the proxy and the real service),
+// but works with any key and value types and has pluggable eviction policies.
+
+package structural
+
+import (
+	"container/heap"
+	"container/list"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Fetcher
+// The fetcher is the generic version of the DataAccess protocol.
+// Both the real service and the caching proxy implement it, so the consumer can use any of them.
+// Unlike DataAccess, the fetcher can fail, which allows the proxy to cache errors as well.
+type Fetcher[K comparable, V any] interface {
+	Fetch(key K) (V, error)
+}
+
+// Fetcher Function
+// The function type below allows plain functions to be used as fetchers.
+// This is the same approach used by the "http.HandlerFunc" type from the standard library.
+type FetcherFunc[K comparable, V any] func(key K) (V, error)
+
+// Fetcher Function Implementation
+// The function simply calls itself.
+func (f FetcherFunc[K, V]) Fetch(key K) (V, error) {
+	return f(key)
+}
+
+// Eviction Policy
+// The eviction policy decides which entry must leave the cache when it is full.
+// The proxy notifies the policy when entries are added, accessed and removed, and asks it for a victim
+// when there is no space left for a new entry.
+// The policies are not safe for concurrent use, since the proxy calls them while holding its own lock.
+type EvictionPolicy[K comparable] interface {
+	Add(key K, expiresAt time.Time)
+	Touch(key K)
+	Remove(key K)
+	Victim() (K, bool)
+}
+
+// LRU Policy
+// The Least Recently Used policy evicts the entry that was not accessed for the longest time.
+// It keeps the keys in a linked list ordered by access, where the front is the most recent key.
+type LRUPolicy[K comparable] struct {
+	order *list.List
+	items map[K]*list.Element
+}
+
+// LRU Policy Constructor
+// The constructor initializes the internal list and index.
+func NewLRUPolicy[K comparable]() *LRUPolicy[K] {
+	return &LRUPolicy[K]{
+		order: list.New(),
+		items: map[K]*list.Element{},
+	}
+}
+
+// LRU Policy Implementation
+// Adding and touching a key moves it to the front of the list, so the victim is always at the back.
+func (p *LRUPolicy[K]) Add(key K, _ time.Time) {
+	if e, ok := p.items[key]; ok {
+		p.order.MoveToFront(e)
+		return
+	}
+	p.items[key] = p.order.PushFront(key)
+}
+func (p *LRUPolicy[K]) Touch(key K) {
+	if e, ok := p.items[key]; ok {
+		p.order.MoveToFront(e)
+	}
+}
+func (p *LRUPolicy[K]) Remove(key K) {
+	if e, ok := p.items[key]; ok {
+		p.order.Remove(e)
+		delete(p.items, key)
+	}
+}
+func (p *LRUPolicy[K]) Victim() (K, bool) {
+	e := p.order.Back()
+	if e == nil {
+		var zero K
+		return zero, false
+	}
+	return e.Value.(K), true
+}
+
+// Ranked Entry
+// The LFU and TTL policies keep their keys in a binary heap ordered by a rank.
+// The sequence number breaks ties, so older entries are evicted first when ranks are equal.
+type rankedEntry[K comparable] struct {
+	key   K
+	rank  int64
+	seq   int64
+	index int
+}
+
+// Ranked Heap
+// The type below implements "heap.Interface" for the ranked entries.
+type rankedHeap[K comparable] []*rankedEntry[K]
+
+func (h rankedHeap[K]) Len() int { return len(h) }
+func (h rankedHeap[K]) Less(i, j int) bool {
+	if h[i].rank != h[j].rank {
+		return h[i].rank < h[j].rank
+	}
+	return h[i].seq < h[j].seq
+}
+func (h rankedHeap[K]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *rankedHeap[K]) Push(x any) {
+	e := x.(*rankedEntry[K])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+func (h *rankedHeap[K]) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return e
+}
+
+// Ranked Policy
+// The ranked policy is shared by the LFU and TTL policies.
+// The rank function defines the rank of a key when it is added, and the touch function updates it on access.
+type rankedPolicy[K comparable] struct {
+	heap  rankedHeap[K]
+	items map[K]*rankedEntry[K]
+	seq   int64
+	rank  func(expiresAt time.Time) int64
+	touch func(e *rankedEntry[K])
+}
+
+func (p *rankedPolicy[K]) Add(key K, expiresAt time.Time) {
+	p.seq++
+	if e, ok := p.items[key]; ok {
+		e.rank = p.rank(expiresAt)
+		e.seq = p.seq
+		heap.Fix(&p.heap, e.index)
+		return
+	}
+	e := &rankedEntry[K]{key: key, rank: p.rank(expiresAt), seq: p.seq}
+	p.items[key] = e
+	heap.Push(&p.heap, e)
+}
+func (p *rankedPolicy[K]) Touch(key K) {
+	if e, ok := p.items[key]; ok && p.touch != nil {
+		p.seq++
+		e.seq = p.seq
+		p.touch(e)
+		heap.Fix(&p.heap, e.index)
+	}
+}
+func (p *rankedPolicy[K]) Remove(key K) {
+	if e, ok := p.items[key]; ok {
+		heap.Remove(&p.heap, e.index)
+		delete(p.items, key)
+	}
+}
+func (p *rankedPolicy[K]) Victim() (K, bool) {
+	if len(p.heap) == 0 {
+		var zero K
+		return zero, false
+	}
+	return p.heap[0].key, true
+}
+
+// LFU Policy
+// The Least Frequently Used policy evicts the entry with the lowest number of accesses.
+// When two entries have the same frequency, the least recently used one is evicted.
+type LFUPolicy[K comparable] struct {
+	rankedPolicy[K]
+}
+
+// LFU Policy Constructor
+// Every entry starts with a frequency of one, and each access increments it.
+func NewLFUPolicy[K comparable]() *LFUPolicy[K] {
+	return &LFUPolicy[K]{rankedPolicy[K]{
+		items: map[K]*rankedEntry[K]{},
+		rank:  func(time.Time) int64 { return 1 },
+		touch: func(e *rankedEntry[K]) { e.rank++ },
+	}}
+}
+
+// TTL Policy
+// The Time To Live policy evicts the entry that is closest to expiring.
+// Entries without expiration are ranked last, and accesses do not change the order.
+type TTLPolicy[K comparable] struct {
+	rankedPolicy[K]
+}
+
+// TTL Policy Constructor
+// The rank of an entry is its expiration time.
+func NewTTLPolicy[K comparable]() *TTLPolicy[K] {
+	return &TTLPolicy[K]{rankedPolicy[K]{
+		items: map[K]*rankedEntry[K]{},
+		rank: func(expiresAt time.Time) int64 {
+			if expiresAt.IsZero() {
+				return 1<<63 - 1
+			}
+			return expiresAt.UnixNano()
+		},
+	}}
+}
+
+// Cache Options
+// The options below configure the caching proxy.
+// A zero Capacity means the cache is unbounded, and a zero TTL means the entries never expire.
+// A zero NegativeTTL disables negative caching, so errors are never cached.
+// The Now function can be replaced to control the clock in tests.
+type CacheOptions[K comparable] struct {
+	Capacity    int
+	TTL         time.Duration
+	NegativeTTL time.Duration
+	Policy      EvictionPolicy[K]
+	Now         func() time.Time
+}
+
+// Cache Stats
+// The stats below are recorded by the proxy to measure how well the cache is doing.
+// Shared counts the calls that waited for a fetch already in flight instead of fetching again.
+// NegativeHits counts the hits that returned a cached error.
+type CacheStats struct {
+	Hits         int64
+	Misses       int64
+	Shared       int64
+	Fetches      int64
+	Evictions    int64
+	Expirations  int64
+	NegativeHits int64
+}
+
+// Hit Ratio
+// The method below returns the ratio of hits over all lookups.
+func (s CacheStats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// String Method
+// The stats are printed in a single line to make them easy to log.
+func (s CacheStats) String() string {
+	return fmt.Sprintf("hits=%d misses=%d shared=%d fetches=%d evictions=%d expirations=%d negative=%d ratio=%.2f",
+		s.Hits, s.Misses, s.Shared, s.Fetches, s.Evictions, s.Expirations, s.NegativeHits, s.HitRatio())
+}
+
+// Cache Entry
+// The entry stores the fetched value, or the fetched error when negative caching is enabled.
+type cacheEntry[V any] struct {
+	value     V
+	err       error
+	expiresAt time.Time
+}
+
+// In-Flight Call
+// The call below represents a fetch that is in progress.
+// Concurrent lookups for the same key wait for it instead of calling the service again.
+// This is the same technique used by the "golang.org/x/sync/singleflight" package.
+// The call is invalidated when the key is invalidated during the fetch, so its result is not cached.
+type flightCall[V any] struct {
+	wg          sync.WaitGroup
+	value       V
+	err         error
+	invalidated bool
+}
+
+// Fetch Panics
+// When the fetcher panics, the goroutine that called it panics again, and the goroutines waiting for the same
+// fetch get an error wrapping ErrFetchPanic (and the panic value, when it is an error), instead of a zero value.
+var ErrFetchPanic = errors.New("fetcher panicked")
+
+// Caching Proxy
+// The proxy implements the Fetcher interface, and has a reference to the real fetcher.
+// All the state is guarded by a mutex, so the proxy is safe for concurrent use.
+type CachingProxy[K comparable, V any] struct {
+	fetcher Fetcher[K, V]
+	opts    CacheOptions[K]
+	mu      sync.Mutex
+	entries map[K]*cacheEntry[V]
+	calls   map[K]*flightCall[V]
+	stats   CacheStats
+}
+
+// Caching Proxy Constructor
+// The constructor applies the defaults for the missing options.
+// When no policy is set, the LRU policy is used.
+func NewCachingProxy[K comparable, V any](fetcher Fetcher[K, V], opts CacheOptions[K]) *CachingProxy[K, V] {
+	if opts.Policy == nil {
+		opts.Policy = NewLRUPolicy[K]()
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &CachingProxy[K, V]{
+		fetcher: fetcher,
+		opts:    opts,
+		entries: map[K]*cacheEntry[V]{},
+		calls:   map[K]*flightCall[V]{},
+	}
+}
+
+// Proxy Implementation
+// The Fetch method returns the cached value if it is present and not expired.
+// Otherwise, it calls the real fetcher once, even if many goroutines ask for the same key at the same time.
+func (p *CachingProxy[K, V]) Fetch(key K) (V, error) {
+	p.mu.Lock()
+	if e, ok := p.entries[key]; ok {
+		if !p.expired(e) {
+			p.stats.Hits++
+			if e.err != nil {
+				p.stats.NegativeHits++
+			}
+			p.opts.Policy.Touch(key)
+			p.mu.Unlock()
+			return e.value, e.err
+		}
+		p.remove(key)
+		p.stats.Expirations++
+	}
+	p.stats.Misses++
+	if c, ok := p.calls[key]; ok {
+		p.stats.Shared++
+		p.mu.Unlock()
+		c.wg.Wait()
+		return c.value, c.err
+	}
+	c := &flightCall[V]{}
+	c.wg.Add(1)
+	p.calls[key] = c
+	p.stats.Fetches++
+	p.mu.Unlock()
+
+	// Fetching
+	// The deferred function releases the waiting goroutines even if the fetcher panics.
+	// The panic is recorded as the error of the call, so the waiting goroutines do not see a silent success.
+	completed := false
+	defer func() {
+		if !completed {
+			// The recovered value is nil when the fetcher called runtime.Goexit, which must not be turned
+			// into a panic.
+			switch r := recover().(type) {
+			case nil:
+				c.err = ErrFetchPanic
+			case error:
+				c.err = fmt.Errorf("%w: %w", ErrFetchPanic, r)
+				defer panic(r)
+			default:
+				c.err = fmt.Errorf("%w: %v", ErrFetchPanic, r)
+				defer panic(r)
+			}
+		}
+		p.mu.Lock()
+		if p.calls[key] == c {
+			delete(p.calls, key)
+		}
+		p.mu.Unlock()
+		c.wg.Done()
+	}()
+	c.value, c.err = p.fetcher.Fetch(key)
+	completed = true
+	p.mu.Lock()
+	if !c.invalidated {
+		p.store(key, c.value, c.err)
+	}
+	p.mu.Unlock()
+	return c.value, c.err
+}
+
+// Invalidate
+// The method below removes a key from the cache, forcing the next lookup to fetch it again.
+// A fetch in flight for the key may return a value older than the invalidation, so its result is not cached,
+// and the next lookups start a new fetch instead of waiting for it.
+func (p *CachingProxy[K, V]) Invalidate(key K) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.remove(key)
+	if c, ok := p.calls[key]; ok {
+		c.invalidated = true
+		delete(p.calls, key)
+	}
+}
+
+// Len
+// The method below returns the number of cached entries, including the expired ones not yet removed.
+func (p *CachingProxy[K, V]) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries)
+}
+
+// Stats
+// The method below returns a copy of the current stats.
+func (p *CachingProxy[K, V]) Stats() CacheStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stats
+}
+
+// Internal Functions
+// The functions below must be called while holding the lock.
+func (p *CachingProxy[K, V]) expired(e *cacheEntry[V]) bool {
+	return !e.expiresAt.IsZero() && !p.opts.Now().Before(e.expiresAt)
+}
+func (p *CachingProxy[K, V]) remove(key K) {
+	if _, ok := p.entries[key]; ok {
+		delete(p.entries, key)
+		p.opts.Policy.Remove(key)
+	}
+}
+func (p *CachingProxy[K, V]) store(key K, value V, err error) {
+	ttl := p.opts.TTL
+	if err != nil {
+		if p.opts.NegativeTTL <= 0 {
+			return
+		}
+		ttl = p.opts.NegativeTTL
+	}
+	e := &cacheEntry[V]{value: value, err: err}
+	if ttl > 0 {
+		e.expiresAt = p.opts.Now().Add(ttl)
+	}
+	if _, ok := p.entries[key]; !ok && p.opts.Capacity > 0 {
+		for len(p.entries) >= p.opts.Capacity {
+			victim, ok := p.opts.Policy.Victim()
+			if !ok {
+				break
+			}
+			if p.expired(p.entries[victim]) {
+				p.stats.Expirations++
+			} else {
+				p.stats.Evictions++
+			}
+			p.remove(victim)
+		}
+	}
+	p.entries[key] = e
+	p.opts.Policy.Add(key, e.expiresAt)
+}
+
+// DataAccess Fetcher
+// The adapter below allows any DataAccess service to be used by the generic caching proxy.
+type DataAccessFetcher struct {
+	Service DataAccess
+}
+
+// DataAccess Fetcher Implementation
+// The DataAccess protocol cannot fail, so the error is always nil.
+func (f DataAccessFetcher) Fetch(query string) (string, error) {
+	return f.Service.Query(query), nil
+}
+
+// Caching Data Service
+// The struct below implements the DataAccess protocol on top of the generic caching proxy.
+// It can replace the CachedDataService in the Proxy example, and it is safe for concurrent use.
+type CachingDataService struct {
+	Proxy *CachingProxy[string, string]
+}
+
+// Caching Data Service Implementation
+// The query is forwarded to the proxy.
+func (s *CachingDataService) Query(query string) string {
+	res, _ := s.Proxy.Fetch(query)
+	return res
+}
+
+// Test Caching Proxy
+// The test function creates a bounded LRU proxy over the DataService and queries it.
+// Note that the third query evicts the least recently used entry ("abc").
+func TestCachingProxy() {
+	proxy := NewCachingProxy(DataAccessFetcher{Service: &DataService{}}, CacheOptions[string]{
+		Capacity: 2,
+		TTL:      time.Minute,
+		Policy:   NewLRUPolicy[string](),
+	})
+	cds := &CachingDataService{Proxy: proxy}
+	cds.Query("abc")           // Computed
+	cds.Query("abc")           // From Cache
+	cds.Query("def")           // Computed
+	cds.Query("ghi")           // Computed (evicts "abc")
+	fmt.Println(proxy.Stats()) // Output: hits=1 misses=3 shared=0 fetches=3 evictions=1 expirations=0 negative=0 ratio=0.25
+}
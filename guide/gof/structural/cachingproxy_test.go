@@ -0,0 +1,297 @@
+package structural
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Fake Clock
+// The clock below allows the tests to control the expiration of entries.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+func newFakeClock() *fakeClock               { return &fakeClock{now: time.Unix(0, 0)} }
+func identityFetcher() FetcherFunc[string, string] {
+	return func(key string) (string, error) { return "value:" + key, nil }
+}
+
+// Counting Fetcher
+// The fetcher below counts how many times each key was fetched.
+type countingFetcher struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (f *countingFetcher) Fetch(key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.calls == nil {
+		f.calls = map[string]int{}
+	}
+	f.calls[key]++
+	return "value:" + key, nil
+}
+
+func cached[K comparable, V any](p *CachingProxy[K, V], key K) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.entries[key]
+	return ok
+}
+
+func TestCachingProxyLRU(t *testing.T) {
+	p := NewCachingProxy[string, string](identityFetcher(), CacheOptions[string]{
+		Capacity: 2,
+		Policy:   NewLRUPolicy[string](),
+	})
+	p.Fetch("a")
+	p.Fetch("b")
+	p.Fetch("a") // "b" becomes the least recently used
+	p.Fetch("c")
+	if !cached(p, "a") || cached(p, "b") || !cached(p, "c") {
+		t.Errorf("LRU evicted the wrong entry: a=%v b=%v c=%v", cached(p, "a"), cached(p, "b"), cached(p, "c"))
+	}
+	s := p.Stats()
+	if s.Hits != 1 || s.Misses != 3 || s.Evictions != 1 {
+		t.Errorf("unexpected stats: %v", s)
+	}
+}
+
+func TestCachingProxyLFU(t *testing.T) {
+	p := NewCachingProxy[string, string](identityFetcher(), CacheOptions[string]{
+		Capacity: 2,
+		Policy:   NewLFUPolicy[string](),
+	})
+	p.Fetch("a")
+	p.Fetch("a")
+	p.Fetch("a")
+	p.Fetch("b")
+	p.Fetch("b")
+	p.Fetch("c") // "b" has fewer accesses than "a"
+	if !cached(p, "a") || cached(p, "b") || !cached(p, "c") {
+		t.Errorf("LFU evicted the wrong entry: a=%v b=%v c=%v", cached(p, "a"), cached(p, "b"), cached(p, "c"))
+	}
+	p.Fetch("d") // "c" was accessed once, "a" three times
+	if cached(p, "c") || !cached(p, "a") {
+		t.Errorf("LFU should evict the least frequent entry: a=%v c=%v", cached(p, "a"), cached(p, "c"))
+	}
+}
+
+func TestCachingProxyTTL(t *testing.T) {
+	clock := newFakeClock()
+	p := NewCachingProxy[string, string](identityFetcher(), CacheOptions[string]{
+		Capacity: 2,
+		TTL:      time.Minute,
+		Policy:   NewTTLPolicy[string](),
+		Now:      clock.Now,
+	})
+	p.Fetch("a")
+	clock.Advance(10 * time.Second)
+	p.Fetch("b")
+	p.Fetch("a") // Accesses do not extend the expiration
+	p.Fetch("c") // "a" is the closest to expiring
+	if cached(p, "a") || !cached(p, "b") || !cached(p, "c") {
+		t.Errorf("TTL evicted the wrong entry: a=%v b=%v c=%v", cached(p, "a"), cached(p, "b"), cached(p, "c"))
+	}
+	clock.Advance(time.Minute)
+	p.Fetch("b")
+	s := p.Stats()
+	if s.Expirations != 1 || s.Evictions != 1 || s.Hits != 1 {
+		t.Errorf("unexpected stats: %v", s)
+	}
+}
+
+func TestCachingProxyExpiration(t *testing.T) {
+	clock := newFakeClock()
+	f := &countingFetcher{}
+	p := NewCachingProxy[string, string](f, CacheOptions[string]{TTL: time.Second, Now: clock.Now})
+	p.Fetch("a")
+	p.Fetch("a")
+	clock.Advance(time.Second)
+	p.Fetch("a")
+	if f.calls["a"] != 2 {
+		t.Errorf("fetches = %d; expected 2", f.calls["a"])
+	}
+}
+
+func TestCachingProxyNegativeCaching(t *testing.T) {
+	clock := newFakeClock()
+	errNotFound := errors.New("not found")
+	calls := 0
+	fetcher := FetcherFunc[int, string](func(key int) (string, error) {
+		calls++
+		return "", fmt.Errorf("key %d: %w", key, errNotFound)
+	})
+	p := NewCachingProxy[int, string](fetcher, CacheOptions[int]{NegativeTTL: time.Second, Now: clock.Now})
+	for range 3 {
+		if _, err := p.Fetch(1); !errors.Is(err, errNotFound) {
+			t.Fatalf("err = %v; expected %v", err, errNotFound)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetches = %d; expected 1", calls)
+	}
+	clock.Advance(time.Second)
+	p.Fetch(1)
+	if calls != 2 {
+		t.Errorf("fetches = %d; expected 2 after the negative TTL", calls)
+	}
+	if s := p.Stats(); s.NegativeHits != 2 {
+		t.Errorf("negative hits = %d; expected 2", s.NegativeHits)
+	}
+
+	// Without a negative TTL, errors are never cached.
+	calls = 0
+	p = NewCachingProxy[int, string](fetcher, CacheOptions[int]{})
+	p.Fetch(1)
+	p.Fetch(1)
+	if calls != 2 {
+		t.Errorf("fetches = %d; expected 2 without negative caching", calls)
+	}
+}
+
+func TestCachingProxyDeduplication(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetcher := FetcherFunc[string, string](func(key string) (string, error) {
+		calls.Add(1)
+		<-release
+		return "value:" + key, nil
+	})
+	p := NewCachingProxy[string, string](fetcher, CacheOptions[string]{})
+
+	const n = 50
+	var wg sync.WaitGroup
+	results := make([]string, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = p.Fetch("a")
+		}()
+	}
+
+	// Wait until every goroutine is either fetching or waiting for the fetch.
+	for {
+		if s := p.Stats(); s.Misses == n {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("fetches = %d; expected 1", calls.Load())
+	}
+	for i, r := range results {
+		if r != "value:a" {
+			t.Errorf("results[%d] = %q; expected %q", i, r, "value:a")
+		}
+	}
+	if s := p.Stats(); s.Shared != n-1 {
+		t.Errorf("shared = %d; expected %d", s.Shared, n-1)
+	}
+}
+
+func TestCachingProxyFetcherPanic(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fetcher := FetcherFunc[string, string](func(key string) (string, error) {
+		close(started)
+		<-release
+		panic("boom")
+	})
+	p := NewCachingProxy[string, string](fetcher, CacheOptions[string]{})
+	panicked := make(chan any)
+	go func() {
+		defer func() { panicked <- recover() }()
+		p.Fetch("a")
+	}()
+	<-started
+	waiter := make(chan error)
+	go func() {
+		_, err := p.Fetch("a")
+		waiter <- err
+	}()
+	for p.Stats().Shared != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	if r := <-panicked; r != "boom" {
+		t.Errorf("the fetching goroutine must panic again, got %v", r)
+	}
+	if err := <-waiter; !errors.Is(err, ErrFetchPanic) || err.Error() != "fetcher panicked: boom" {
+		t.Errorf("the waiting goroutine got %v; expected %v", err, ErrFetchPanic)
+	}
+	if len(p.calls) != 0 || cached(p, "a") {
+		t.Errorf("in-flight call was not released after a panic")
+	}
+}
+
+func TestCachingProxyInvalidateInFlight(t *testing.T) {
+	var version atomic.Int32
+	started := make(chan struct{}, 2)
+	release := make(chan struct{}, 2)
+	fetcher := FetcherFunc[string, int32](func(key string) (int32, error) {
+		v := version.Load()
+		started <- struct{}{}
+		<-release
+		return v, nil
+	})
+	p := NewCachingProxy[string, int32](fetcher, CacheOptions[string]{})
+	done := make(chan int32)
+	go func() {
+		v, _ := p.Fetch("a")
+		done <- v
+	}()
+	<-started
+	version.Store(1)
+	p.Invalidate("a")
+	release <- struct{}{}
+	if v := <-done; v != 0 {
+		t.Errorf("the in-flight fetch returned %d", v)
+	}
+	if cached(p, "a") {
+		t.Errorf("the result of a fetch started before Invalidate must not be cached")
+	}
+	release <- struct{}{}
+	if v, _ := p.Fetch("a"); v != 1 {
+		t.Errorf("Fetch after Invalidate = %d; expected the new version", v)
+	}
+}
+
+func TestCachingDataService(t *testing.T) {
+	var service DataAccess = &CachingDataService{
+		Proxy: NewCachingProxy(DataAccessFetcher{Service: &DataService{}}, CacheOptions[string]{}),
+	}
+	if res := service.Query("abc"); res != "data" {
+		t.Errorf("Query(abc) = %q; expected %q", res, "data")
+	}
+}
+
+func benchmarkCachingProxy(b *testing.B, policy EvictionPolicy[int]) {
+	fetcher := FetcherFunc[int, int](func(key int) (int, error) { return key, nil })
+	p := NewCachingProxy[int, int](fetcher, CacheOptions[int]{
+		Capacity: 1024,
+		TTL:      time.Minute,
+		Policy:   policy,
+	})
+	i := 0
+	b.ReportAllocs()
+	for b.Loop() {
+		p.Fetch(i % 2048)
+		i++
+	}
+}
+
+func BenchmarkCachingProxyLRU(b *testing.B) { benchmarkCachingProxy(b, NewLRUPolicy[int]()) }
+func BenchmarkCachingProxyLFU(b *testing.B) { benchmarkCachingProxy(b, NewLFUPolicy[int]()) }
+func BenchmarkCachingProxyTTL(b *testing.B) { benchmarkCachingProxy(b, NewTTLPolicy[int]()) }
@@ -0,0 +1,261 @@
+// Document Bridge
+// The HTMLWriter from the Bridge example can only write paragraphs as HTML.
+// The example below expands the bridge into a multi-format document writer.
+// The abstraction (DocumentWriter) offers a document API with headings, paragraphs, lists, tables, and code blocks.
+// The implementors (DocumentFormat) render the document as HTML, Markdown, or plain text.
+// Both sides can vary independently: any document can be rendered in any format, and written to any Writer.
+
+package structural
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Writers
+// The writers below are other implementations of the Writer interface from the Bridge example.
+// The MemoryWriter keeps the content in memory, and the StdoutWriter prints the content to the standard output.
+type (
+	MemoryWriter struct {
+		sb strings.Builder
+	}
+	StdoutWriter struct{}
+)
+
+// Writer Implementations
+// The functions below implement the Writer interface.
+func (w *MemoryWriter) Write(content string) {
+	w.sb.WriteString(content)
+}
+func (w *MemoryWriter) String() string {
+	return w.sb.String()
+}
+func (w StdoutWriter) Write(content string) {
+	os.Stdout.WriteString(content)
+}
+
+// Implementor
+// The interface below defines the operations that each document format must implement.
+// The formats receive the Writer, so they do not depend on where the content is written.
+type DocumentFormat interface {
+	Heading(w Writer, level int, text string)
+	Paragraph(w Writer, text string)
+	List(w Writer, ordered bool, items []string)
+	Table(w Writer, header []string, rows [][]string)
+	CodeBlock(w Writer, lang, code string)
+}
+
+// Abstraction
+// The document writer is the abstraction of the bridge.
+// It has a reference to the format (implementor) and to the writer where the content is written.
+type DocumentWriter struct {
+	Format DocumentFormat
+	Writer Writer
+}
+
+// Abstraction Implementation
+// The methods below delegate the rendering to the format.
+// Each method returns the document writer, so the calls can be chained.
+// The table rows are padded (or truncated) to the number of header columns, so the formats can rely on it.
+func (d *DocumentWriter) Heading(level int, text string) *DocumentWriter {
+	d.Format.Heading(d.Writer, min(max(level, 1), 6), text)
+	return d
+}
+func (d *DocumentWriter) Paragraph(text string) *DocumentWriter {
+	d.Format.Paragraph(d.Writer, text)
+	return d
+}
+func (d *DocumentWriter) List(items ...string) *DocumentWriter {
+	d.Format.List(d.Writer, false, items)
+	return d
+}
+func (d *DocumentWriter) OrderedList(items ...string) *DocumentWriter {
+	d.Format.List(d.Writer, true, items)
+	return d
+}
+func (d *DocumentWriter) Table(header []string, rows [][]string) *DocumentWriter {
+	padded := make([][]string, len(rows))
+	for i, r := range rows {
+		padded[i] = make([]string, len(header))
+		copy(padded[i], r)
+	}
+	d.Format.Table(d.Writer, header, padded)
+	return d
+}
+func (d *DocumentWriter) CodeBlock(lang, code string) *DocumentWriter {
+	d.Format.CodeBlock(d.Writer, lang, strings.TrimSuffix(code, "\n"))
+	return d
+}
+
+// Concrete Implementors
+// The structs below are the formats supported by the document writer.
+type (
+	HTMLFormat     struct{}
+	MarkdownFormat struct{}
+	TextFormat     struct{}
+)
+
+// HTML Format
+// The HTML format escapes the text, so characters like "<" and "&" are displayed correctly.
+func (HTMLFormat) Heading(w Writer, level int, text string) {
+	w.Write(fmt.Sprintf("<h%d>%s</h%d>\n", level, html.EscapeString(text), level))
+}
+func (HTMLFormat) Paragraph(w Writer, text string) {
+	w.Write("<p>" + html.EscapeString(text) + "</p>\n")
+}
+func (HTMLFormat) List(w Writer, ordered bool, items []string) {
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	w.Write("<" + tag + ">\n")
+	for _, item := range items {
+		w.Write("  <li>" + html.EscapeString(item) + "</li>\n")
+	}
+	w.Write("</" + tag + ">\n")
+}
+func (HTMLFormat) Table(w Writer, header []string, rows [][]string) {
+	row := func(tag string, cells []string) {
+		w.Write("  <tr>")
+		for _, c := range cells {
+			w.Write("<" + tag + ">" + html.EscapeString(c) + "</" + tag + ">")
+		}
+		w.Write("</tr>\n")
+	}
+	w.Write("<table>\n")
+	row("th", header)
+	for _, r := range rows {
+		row("td", r)
+	}
+	w.Write("</table>\n")
+}
+func (HTMLFormat) CodeBlock(w Writer, lang, code string) {
+	class := ""
+	if lang != "" {
+		class = ` class="language-` + html.EscapeString(lang) + `"`
+	}
+	w.Write("<pre><code" + class + ">" + html.EscapeString(code) + "</code></pre>\n")
+}
+
+// Markdown Format
+// The Markdown format separates the blocks with blank lines, as required by most Markdown parsers.
+// The pipes inside table cells are escaped, since they are used as column separators.
+func (MarkdownFormat) Heading(w Writer, level int, text string) {
+	w.Write(strings.Repeat("#", level) + " " + text + "\n\n")
+}
+func (MarkdownFormat) Paragraph(w Writer, text string) {
+	w.Write(text + "\n\n")
+}
+func (MarkdownFormat) List(w Writer, ordered bool, items []string) {
+	for i, item := range items {
+		if ordered {
+			w.Write(fmt.Sprintf("%d. %s\n", i+1, item))
+		} else {
+			w.Write("- " + item + "\n")
+		}
+	}
+	w.Write("\n")
+}
+func (MarkdownFormat) Table(w Writer, header []string, rows [][]string) {
+	row := func(cells []string) {
+		escaped := make([]string, len(cells))
+		for i, c := range cells {
+			escaped[i] = strings.ReplaceAll(c, "|", `\|`)
+		}
+		w.Write("| " + strings.Join(escaped, " | ") + " |\n")
+	}
+	row(header)
+	sep := make([]string, len(header))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	row(sep)
+	for _, r := range rows {
+		row(r)
+	}
+	w.Write("\n")
+}
+func (MarkdownFormat) CodeBlock(w Writer, lang, code string) {
+	w.Write("```" + lang + "\n" + code + "\n```\n\n")
+}
+
+// Text Format
+// The plain text format underlines the main headings and aligns the table columns.
+// The code blocks are indented with four spaces.
+func (TextFormat) Heading(w Writer, level int, text string) {
+	switch level {
+	case 1:
+		w.Write(text + "\n" + strings.Repeat("=", utf8.RuneCountInString(text)) + "\n\n")
+	case 2:
+		w.Write(text + "\n" + strings.Repeat("-", utf8.RuneCountInString(text)) + "\n\n")
+	default:
+		w.Write(strings.ToUpper(text) + "\n\n")
+	}
+}
+func (TextFormat) Paragraph(w Writer, text string) {
+	w.Write(text + "\n\n")
+}
+func (TextFormat) List(w Writer, ordered bool, items []string) {
+	for i, item := range items {
+		if ordered {
+			w.Write(fmt.Sprintf("%d) %s\n", i+1, item))
+		} else {
+			w.Write("* " + item + "\n")
+		}
+	}
+	w.Write("\n")
+}
+func (TextFormat) Table(w Writer, header []string, rows [][]string) {
+	widths := make([]int, len(header))
+	for _, r := range append([][]string{header}, rows...) {
+		for i, c := range r {
+			widths[i] = max(widths[i], utf8.RuneCountInString(c))
+		}
+	}
+	row := func(cells []string) {
+		line := ""
+		for i, width := range widths {
+			c := cells[i]
+			line += c + strings.Repeat(" ", width-utf8.RuneCountInString(c)) + "  "
+		}
+		w.Write(strings.TrimRight(line, " ") + "\n")
+	}
+	row(header)
+	sep := make([]string, len(widths))
+	for i, width := range widths {
+		sep[i] = strings.Repeat("-", width)
+	}
+	row(sep)
+	for _, r := range rows {
+		row(r)
+	}
+	w.Write("\n")
+}
+func (TextFormat) CodeBlock(w Writer, lang, code string) {
+	for line := range strings.SplitSeq(code, "\n") {
+		w.Write(strings.TrimRight("    "+line, " ") + "\n")
+	}
+	w.Write("\n")
+}
+
+// Test Document Bridge
+// The test function writes the same document in the three formats.
+// The HTML document is written to a File, the Markdown to memory, and the plain text to the standard output.
+func TestDocumentBridge() {
+	write := func(d *DocumentWriter) {
+		d.Heading(1, "Report").
+			Paragraph("Sales per region.").
+			Table([]string{"Region", "Total"}, [][]string{{"North", "10"}, {"South", "25"}}).
+			List("Checked", "Approved")
+	}
+	file := &File{}
+	mem := &MemoryWriter{}
+	write(&DocumentWriter{Format: HTMLFormat{}, Writer: file})
+	write(&DocumentWriter{Format: MarkdownFormat{}, Writer: mem})
+	write(&DocumentWriter{Format: TextFormat{}, Writer: StdoutWriter{}})
+	fmt.Println(file.Content) // Output: <h1>Report</h1> ...
+	fmt.Println(mem)          // Output: # Report ...
+}
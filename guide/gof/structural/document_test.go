@@ -0,0 +1,57 @@
+package structural
+
+import (
+	"testing"
+)
+
+func writeSampleDocument(d *DocumentWriter) {
+	d.Heading(1, "Quarterly Report").
+		Paragraph("Sales grew in every region & the <best> one was South.").
+		Heading(2, "Highlights").
+		List("New customers", "Lower costs").
+		OrderedList("Plan", "Build", "Ship").
+		Heading(3, "Numbers").
+		Table([]string{"Region", "Sales", "Notes"}, [][]string{
+			{"North", "1200", "stable"},
+			{"South", "3400", "a|b"},
+			{"East", "900"},
+		}).
+		CodeBlock("go", "func main() {\n\tfmt.Println(\"<ok>\")\n}\n")
+}
+
+func TestDocumentGolden(t *testing.T) {
+	formats := []struct {
+		name   string
+		format DocumentFormat
+	}{
+		{"document_html", HTMLFormat{}},
+		{"document_markdown", MarkdownFormat{}},
+		{"document_text", TextFormat{}},
+	}
+	for _, f := range formats {
+		t.Run(f.name, func(t *testing.T) {
+			w := &MemoryWriter{}
+			writeSampleDocument(&DocumentWriter{Format: f.format, Writer: w})
+			assertGolden(t, f.name, w.String())
+		})
+	}
+}
+
+func TestDocumentWriters(t *testing.T) {
+	// The same format must produce the same output on any Writer.
+	file := &File{}
+	mem := &MemoryWriter{}
+	(&DocumentWriter{Format: MarkdownFormat{}, Writer: file}).Paragraph("Hello")
+	(&DocumentWriter{Format: MarkdownFormat{}, Writer: mem}).Paragraph("Hello")
+	if file.Content != mem.String() || file.Content != "Hello\n\n" {
+		t.Errorf("File = %q, MemoryWriter = %q; expected %q", file.Content, mem.String(), "Hello\n\n")
+	}
+}
+
+func TestDocumentHeadingLevel(t *testing.T) {
+	w := &MemoryWriter{}
+	(&DocumentWriter{Format: HTMLFormat{}, Writer: w}).Heading(0, "a").Heading(9, "b")
+	if got := w.String(); got != "<h1>a</h1>\n<h6>b</h6>\n" {
+		t.Errorf("headings = %q; expected levels clamped to 1..6", got)
+	}
+}
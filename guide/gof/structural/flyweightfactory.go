@@ -0,0 +1,203 @@
+// Flyweight Factory
+// The Flyweight example shares the textures through the global Textures map.
+// A global map is not safe for concurrent use, and it keeps every texture in memory forever.
+// The factory below is a generic flyweight factory: it interns resources by key, so the same key
+// always returns the same shared instance, and it counts the references to each resource.
+// The factory does not copy the resources, so they must be read-only: a change made by one consumer is seen
+// by all the others (see Glyph for a resource that cannot be changed).
+// When the last reference is released, the resource is removed from the factory, so memory is bounded by the
+// resources actually in use.
+
+package structural
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"sync"
+	"unicode/utf8"
+	"unique"
+)
+
+// Flyweight Entry
+// The entry holds the shared value and the number of references to it.
+// The loaded wait group is done when the value (or the error) is ready, so the goroutines acquiring a key that
+// is still loading wait for the same load.
+type flyweightEntry[V any] struct {
+	value  V
+	err    error
+	refs   int
+	loaded sync.WaitGroup
+}
+
+// Load Panics
+// When the load function panics, the goroutines waiting for the same key get ErrLoadPanic.
+var ErrLoadPanic = errors.New("flyweight load panicked")
+
+// Flyweight Factory
+// The factory loads each resource once, using the load function, and returns the shared instance afterward.
+// It is safe for concurrent use.
+type FlyweightFactory[K comparable, V any] struct {
+	mu      sync.Mutex
+	load    func(key K) (V, error)
+	entries map[K]*flyweightEntry[V]
+}
+
+// Flyweight Factory Constructor
+// The constructor receives the function that creates the resource for a key.
+func NewFlyweightFactory[K comparable, V any](load func(key K) (V, error)) *FlyweightFactory[K, V] {
+	return &FlyweightFactory[K, V]{load: load, entries: map[K]*flyweightEntry[V]{}}
+}
+
+// Acquire
+// The method below returns the shared resource for the key, loading it on the first call.
+// Each call must be paired with a call to Release when the resource is no longer used.
+// The resource is loaded without holding the lock, so a slow load does not block the other keys.
+// Concurrent calls for the same key wait for the first load instead of loading the key twice,
+// like the in-flight fetches of the caching proxy.
+func (f *FlyweightFactory[K, V]) Acquire(key K) (V, error) {
+	f.mu.Lock()
+	if e, ok := f.entries[key]; ok {
+		e.refs++
+		f.mu.Unlock()
+		e.loaded.Wait()
+		return e.value, e.err
+	}
+	// The error is only replaced when the load returns, so it stays ErrLoadPanic if the load panics.
+	e := &flyweightEntry[V]{refs: 1, err: ErrLoadPanic}
+	e.loaded.Add(1)
+	f.entries[key] = e
+	f.mu.Unlock()
+
+	// Loading
+	// The deferred function releases the waiting goroutines even if the load panics.
+	// The failed entries are removed, so the next call loads the resource again.
+	defer func() {
+		if e.err != nil {
+			f.mu.Lock()
+			if f.entries[key] == e {
+				delete(f.entries, key)
+			}
+			f.mu.Unlock()
+		}
+		e.loaded.Done()
+	}()
+	e.value, e.err = f.load(key)
+	return e.value, e.err
+}
+
+// Release
+// The method below releases a reference to the resource.
+// The resource is removed when there are no more references to it.
+// Release must only be called after a successful Acquire: a failed Acquire holds no reference, and releasing it
+// would drop a reference held by another caller of the same key.
+func (f *FlyweightFactory[K, V]) Release(key K) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, ok := f.entries[key]
+	if !ok {
+		return
+	}
+	e.refs--
+	if e.refs <= 0 {
+		delete(f.entries, key)
+	}
+}
+
+// Factory Functions
+// The Refs function returns the number of references to a resource, and Len returns the number of resources.
+func (f *FlyweightFactory[K, V]) Refs(key K) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if e, ok := f.entries[key]; ok {
+		return e.refs
+	}
+	return 0
+}
+func (f *FlyweightFactory[K, V]) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.entries)
+}
+
+// Embedded Textures
+// The textures are stored in files embedded in the binary at build time.
+// Each texture is a 16x16 image where each character represents a pixel.
+//
+//go:embed resources/textures/*.tex
+var textureFiles embed.FS
+
+// Load Texture
+// The function below loads a texture from the embedded files.
+// It is used as the load function of the texture factory.
+// The texture is a byte slice shared by all the sprites, so it must not be modified after it is loaded.
+func LoadTexture(name string) (*Texture, error) {
+	b, err := textureFiles.ReadFile("resources/textures/" + name + ".tex")
+	if err != nil {
+		return nil, fmt.Errorf("load texture %q: %w", name, err)
+	}
+	t := Texture(b)
+	return &t, nil
+}
+
+// Texture Factory
+// The texture factory is a flyweight factory that loads the textures from the embedded files.
+func NewTextureFactory() *FlyweightFactory[string, *Texture] {
+	return NewFlyweightFactory(LoadTexture)
+}
+
+// Glyph
+// The glyph is another immutable resource that can be shared.
+// A text editor, for example, has millions of characters, but only a few distinct glyphs.
+// The encoded bytes are kept in a string, and Bytes returns a copy, so a consumer cannot change the shared glyph.
+type Glyph struct {
+	Rune    rune
+	encoded string
+	Width   int
+}
+
+// Bytes returns the UTF-8 encoding of the glyph.
+func (g *Glyph) Bytes() []byte {
+	return []byte(g.encoded)
+}
+
+// Glyph Factory
+// The glyph factory creates the glyphs from their runes.
+// Wide characters (such as the CJK ideographs) take two columns in a terminal.
+func NewGlyphFactory() *FlyweightFactory[rune, *Glyph] {
+	return NewFlyweightFactory(func(r rune) (*Glyph, error) {
+		if !utf8.ValidRune(r) {
+			return nil, fmt.Errorf("invalid rune: %U", r)
+		}
+		width := 1
+		if r >= 0x1100 && (r <= 0x115F || (r >= 0x2E80 && r <= 0xA4CF) || (r >= 0xAC00 && r <= 0xD7A3) ||
+			(r >= 0xF900 && r <= 0xFAFF) || (r >= 0xFF00 && r <= 0xFF60)) {
+			width = 2
+		}
+		return &Glyph{Rune: r, encoded: string(r), Width: width}, nil
+	})
+}
+
+// String Interning
+// Strings are immutable in Go, so equal strings can share the same memory.
+// The "unique" package (Go 1.23+) interns comparable values globally, and is safe for concurrent use.
+// The interned values are released by the garbage collector when they are no longer used, so no release is needed.
+func Intern(s string) string {
+	return unique.Make(s).Value()
+}
+
+// Test Flyweight Factory
+// The test function acquires the same texture twice, and checks that both sprites share it.
+// After releasing both references, the texture is removed from the factory.
+func TestFlyweightFactory() {
+	textures := NewTextureFactory()
+	t1, _ := textures.Acquire("tree")
+	t2, _ := textures.Acquire("tree")
+	tree1 := &Sprite{X: 0, Y: 0, Texture: t1}
+	tree2 := &Sprite{X: 1, Y: 1, Texture: t2}
+	fmt.Println(tree1.Texture == tree2.Texture) // Output: true
+	fmt.Println(textures.Refs("tree"))          // Output: 2
+	textures.Release("tree")
+	textures.Release("tree")
+	fmt.Println(textures.Len()) // Output: 0
+}
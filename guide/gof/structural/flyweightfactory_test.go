@@ -0,0 +1,213 @@
+package structural
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"unsafe"
+)
+
+func TestFlyweightFactoryAcquireRelease(t *testing.T) {
+	loads := 0
+	f := NewFlyweightFactory(func(key string) (*string, error) {
+		loads++
+		s := "value:" + key
+		return &s, nil
+	})
+	a, _ := f.Acquire("a")
+	b, _ := f.Acquire("a")
+	if a != b || loads != 1 || f.Refs("a") != 2 {
+		t.Errorf("shared = %v, loads = %d, refs = %d; expected true, 1, 2", a == b, loads, f.Refs("a"))
+	}
+	f.Release("a")
+	if f.Len() != 1 {
+		t.Errorf("resource removed while still referenced")
+	}
+	f.Release("a")
+	f.Release("a") // Extra releases are ignored
+	if f.Len() != 0 || f.Refs("a") != 0 {
+		t.Errorf("Len = %d, Refs = %d; expected 0, 0", f.Len(), f.Refs("a"))
+	}
+	f.Acquire("a")
+	if loads != 2 {
+		t.Errorf("loads = %d; expected the resource to be loaded again", loads)
+	}
+}
+
+func TestFlyweightFactoryConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	loads := map[int]int{}
+	f := NewFlyweightFactory(func(key int) (*int, error) {
+		mu.Lock()
+		loads[key]++
+		mu.Unlock()
+		return &key, nil
+	})
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f.Acquire(i % 4)
+		}()
+	}
+	wg.Wait()
+	for key, n := range loads {
+		if n != 1 {
+			t.Errorf("key %d loaded %d times; expected 1", key, n)
+		}
+		if f.Refs(key) != 25 {
+			t.Errorf("Refs(%d) = %d; expected 25", key, f.Refs(key))
+		}
+	}
+}
+
+// A slow load must not block the other keys, and the goroutines acquiring the same key wait for its load.
+func TestFlyweightFactoryLoadOutsideLock(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var loads atomic.Int32
+	f := NewFlyweightFactory(func(key string) (string, error) {
+		loads.Add(1)
+		if key == "slow" {
+			close(started)
+			<-release
+		}
+		return "value:" + key, nil
+	})
+	results := make(chan string, 2)
+	for range 2 {
+		go func() {
+			v, _ := f.Acquire("slow")
+			results <- v
+		}()
+	}
+	<-started
+	if v, err := f.Acquire("fast"); err != nil || v != "value:fast" {
+		t.Errorf("Acquire(fast) = %q, %v", v, err)
+	}
+	close(release)
+	for range 2 {
+		if v := <-results; v != "value:slow" {
+			t.Errorf("Acquire(slow) = %q", v)
+		}
+	}
+	if loads.Load() != 2 || f.Refs("slow") != 2 {
+		t.Errorf("loads = %d, Refs = %d; expected 2, 2", loads.Load(), f.Refs("slow"))
+	}
+}
+
+func TestFlyweightFactoryLoadPanic(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	f := NewFlyweightFactory(func(key string) (string, error) {
+		close(started)
+		<-release
+		panic("boom")
+	})
+	go func() {
+		defer func() { recover() }()
+		f.Acquire("a")
+	}()
+	<-started
+	done := make(chan error)
+	go func() {
+		_, err := f.Acquire("a")
+		done <- err
+	}()
+	for f.Refs("a") != 2 {
+		runtime.Gosched()
+	}
+	close(release)
+	if err := <-done; !errors.Is(err, ErrLoadPanic) {
+		t.Errorf("waiting Acquire = %v; expected %v", err, ErrLoadPanic)
+	}
+	if f.Len() != 0 {
+		t.Errorf("failed loads must not be kept: Len = %d", f.Len())
+	}
+}
+
+func TestTextureFactory(t *testing.T) {
+	textures := NewTextureFactory()
+	tree, err := textures.Acquire("tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*tree) != 16*17 || !bytes.HasPrefix(*tree, []byte("......####......\n")) {
+		t.Errorf("unexpected tree texture: %q", *tree)
+	}
+	if _, err := textures.Acquire("lava"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Acquire(lava) = %v; expected %v", err, fs.ErrNotExist)
+	}
+	if textures.Len() != 1 {
+		t.Errorf("failed loads must not be cached: Len = %d", textures.Len())
+	}
+}
+
+func TestGlyphFactory(t *testing.T) {
+	glyphs := NewGlyphFactory()
+	for _, tt := range []struct {
+		r     rune
+		width int
+	}{{'a', 1}, {'é', 1}, {'世', 2}, {'한', 2}} {
+		g, err := glyphs.Acquire(tt.r)
+		if err != nil || g.Width != tt.width || string(g.Bytes()) != string(tt.r) {
+			t.Errorf("Acquire(%q) = %+v, %v; expected width %d", tt.r, g, err, tt.width)
+		}
+	}
+	g, _ := glyphs.Acquire('a')
+	g.Bytes()[0] = 'b'
+	if string(g.Bytes()) != "a" {
+		t.Errorf("Bytes must return a copy, the shared glyph is now %q", g.Bytes())
+	}
+	if _, err := glyphs.Acquire(-1); err == nil {
+		t.Errorf("invalid runes should fail")
+	}
+}
+
+func TestIntern(t *testing.T) {
+	a := Intern(string([]byte("shared")))
+	b := Intern(string([]byte("shared")))
+	if a != b || unsafe.StringData(a) != unsafe.StringData(b) {
+		t.Errorf("interned strings do not share memory")
+	}
+}
+
+// Sprites Benchmark
+// The benchmarks below create one million sprites, cycling over three textures.
+// The shared version references the textures from the factory, while the copied version gives
+// each sprite its own copy of the texture bytes.
+// Compare the B/op and allocs/op columns:
+//
+//	go test -run XXX -bench Sprites ./gof/structural
+const benchmarkSprites = 1_000_000
+
+var benchmarkTextureNames = []string{"tree", "stone", "grass"}
+
+func BenchmarkSpritesShared(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		textures := NewTextureFactory()
+		sprites := make([]Sprite, benchmarkSprites)
+		for i := range sprites {
+			name := benchmarkTextureNames[i%len(benchmarkTextureNames)]
+			tex, _ := textures.Acquire(name)
+			sprites[i] = Sprite{X: i, Y: i, Texture: tex}
+		}
+	}
+}
+
+func BenchmarkSpritesCopied(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		sprites := make([]Sprite, benchmarkSprites)
+		for i := range sprites {
+			name := benchmarkTextureNames[i%len(benchmarkTextureNames)]
+			tex, _ := LoadTexture(name)
+			sprites[i] = Sprite{X: i, Y: i, Texture: tex}
+		}
+	}
+}
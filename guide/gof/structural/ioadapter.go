@@ -0,0 +1,290 @@
+// IO Adapters
+// The ByteWriter and TextWriter protocols from the Adapter example do not interoperate with the standard library.
+// The standard library uses the "io.Writer" and "io.Reader" interfaces for almost everything that reads or writes
+// bytes, such as files, buffers, network connections, and the standard output.
+// The adapters below convert between both worlds, so the demo types can be plugged into "os.Stdout", files,
+// "bytes.Buffer", and any other standard writer or reader.
+
+package structural
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf16"
+)
+
+// Adapter (ByteWriter to io.Writer)
+// The adapter below implements io.Writer on top of a ByteWriter.
+// This allows a ByteWriter to be used with functions like "fmt.Fprintf" and "io.Copy".
+type ByteToIOWriterAdapter struct {
+	Writer ByteWriter
+}
+
+// Adapter Implementation
+// The io.Writer contract forbids retaining the slice, so the bytes are copied before being written.
+// The ByteWriter protocol cannot fail, so the whole slice is always written.
+func (a *ByteToIOWriterAdapter) Write(p []byte) (int, error) {
+	a.Writer.WriteBytes(bytes.Clone(p))
+	return len(p), nil
+}
+
+// Adapter (TextWriter to io.Writer)
+// The adapter below implements io.Writer on top of a TextWriter, converting the bytes to text.
+type TextToIOWriterAdapter struct {
+	Writer TextWriter
+}
+
+// Adapter Implementation
+// The bytes are converted to a string, which also copies them.
+func (a *TextToIOWriterAdapter) Write(p []byte) (int, error) {
+	a.Writer.WriteText(string(p))
+	return len(p), nil
+}
+
+// Adapter (io.Writer to ByteWriter)
+// The adapter below implements the ByteWriter protocol on top of any io.Writer.
+// Since the ByteWriter protocol does not return errors, the first error is kept in the Err field.
+// After an error, the following writes are ignored.
+type IOToByteWriterAdapter struct {
+	Writer io.Writer
+	Err    error
+}
+
+// Adapter Implementation
+// The bytes are written to the io.Writer.
+func (a *IOToByteWriterAdapter) WriteBytes(b []byte) {
+	if a.Err != nil {
+		return
+	}
+	_, a.Err = a.Writer.Write(b)
+}
+
+// Adapter (io.Writer to TextWriter)
+// The adapter below implements the TextWriter protocol on top of any io.Writer.
+// The text is written as UTF-8, which is the native encoding of Go strings.
+type IOToTextWriterAdapter struct {
+	Writer io.Writer
+	Err    error
+}
+
+// Adapter Implementation
+// The "io.WriteString" function avoids the conversion to bytes when the writer implements "io.StringWriter".
+func (a *IOToTextWriterAdapter) WriteText(t string) {
+	if a.Err != nil {
+		return
+	}
+	_, a.Err = io.WriteString(a.Writer, t)
+}
+
+// Adapter (DefaultByteWriter to io.Reader)
+// The adapter below implements io.Reader on top of the bytes stored by a DefaultByteWriter.
+// This allows the stored bytes to be copied to any io.Writer.
+type ByteSourceReader struct {
+	Writer *DefaultByteWriter
+	offset int
+}
+
+// Adapter Implementation
+// The reader returns "io.EOF" when all the stored bytes were read.
+func (r *ByteSourceReader) Read(p []byte) (int, error) {
+	if r.offset >= len(r.Writer.Source) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.Writer.Source[r.offset:])
+	r.offset += n
+	return n, nil
+}
+
+// Copy to ByteWriter
+// The function below reads all the bytes from an io.Reader and writes them to a ByteWriter.
+// Note that the DefaultByteWriter replaces its Source on each write, so all the bytes are written at once.
+func CopyToByteWriter(dst ByteWriter, src io.Reader) (int64, error) {
+	b, err := io.ReadAll(src)
+	if err != nil {
+		return 0, err
+	}
+	dst.WriteBytes(b)
+	return int64(len(b)), nil
+}
+
+// Text Encoding
+// The enumeration below defines the encodings supported by the EncodingTextWriter.
+type TextEncoding int
+
+const (
+	EncodingUTF8 TextEncoding = iota
+	EncodingUTF16LE
+	EncodingUTF16BE
+	EncodingLatin1
+)
+
+// Encoding Text Writer
+// The adapter below implements the TextWriter protocol, transcoding the text before writing it to an io.Writer.
+// Go strings are UTF-8, so the text must be converted to be read by programs that expect other encodings.
+// Characters that cannot be represented in Latin-1 (ISO-8859-1) are replaced by "?".
+type EncodingTextWriter struct {
+	Writer   io.Writer
+	Encoding TextEncoding
+	Err      error
+}
+
+// Encoding Text Writer Implementation
+// The text is encoded and written to the io.Writer.
+func (w *EncodingTextWriter) WriteText(t string) {
+	if w.Err != nil {
+		return
+	}
+	b, err := EncodeText(t, w.Encoding)
+	if err != nil {
+		w.Err = err
+		return
+	}
+	_, w.Err = w.Writer.Write(b)
+}
+
+// Encode Text
+// The function below converts a UTF-8 string to the given encoding.
+// UTF-16 uses surrogate pairs for the characters outside the Basic Multilingual Plane.
+func EncodeText(t string, enc TextEncoding) ([]byte, error) {
+	switch enc {
+	case EncodingUTF8:
+		return []byte(t), nil
+	case EncodingUTF16LE, EncodingUTF16BE:
+		units := utf16.Encode([]rune(t))
+		b := make([]byte, 0, len(units)*2)
+		for _, u := range units {
+			if enc == EncodingUTF16LE {
+				b = append(b, byte(u), byte(u>>8))
+			} else {
+				b = append(b, byte(u>>8), byte(u))
+			}
+		}
+		return b, nil
+	case EncodingLatin1:
+		b := make([]byte, 0, len(t))
+		for _, r := range t {
+			if r > 0xFF {
+				r = '?'
+			}
+			b = append(b, byte(r))
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("unknown encoding: %d", enc)
+}
+
+// Line Buffered Writer
+// The writer below keeps the bytes in memory until a complete line is written.
+// This is useful to avoid interleaving partial lines when many producers write to the same output.
+type LineBufferedWriter struct {
+	Writer io.Writer
+	buf    []byte
+}
+
+// Line Buffered Writer Constructor
+// The constructor wraps any io.Writer.
+func NewLineBufferedWriter(w io.Writer) *LineBufferedWriter {
+	return &LineBufferedWriter{Writer: w}
+}
+
+// Line Buffered Writer Implementation
+// Only the complete lines are written, the rest is kept until the next newline or until Flush is called.
+// When the underlying writer fails, p is removed from the buffer and 0 is returned with the error, so the caller
+// can retry p without writing it twice (the io.Writer contract).
+func (w *LineBufferedWriter) Write(p []byte) (int, error) {
+	n := len(w.buf)
+	w.buf = append(w.buf, p...)
+	i := bytes.LastIndexByte(w.buf, '\n')
+	if i < 0 {
+		return len(p), nil
+	}
+	if _, err := w.Writer.Write(w.buf[:i+1]); err != nil {
+		w.buf = w.buf[:n]
+		return 0, err
+	}
+	w.buf = append(w.buf[:0], w.buf[i+1:]...)
+	return len(p), nil
+}
+
+// Flush
+// The method below writes the incomplete line kept in memory.
+func (w *LineBufferedWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	_, err := w.Writer.Write(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
+
+// Gzip Byte Writer
+// The adapter below implements the ByteWriter protocol, compressing the bytes before writing them.
+// The compressed stream is only complete after Close is called.
+type GzipByteWriter struct {
+	gz  *gzip.Writer
+	Err error
+}
+
+// Gzip Byte Writer Constructor
+// The constructor wraps any io.Writer, such as a file or a buffer.
+func NewGzipByteWriter(w io.Writer) *GzipByteWriter {
+	return &GzipByteWriter{gz: gzip.NewWriter(w)}
+}
+
+// Gzip Byte Writer Implementation
+// The bytes are compressed and written to the underlying writer.
+func (w *GzipByteWriter) WriteBytes(b []byte) {
+	if w.Err != nil {
+		return
+	}
+	_, w.Err = w.gz.Write(b)
+}
+func (w *GzipByteWriter) Close() error {
+	if err := w.gz.Close(); w.Err == nil {
+		w.Err = err
+	}
+	return w.Err
+}
+
+// Test IO Adapters
+// The test function plugs the demo types into standard writers and readers.
+func TestIOAdapters() {
+
+	// ByteWriter as io.Writer
+	// The fmt package can write to a ByteWriter through the adapter.
+	bw := &DefaultByteWriter{}
+	fmt.Fprintf(&ByteToIOWriterAdapter{Writer: bw}, "Hello %s!", "World")
+	fmt.Println(string(bw.Source)) // Output: Hello World!
+
+	// io.Reader from ByteWriter
+	// The stored bytes can be copied to the standard output.
+	io.Copy(os.Stdout, &ByteSourceReader{Writer: bw}) // Output: Hello World!
+	fmt.Println()
+
+	// TextWriter over os.Stdout
+	// The standard output is an io.Writer, so it can be used as a TextWriter.
+	var tw TextWriter = &IOToTextWriterAdapter{Writer: os.Stdout}
+	tw.WriteText("Hello Stdout!\n") // Output: Hello Stdout!
+
+	// Transcoding
+	// The text is written to a buffer as UTF-16 (little endian).
+	buf := &bytes.Buffer{}
+	tw = &EncodingTextWriter{Writer: buf, Encoding: EncodingUTF16LE}
+	tw.WriteText("Hi")
+	fmt.Println(buf.Bytes()) // Output: [72 0 105 0]
+
+	// Compressing to a File
+	// The compressed bytes are written to a temporary file.
+	f, err := os.CreateTemp("", "adapter-*.gz")
+	if err != nil {
+		return
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	gz := NewGzipByteWriter(f)
+	gz.WriteBytes([]byte("Hello File!"))
+	fmt.Println(gz.Close()) // Output: <nil>
+}
@@ -0,0 +1,214 @@
+package structural
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Failing Writer
+// The writer below fails after a given number of writes.
+type failingWriter struct {
+	writes int
+	limit  int
+}
+
+var errWriteFailed = errors.New("write failed")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.writes >= w.limit {
+		return 0, errWriteFailed
+	}
+	w.writes++
+	return len(p), nil
+}
+
+func TestByteToIOWriterAdapter(t *testing.T) {
+	bw := &DefaultByteWriter{}
+	p := []byte("Hello")
+	n, err := (&ByteToIOWriterAdapter{Writer: bw}).Write(p)
+	if n != 5 || err != nil {
+		t.Fatalf("Write = %d, %v; expected 5, <nil>", n, err)
+	}
+	p[0] = 'J' // The adapter must not retain the slice
+	if string(bw.Source) != "Hello" {
+		t.Errorf("Source = %q; expected %q", bw.Source, "Hello")
+	}
+}
+
+func TestTextToIOWriterAdapter(t *testing.T) {
+	tw := &DefaultTextWriter{}
+	fmt.Fprintf(&TextToIOWriterAdapter{Writer: tw}, "%d-%s", 1, "a")
+	if tw.Source != "1-a" {
+		t.Errorf("Source = %q; expected %q", tw.Source, "1-a")
+	}
+}
+
+func TestIOToByteWriterAdapter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var bw ByteWriter = &IOToByteWriterAdapter{Writer: buf}
+	bw.WriteBytes([]byte("Hello "))
+	bw.WriteBytes([]byte("World"))
+	if buf.String() != "Hello World" {
+		t.Errorf("buffer = %q; expected %q", buf.String(), "Hello World")
+	}
+
+	fw := &failingWriter{limit: 1}
+	a := &IOToByteWriterAdapter{Writer: fw}
+	a.WriteBytes([]byte("a"))
+	a.WriteBytes([]byte("b"))
+	a.WriteBytes([]byte("c"))
+	if !errors.Is(a.Err, errWriteFailed) || fw.writes != 1 {
+		t.Errorf("Err = %v, writes = %d; expected %v, 1", a.Err, fw.writes, errWriteFailed)
+	}
+}
+
+func TestIOToTextWriterAdapterFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.txt")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &IOToTextWriterAdapter{Writer: f}
+	a.WriteText("line 1\n")
+	a.WriteText("line 2\n")
+	if err := f.Close(); err != nil || a.Err != nil {
+		t.Fatalf("Close = %v, Err = %v", err, a.Err)
+	}
+	b, _ := os.ReadFile(path)
+	if string(b) != "line 1\nline 2\n" {
+		t.Errorf("file = %q; expected %q", b, "line 1\nline 2\n")
+	}
+}
+
+func TestByteSourceReader(t *testing.T) {
+	bw := &DefaultByteWriter{Source: []byte("Hello World!")}
+	r := &ByteSourceReader{Writer: bw}
+	p := make([]byte, 5)
+	n, _ := r.Read(p)
+	if string(p[:n]) != "Hello" {
+		t.Errorf("Read = %q; expected %q", p[:n], "Hello")
+	}
+	rest, err := io.ReadAll(r)
+	if string(rest) != " World!" || err != nil {
+		t.Errorf("ReadAll = %q, %v; expected %q, <nil>", rest, err, " World!")
+	}
+
+	dst := &DefaultByteWriter{}
+	n64, err := CopyToByteWriter(dst, strings.NewReader("copied"))
+	if n64 != 6 || err != nil || string(dst.Source) != "copied" {
+		t.Errorf("CopyToByteWriter = %d, %v, %q", n64, err, dst.Source)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		text string
+		enc  TextEncoding
+		want []byte
+	}{
+		{"Hé", EncodingUTF8, []byte{'H', 0xC3, 0xA9}},
+		{"Hé", EncodingUTF16LE, []byte{'H', 0, 0xE9, 0}},
+		{"Hé", EncodingUTF16BE, []byte{0, 'H', 0, 0xE9}},
+		{"😀", EncodingUTF16BE, []byte{0xD8, 0x3D, 0xDE, 0x00}},
+		{"Hé", EncodingLatin1, []byte{'H', 0xE9}},
+		{"H€", EncodingLatin1, []byte{'H', '?'}},
+	}
+	for _, tt := range tests {
+		got, err := EncodeText(tt.text, tt.enc)
+		if err != nil || !bytes.Equal(got, tt.want) {
+			t.Errorf("EncodeText(%q, %d) = %v, %v; expected %v", tt.text, tt.enc, got, err, tt.want)
+		}
+	}
+	if _, err := EncodeText("x", TextEncoding(99)); err == nil {
+		t.Errorf("Encode with unknown encoding should fail")
+	}
+}
+
+func TestEncodingTextWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var tw TextWriter = &EncodingTextWriter{Writer: buf, Encoding: EncodingLatin1}
+	tw.WriteText("Olá, ")
+	tw.WriteText("Mundo")
+	if want := []byte("Ol\xe1, Mundo"); !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("buffer = %q; expected %q", buf.Bytes(), want)
+	}
+}
+
+func TestLineBufferedWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewLineBufferedWriter(buf)
+	fmt.Fprint(w, "first")
+	if buf.Len() != 0 {
+		t.Errorf("incomplete line was written: %q", buf.String())
+	}
+	fmt.Fprint(w, " line\nsecond line\nthi")
+	if buf.String() != "first line\nsecond line\n" {
+		t.Errorf("buffer = %q; expected %q", buf.String(), "first line\nsecond line\n")
+	}
+	if err := w.Flush(); err != nil || buf.String() != "first line\nsecond line\nthi" {
+		t.Errorf("Flush = %v, buffer = %q", err, buf.String())
+	}
+}
+
+// Flaky Writer
+// The writer below fails the first writes, and then writes to the buffer.
+type flakyWriter struct {
+	buf   bytes.Buffer
+	fails int
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	if w.fails > 0 {
+		w.fails--
+		return 0, errWriteFailed
+	}
+	return w.buf.Write(p)
+}
+
+func TestLineBufferedWriterError(t *testing.T) {
+	fw := &flakyWriter{fails: 1}
+	w := NewLineBufferedWriter(fw)
+	w.Write([]byte("a"))
+	if n, err := w.Write([]byte("b\nc")); n != 0 || err == nil {
+		t.Errorf("Write = %d, %v; expected 0 and the error", n, err)
+	}
+	// The retry must not write "b\nc" twice.
+	if n, err := w.Write([]byte("b\nc")); n != 3 || err != nil {
+		t.Errorf("retry Write = %d, %v", n, err)
+	}
+	w.Flush()
+	if got := fw.buf.String(); got != "ab\nc" {
+		t.Errorf("written = %q; expected %q", got, "ab\nc")
+	}
+}
+
+func TestGzipByteWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	gz := NewGzipByteWriter(buf)
+	gz.WriteBytes([]byte("Hello "))
+	gz.WriteBytes([]byte("Gzip"))
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := gzip.NewReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil || string(b) != "Hello Gzip" {
+		t.Errorf("decompressed = %q, %v; expected %q", b, err, "Hello Gzip")
+	}
+
+	gz = NewGzipByteWriter(&failingWriter{limit: 0})
+	gz.WriteBytes([]byte("data"))
+	if err := gz.Close(); !errors.Is(err, errWriteFailed) {
+		t.Errorf("Close = %v; expected %v", err, errWriteFailed)
+	}
+}
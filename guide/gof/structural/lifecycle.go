@@ -0,0 +1,331 @@
+// Lifecycle Facade
+// The MessageProcessorFacade from the Facade example only calls the subsystems in a fixed order.
+// Real applications have many components (databases, loggers, listeners, caches) that depend on each other.
+// They must be started in the right order, checked while running, and stopped in the reverse order
+// when the process receives a termination signal.
+// The lifecycle manager below is a facade over all these operations: the consumer only registers the
+// components and calls Run, without knowing the order or the details of each component.
+
+package structural
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Component
+// The interface below defines a component managed by the lifecycle.
+// The component declares the names of the components it depends on, which are started before it.
+type Component interface {
+	Name() string
+	Dependencies() []string
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+	Health(ctx context.Context) error
+}
+
+// Lifecycle Errors
+// The errors below are returned when the components cannot be ordered.
+var (
+	ErrDuplicateComponent = errors.New("duplicate component")
+	ErrMissingDependency  = errors.New("missing dependency")
+	ErrDependencyCycle    = errors.New("dependency cycle")
+	ErrAlreadyStarted     = errors.New("lifecycle already started")
+)
+
+// Facade
+// The lifecycle keeps the registered components, and the components that were started (in start order).
+// The shutdown timeout limits the rollback when Start fails, and the shutdown of Run
+// (DefaultShutdownTimeout when it is zero).
+// The running flag is set from the beginning of Start until Stop, so the components are not started twice.
+type Lifecycle struct {
+	ShutdownTimeout time.Duration
+	mu              sync.Mutex
+	components      []Component
+	started         []Component
+	running         bool
+}
+
+const DefaultShutdownTimeout = 30 * time.Second
+
+// Register
+// The method below adds components to the lifecycle.
+// The names must be unique, since they are used to resolve the dependencies.
+func (l *Lifecycle) Register(components ...Component) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, c := range components {
+		for _, r := range l.components {
+			if r.Name() == c.Name() {
+				return fmt.Errorf("%w: %s", ErrDuplicateComponent, c.Name())
+			}
+		}
+		l.components = append(l.components, c)
+	}
+	return nil
+}
+
+// Order
+// The method below sorts the components in topological order, so each component comes after its dependencies.
+// It uses a depth-first search, which detects cycles when a component is visited while it is still in progress.
+// Components without dependencies between them keep the registration order.
+func (l *Lifecycle) Order() ([]Component, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	byName := map[string]Component{}
+	for _, c := range l.components {
+		byName[c.Name()] = c
+	}
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	order := make([]Component, 0, len(l.components))
+	var path []string
+	var visit func(c Component) error
+	visit = func(c Component) error {
+		switch state[c.Name()] {
+		case visited:
+			return nil
+		case visiting:
+			i := slices.Index(path, c.Name())
+			return fmt.Errorf("%w: %v", ErrDependencyCycle, append(path[i:], c.Name()))
+		}
+		state[c.Name()] = visiting
+		path = append(path, c.Name())
+		for _, dep := range c.Dependencies() {
+			d, ok := byName[dep]
+			if !ok {
+				return fmt.Errorf("%w: %s requires %s", ErrMissingDependency, c.Name(), dep)
+			}
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[c.Name()] = visited
+		order = append(order, c)
+		return nil
+	}
+	for _, c := range l.components {
+		if err := visit(c); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Start
+// The method below starts the components in topological order.
+// If a component fails to start, or the context is canceled, the components already started are stopped in
+// reverse order (rollback), and the start error is returned together with any rollback error.
+// The rollback is not canceled with the context, but it is limited by the shutdown timeout.
+// Calling Start again before Stop returns ErrAlreadyStarted.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	order, err := l.Order()
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	if l.running {
+		l.mu.Unlock()
+		return ErrAlreadyStarted
+	}
+	l.running = true
+	l.mu.Unlock()
+	rollback := func(err error) error {
+		stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), l.shutdownTimeout())
+		defer cancel()
+		return errors.Join(err, l.Stop(stopCtx))
+	}
+	for _, c := range order {
+		if err := ctx.Err(); err != nil {
+			return rollback(fmt.Errorf("start %s: %w", c.Name(), err))
+		}
+		if err := c.Start(ctx); err != nil {
+			return rollback(fmt.Errorf("start %s: %w", c.Name(), err))
+		}
+		l.mu.Lock()
+		l.started = append(l.started, c)
+		l.mu.Unlock()
+	}
+	return nil
+}
+
+// Stop
+// The method below stops the started components in reverse order, so no component is stopped before
+// the components that depend on it.
+// All the components are stopped, one at a time, even if some of them fail, and the errors are joined.
+// The components must return when the context expires. After that, the remaining components are still stopped
+// in order with the expired context, so they can release their resources without waiting (e.g., close the
+// connections instead of draining them).
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	started := l.started
+	l.started, l.running = nil, false
+	l.mu.Unlock()
+	var errs []error
+	for _, c := range slices.Backward(started) {
+		if err := c.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", c.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Health
+// The method below checks the health of the started components.
+// It returns a report with the error of each component (nil when the component is healthy).
+func (l *Lifecycle) Health(ctx context.Context) HealthReport {
+	l.mu.Lock()
+	started := slices.Clone(l.started)
+	l.mu.Unlock()
+	report := HealthReport{}
+	for _, c := range started {
+		report[c.Name()] = c.Health(ctx)
+	}
+	return report
+}
+
+// Health Report
+// The report maps each component name to its health error.
+type HealthReport map[string]error
+
+// Healthy
+// The method below returns true when all the components are healthy.
+func (r HealthReport) Healthy() bool {
+	for _, err := range r {
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Run
+// The method below is the main operation of the facade.
+// It starts the components, waits until the context is canceled or the process receives SIGINT or SIGTERM,
+// and then stops the components, giving them at most the shutdown timeout to finish.
+// The same timeout limits the rollback when the start fails or is interrupted.
+func (l *Lifecycle) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := l.Start(ctx); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), l.shutdownTimeout())
+	defer cancel()
+	return l.Stop(shutdownCtx)
+}
+
+// shutdownTimeout returns the shutdown timeout, or DefaultShutdownTimeout when it is not set.
+func (l *Lifecycle) shutdownTimeout() time.Duration {
+	if l.ShutdownTimeout <= 0 {
+		return DefaultShutdownTimeout
+	}
+	return l.ShutdownTimeout
+}
+
+// Stage
+// The enumeration below defines the stages of a component, used by the fake components to simulate failures.
+type Stage int
+
+const (
+	NoStage Stage = iota
+	StartStage
+	HealthStage
+	StopStage
+)
+
+// Event Log
+// The event log records the events of the fake components, in the order they happen.
+// It is shared by all the components of a lifecycle, so it is guarded by a mutex.
+type EventLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+// Event Log Functions
+// The Add function records an event, and the Events function returns a copy of the recorded events.
+func (l *EventLog) Add(event string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+func (l *EventLog) Events() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.events)
+}
+
+// Fake Component
+// The fake component below is used to demonstrate the lifecycle.
+// It records its events in the shared log, fails on the FailOn stage, and waits StopDelay when stopping.
+type FakeComponent struct {
+	ID        string
+	Deps      []string
+	FailOn    Stage
+	StopDelay time.Duration
+	Log       *EventLog
+}
+
+// Fake Component Constructor
+// The constructor creates a fake component that records its events in the given log.
+func NewFakeComponent(id string, log *EventLog, deps ...string) *FakeComponent {
+	return &FakeComponent{ID: id, Deps: deps, Log: log}
+}
+
+// Fake Component Implementation
+// The functions below implement the Component interface.
+func (c *FakeComponent) Name() string           { return c.ID }
+func (c *FakeComponent) Dependencies() []string { return c.Deps }
+func (c *FakeComponent) Start(ctx context.Context) error {
+	return c.event(StartStage, "start")
+}
+func (c *FakeComponent) Health(ctx context.Context) error {
+	return c.event(HealthStage, "health")
+}
+func (c *FakeComponent) Stop(ctx context.Context) error {
+	if c.StopDelay > 0 {
+		select {
+		case <-time.After(c.StopDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return c.event(StopStage, "stop")
+}
+func (c *FakeComponent) event(stage Stage, name string) error {
+	if c.FailOn == stage {
+		c.Log.Add(name + " " + c.ID + " failed")
+		return fmt.Errorf("%s failed on %s", c.ID, name)
+	}
+	c.Log.Add(name + " " + c.ID)
+	return nil
+}
+
+// Test Lifecycle
+// The test function registers the components in any order, and the facade starts them in dependency order.
+// The listener fails to start, so the components already started are rolled back.
+func TestLifecycle() {
+	log := &EventLog{}
+	db := NewFakeComponent("db", log)
+	logger := NewFakeComponent("logger", log)
+	listener := NewFakeComponent("listener", log, "db", "logger")
+	listener.FailOn = StartStage
+
+	lc := &Lifecycle{}
+	lc.Register(listener, logger, db)
+	err := lc.Start(context.Background())
+	fmt.Println(err)          // Output: start listener: listener failed on start
+	fmt.Println(log.Events()) // Output: [start db start logger start listener failed stop logger stop db]
+}
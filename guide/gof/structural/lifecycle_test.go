@@ -0,0 +1,259 @@
+package structural
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLifecycle(t *testing.T, components ...Component) *Lifecycle {
+	t.Helper()
+	lc := &Lifecycle{}
+	if err := lc.Register(components...); err != nil {
+		t.Fatal(err)
+	}
+	return lc
+}
+
+func assertEvents(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if !slices.Equal(got, want) {
+		t.Errorf("events = %q; expected %q", got, want)
+	}
+}
+
+func TestLifecycleOrder(t *testing.T) {
+	log := &EventLog{}
+	lc := newTestLifecycle(t,
+		NewFakeComponent("api", log, "cache", "db"),
+		NewFakeComponent("cache", log, "config"),
+		NewFakeComponent("db", log, "config"),
+		NewFakeComponent("config", log),
+	)
+	if err := lc.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := lc.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertEvents(t, log.Events(),
+		"start config", "start cache", "start db", "start api",
+		"stop api", "stop db", "stop cache", "stop config",
+	)
+}
+
+func TestLifecycleOrderErrors(t *testing.T) {
+	log := &EventLog{}
+	lc := newTestLifecycle(t, NewFakeComponent("a", log, "b"))
+	if err := lc.Start(context.Background()); !errors.Is(err, ErrMissingDependency) {
+		t.Errorf("Start = %v; expected %v", err, ErrMissingDependency)
+	}
+
+	lc = newTestLifecycle(t,
+		NewFakeComponent("a", log, "b"),
+		NewFakeComponent("b", log, "c"),
+		NewFakeComponent("c", log, "a"),
+	)
+	err := lc.Start(context.Background())
+	if !errors.Is(err, ErrDependencyCycle) || !strings.Contains(err.Error(), "[a b c a]") {
+		t.Errorf("Start = %v; expected %v with the cycle path", err, ErrDependencyCycle)
+	}
+	if len(log.Events()) != 0 {
+		t.Errorf("no component should start when the order is invalid: %q", log.Events())
+	}
+
+	err = lc.Register(NewFakeComponent("a", log))
+	if !errors.Is(err, ErrDuplicateComponent) {
+		t.Errorf("Register = %v; expected %v", err, ErrDuplicateComponent)
+	}
+}
+
+func TestLifecycleStartRollback(t *testing.T) {
+	log := &EventLog{}
+	cache := NewFakeComponent("cache", log, "db")
+	cache.FailOn = StartStage
+	lc := newTestLifecycle(t,
+		NewFakeComponent("db", log),
+		NewFakeComponent("logger", log),
+		cache,
+		NewFakeComponent("api", log, "cache"),
+	)
+	err := lc.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "start cache") {
+		t.Fatalf("Start = %v; expected the cache start error", err)
+	}
+	assertEvents(t, log.Events(), "start db", "start logger", "start cache failed", "stop logger", "stop db")
+	if report := lc.Health(context.Background()); len(report) != 0 {
+		t.Errorf("no component should remain started after a rollback: %v", report)
+	}
+}
+
+func TestLifecycleRollbackFailure(t *testing.T) {
+	log := &EventLog{}
+	db := NewFakeComponent("db", log)
+	db.FailOn = StopStage
+	api := NewFakeComponent("api", log, "db")
+	api.FailOn = StartStage
+	lc := newTestLifecycle(t, db, api)
+	err := lc.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "start api") || !strings.Contains(err.Error(), "stop db") {
+		t.Errorf("Start = %v; expected both the start and the rollback errors", err)
+	}
+}
+
+func TestLifecycleHealth(t *testing.T) {
+	log := &EventLog{}
+	cache := NewFakeComponent("cache", log)
+	cache.FailOn = HealthStage
+	lc := newTestLifecycle(t, NewFakeComponent("db", log), cache)
+	if err := lc.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	report := lc.Health(context.Background())
+	if report.Healthy() || report["db"] != nil || report["cache"] == nil {
+		t.Errorf("report = %v; expected only the cache to be unhealthy", report)
+	}
+}
+
+func TestLifecycleStopFailure(t *testing.T) {
+	log := &EventLog{}
+	cache := NewFakeComponent("cache", log, "db")
+	cache.FailOn = StopStage
+	lc := newTestLifecycle(t, NewFakeComponent("db", log), cache)
+	lc.Start(context.Background())
+	err := lc.Stop(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "stop cache") {
+		t.Errorf("Stop = %v; expected the cache stop error", err)
+	}
+	assertEvents(t, log.Events(), "start db", "start cache", "stop cache failed", "stop db")
+}
+
+func TestLifecycleStopTimeout(t *testing.T) {
+	log := &EventLog{}
+	slow := NewFakeComponent("slow", log)
+	slow.StopDelay = time.Hour
+	lc := newTestLifecycle(t, slow)
+	lc.Start(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := lc.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop = %v; expected %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestLifecycleStopOrderAfterTimeout(t *testing.T) {
+	log := &EventLog{}
+	db := NewFakeComponent("db", log)
+	slow := NewFakeComponent("slow", log, "db")
+	slow.StopDelay = time.Hour
+	lc := newTestLifecycle(t, db, slow)
+	lc.Start(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := lc.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "stop db") {
+		t.Errorf("Stop = %v; expected only the slow component to time out", err)
+	}
+	// The db is stopped after the slow component returned, not at the same time.
+	assertEvents(t, log.Events(), "start db", "start slow", "stop db")
+}
+
+func TestLifecycleRollbackTimeout(t *testing.T) {
+	log := &EventLog{}
+	slow := NewFakeComponent("slow", log)
+	slow.StopDelay = time.Hour
+	api := NewFakeComponent("api", log, "slow")
+	api.FailOn = StartStage
+	lc := newTestLifecycle(t, slow, api)
+	lc.ShutdownTimeout = 10 * time.Millisecond
+	start := time.Now()
+	err := lc.Start(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "start api") {
+		t.Errorf("Start = %v; expected the start error and the rollback timeout", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("the rollback took %v; expected the shutdown timeout", d)
+	}
+}
+
+func TestLifecycleRun(t *testing.T) {
+	log := &EventLog{}
+	lc := newTestLifecycle(t, NewFakeComponent("db", log), NewFakeComponent("api", log, "db"))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- lc.Run(ctx) }()
+	for len(log.Events()) != 2 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	assertEvents(t, log.Events(), "start db", "start api", "stop api", "stop db")
+}
+
+func TestLifecycleRunSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupt signals cannot be sent on windows")
+	}
+	log := &EventLog{}
+	lc := newTestLifecycle(t, NewFakeComponent("db", log))
+	done := make(chan error)
+	go func() { done <- lc.Run(context.Background()) }()
+	for len(log.Events()) != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	p, _ := os.FindProcess(os.Getpid())
+	if err := p.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not stop after SIGINT")
+	}
+	assertEvents(t, log.Events(), "start db", "stop db")
+}
+
+func TestLifecycleStartTwice(t *testing.T) {
+	log := &EventLog{}
+	lc := newTestLifecycle(t, NewFakeComponent("db", log))
+	if err := lc.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := lc.Start(context.Background()); !errors.Is(err, ErrAlreadyStarted) {
+		t.Errorf("second Start = %v; expected %v", err, ErrAlreadyStarted)
+	}
+	if err := lc.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := lc.Start(context.Background()); err != nil {
+		t.Errorf("Start after Stop = %v", err)
+	}
+	assertEvents(t, log.Events(), "start db", "stop db", "start db")
+}
+
+// Run uses the ShutdownTimeout field, with DefaultShutdownTimeout when it is zero.
+func TestLifecycleRunDefaultTimeout(t *testing.T) {
+	log := &EventLog{}
+	db := NewFakeComponent("db", log)
+	db.StopDelay = 20 * time.Millisecond
+	lc := newTestLifecycle(t, db)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- lc.Run(ctx) }()
+	for len(log.Events()) != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run = %v; expected the default timeout to let db stop", err)
+	}
+}
@@ -4,6 +4,8 @@
 
 package structural
 
+import "sync"
+
 // Protocol
 // We will define this interface to represent a common protocol.
 // This will be implemented by the main service, and by the proxy.
@@ -28,21 +30,34 @@ func (d *DataService) Query(query string) string {
 // It will be used to control access to the main service.
 // In this case, it will cache the results of the queries, and will call the main service only if the result
 // is not in the cache.
+// The cache is a plain map, which is not safe for concurrent use, so it is guarded by a read-write mutex.
+// Without it, concurrent queries would crash the program with "fatal error: concurrent map writes".
+// The cache is unexported, so it can only be used through Query, which holds the lock. It is created by the first
+// query that misses, so the zero value (with a Service) is ready to use.
 type CachedDataService struct {
-	Cache   map[string]string
 	Service DataAccess
+	mu      sync.RWMutex
+	cache   map[string]string
 }
 
 // Proxy Implementation
 // This is the same method from the main service, however, it will check if the result is in the cache.
 // If it is, it will return the cached result.
+// The lock is not held while the main service runs, so two concurrent misses may both compute the result.
 func (d *CachedDataService) Query(query string) string {
-	res, ok := d.Cache[query]
+	d.mu.RLock()
+	res, ok := d.cache[query]
+	d.mu.RUnlock()
 	if ok {
 		return res
 	}
 	res = d.Service.Query(query)
-	d.Cache[query] = res
+	d.mu.Lock()
+	if d.cache == nil {
+		d.cache = map[string]string{}
+	}
+	d.cache[query] = res
+	d.mu.Unlock()
 	return res
 }
 
@@ -56,10 +71,7 @@ func TestProxy() {
 	ds.Query("abc") // Computed
 	ds.Query("abc") // Computed
 
-	cds := &CachedDataService{
-		Cache:   map[string]string{},
-		Service: ds,
-	}
+	cds := &CachedDataService{Service: ds}
 	cds.Query("abc") // Computed
 	cds.Query("abc") // From Cache
 }
@@ -0,0 +1,302 @@
+// Scene Graph
+// The Canvas from the Composite example only concatenates the strings returned by its shapes.
+// A scene graph is a more realistic use of the Composite pattern: it is a tree where the leafs are shapes,
+// and the composites are groups that carry a transform (translation and scale) applied to all their children.
+// Since groups can contain other groups, the transforms are combined recursively from the root to the leafs.
+// The scene is rendered by an ASCII rasterizer, which draws the shapes into a character buffer.
+
+package structural
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Transform
+// The transform below maps a point from the local space of a node to the space of its parent.
+// The point is scaled first, and then translated.
+type Transform struct {
+	TX, TY float64
+	SX, SY float64
+}
+
+// Identity Transform
+// The identity transform does not change the points.
+// The transform functions are prefixed with "Scene", since they share the package with the other structural examples.
+func SceneIdentity() Transform {
+	return Transform{SX: 1, SY: 1}
+}
+
+// Transform Functions
+// The functions below create transforms that only translate or only scale the points.
+func SceneTranslate(tx, ty float64) Transform {
+	return Transform{TX: tx, TY: ty, SX: 1, SY: 1}
+}
+func SceneScale(sx, sy float64) Transform {
+	return Transform{SX: sx, SY: sy}
+}
+
+// Apply
+// The method below applies the transform to a point.
+func (t Transform) Apply(x, y float64) (float64, float64) {
+	return x*t.SX + t.TX, y*t.SY + t.TY
+}
+
+// Then
+// The method below combines the transform of a parent (t) with the transform of a child.
+// Applying the result is the same as applying the child transform and then the parent transform.
+func (t Transform) Then(child Transform) Transform {
+	return Transform{
+		TX: child.TX*t.SX + t.TX,
+		TY: child.TY*t.SY + t.TY,
+		SX: t.SX * child.SX,
+		SY: t.SY * child.SY,
+	}
+}
+
+// Raster
+// The raster is a character buffer with a fixed size.
+// The origin (0, 0) is the top-left cell, and the Y axis points down.
+type Raster struct {
+	Width, Height int
+	cells         []rune
+}
+
+// Raster Constructor
+// The constructor creates a raster filled with spaces.
+func NewRaster(width, height int) *Raster {
+	r := &Raster{Width: width, Height: height, cells: make([]rune, width*height)}
+	r.Clear()
+	return r
+}
+
+// Raster Functions
+// The functions below manipulate the cells of the raster.
+// Cells outside the raster are ignored, so shapes can be partially visible.
+func (r *Raster) Clear() {
+	for i := range r.cells {
+		r.cells[i] = ' '
+	}
+}
+func (r *Raster) Set(x, y int, c rune) {
+	if x < 0 || y < 0 || x >= r.Width || y >= r.Height {
+		return
+	}
+	r.cells[y*r.Width+x] = c
+}
+func (r *Raster) At(x, y int) rune {
+	if x < 0 || y < 0 || x >= r.Width || y >= r.Height {
+		return 0
+	}
+	return r.cells[y*r.Width+x]
+}
+
+// String Method
+// The raster is converted to a string with one line per row.
+// Trailing spaces are removed from each line.
+func (r *Raster) String() string {
+	var sb strings.Builder
+	for y := range r.Height {
+		row := string(r.cells[y*r.Width : (y+1)*r.Width])
+		sb.WriteString(strings.TrimRight(row, " "))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// Scene Node
+// The interface below defines the common operation for both leaf and composite nodes.
+// The transform received by the node is the combination of the transforms of all its ancestors.
+type SceneNode interface {
+	Render(r *Raster, t Transform)
+}
+
+// Composite
+// The group is the composite node of the scene graph.
+// It applies its own transform to all its children, which can be shapes or other groups.
+type Group struct {
+	Transform Transform
+	Children  []SceneNode
+}
+
+// Group Constructor
+// The constructor creates a group with the given transform and children.
+func NewGroup(t Transform, children ...SceneNode) *Group {
+	return &Group{Transform: t, Children: children}
+}
+
+// Group Functions
+// The Add function appends children to the group, and returns the group to allow chaining.
+func (g *Group) Add(children ...SceneNode) *Group {
+	g.Children = append(g.Children, children...)
+	return g
+}
+
+// Composite Implementation
+// The group delegates the rendering to its children, combining its transform with the received one.
+func (g *Group) Render(r *Raster, t Transform) {
+	world := t.Then(g.Transform)
+	for _, child := range g.Children {
+		child.Render(r, world)
+	}
+}
+
+// Leafs
+// The shapes below are the leafs of the scene graph.
+// Their coordinates are defined in the local space of their parent group.
+// The Ink is the character used to draw the shape, and Filled fills the shape instead of drawing only its outline.
+// The rectangle and the line both include their end points: a rectangle from X to X+W covers the same cells as a
+// line from X to X+W, so the edges of the shapes still meet after a scale.
+type (
+	CircleShape struct {
+		CX, CY, Radius float64
+		Ink            rune
+		Filled         bool
+	}
+	RectShape struct {
+		X, Y, W, H float64
+		Ink        rune
+		Filled     bool
+	}
+	LineShape struct {
+		X1, Y1, X2, Y2 float64
+		Ink            rune
+	}
+)
+
+// Leaf Implementation (Circle)
+// The circle is drawn as an ellipse, since the scale of the X and Y axes can be different.
+// A cell is inside the ellipse when its normalized distance to the center is at most one.
+// Half a cell is added to the radii, so the cells crossed by the ellipse are inside as well.
+// A cell belongs to the outline when it is inside, but at least one of its neighbours is outside.
+func (c CircleShape) Render(r *Raster, t Transform) {
+	cx, cy := t.Apply(c.CX, c.CY)
+	rx, ry := math.Abs(c.Radius*t.SX), math.Abs(c.Radius*t.SY)
+	if rx == 0 || ry == 0 {
+		r.Set(roundCell(cx), roundCell(cy), c.Ink)
+		return
+	}
+	inside := func(x, y int) bool {
+		dx := (float64(x) - cx) / (rx + 0.5)
+		dy := (float64(y) - cy) / (ry + 0.5)
+		return dx*dx+dy*dy <= 1
+	}
+	for y := int(math.Floor(cy - ry - 1)); y <= int(math.Ceil(cy+ry+1)); y++ {
+		for x := int(math.Floor(cx - rx - 1)); x <= int(math.Ceil(cx+rx+1)); x++ {
+			if !inside(x, y) {
+				continue
+			}
+			edge := !inside(x-1, y) || !inside(x+1, y) || !inside(x, y-1) || !inside(x, y+1)
+			if c.Filled || edge {
+				r.Set(x, y, c.Ink)
+			}
+		}
+	}
+}
+
+// Leaf Implementation (Rectangle)
+// The corners of the rectangle are transformed, and then normalized, since a negative scale flips the rectangle.
+// Both corners are drawn, so the rectangle is W+1 cells wide and H+1 cells high.
+func (s RectShape) Render(r *Raster, t Transform) {
+	x1, y1 := t.Apply(s.X, s.Y)
+	x2, y2 := t.Apply(s.X+s.W, s.Y+s.H)
+	left, right := roundCell(math.Min(x1, x2)), roundCell(math.Max(x1, x2))
+	top, bottom := roundCell(math.Min(y1, y2)), roundCell(math.Max(y1, y2))
+	for y := top; y <= bottom; y++ {
+		for x := left; x <= right; x++ {
+			edge := x == left || x == right || y == top || y == bottom
+			if s.Filled || edge {
+				r.Set(x, y, s.Ink)
+			}
+		}
+	}
+}
+
+// Leaf Implementation (Line)
+// The line is drawn with the Bresenham's algorithm, which only uses integer operations.
+func (l LineShape) Render(r *Raster, t Transform) {
+	fx1, fy1 := t.Apply(l.X1, l.Y1)
+	fx2, fy2 := t.Apply(l.X2, l.Y2)
+	x1, y1, x2, y2 := roundCell(fx1), roundCell(fy1), roundCell(fx2), roundCell(fy2)
+	dx, dy := absInt(x2-x1), -absInt(y2-y1)
+	sx, sy := signInt(x2-x1), signInt(y2-y1)
+	e := dx + dy
+	for {
+		r.Set(x1, y1, l.Ink)
+		if x1 == x2 && y1 == y2 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x1 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y1 += sy
+		}
+	}
+}
+
+// Scene
+// The scene holds the root of the graph and the size of the raster.
+// It implements the Graphic interface from the Composite example, so it can be placed in a Canvas.
+type Scene struct {
+	Width, Height int
+	Root          SceneNode
+}
+
+// Scene Implementation
+// The scene renders the whole graph into a new raster, starting with the identity transform.
+func (s *Scene) Draw() string {
+	r := NewRaster(s.Width, s.Height)
+	if s.Root != nil {
+		s.Root.Render(r, SceneIdentity())
+	}
+	return r.String()
+}
+
+// Helper Functions
+// The functions below are used by the rasterizer.
+func roundCell(f float64) int {
+	return int(math.Floor(f + 0.5))
+}
+func absInt(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+func signInt(i int) int {
+	switch {
+	case i < 0:
+		return -1
+	case i > 0:
+		return 1
+	}
+	return 0
+}
+
+// Test Scene Graph
+// The test function composes a house with a group, and places two houses and a sun in the scene.
+// The second house is the same group, translated and scaled by its parent group.
+func TestSceneGraph() {
+	house := NewGroup(SceneIdentity(),
+		RectShape{X: 0, Y: 4, W: 9, H: 5, Ink: '#'},
+		LineShape{X1: 0, Y1: 4, X2: 5, Y2: 0, Ink: '/'},
+		LineShape{X1: 5, Y1: 0, X2: 9, Y2: 4, Ink: '\\'},
+		RectShape{X: 4, Y: 7, W: 1, H: 2, Ink: '|', Filled: true},
+	)
+	scene := &Scene{
+		Width:  44,
+		Height: 12,
+		Root: NewGroup(SceneTranslate(1, 1),
+			house,
+			NewGroup(SceneTranslate(14, 0).Then(SceneScale(2, 1)), house),
+			CircleShape{CX: 38, CY: 2, Radius: 2, Ink: 'o'},
+		),
+	}
+	canvas := Canvas{Shapes: []Graphic{scene}} // The scene is a Graphic as well
+	fmt.Print(canvas.Draw())
+}
@@ -0,0 +1,76 @@
+package structural
+
+import (
+	"testing"
+
+	"guide/internal/golden"
+)
+
+// Golden Files
+// The rendered scenes and documents are compared with the files in the testdata folder, using the golden
+// helper shared by the module. To update them after an intended change, run: "go test -run TestScene -update".
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	golden.Assert(t, name, []byte(got))
+}
+
+func sceneHouse() *Group {
+	return NewGroup(SceneIdentity(),
+		RectShape{X: 0, Y: 4, W: 9, H: 5, Ink: '#'},
+		LineShape{X1: 0, Y1: 4, X2: 5, Y2: 0, Ink: '/'},
+		LineShape{X1: 5, Y1: 0, X2: 9, Y2: 4, Ink: '\\'},
+		RectShape{X: 4, Y: 7, W: 1, H: 2, Ink: '|', Filled: true},
+	)
+}
+
+func TestSceneGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		scene *Scene
+	}{
+		{"scene_circle", &Scene{Width: 21, Height: 11, Root: CircleShape{CX: 10, CY: 5, Radius: 5, Ink: 'o'}}},
+		{"scene_circle_filled", &Scene{Width: 21, Height: 11, Root: CircleShape{CX: 10, CY: 5, Radius: 4, Ink: '@', Filled: true}}},
+		{"scene_shapes", &Scene{Width: 20, Height: 10, Root: NewGroup(SceneIdentity(),
+			RectShape{X: 1, Y: 1, W: 7, H: 3, Ink: '#'},
+			RectShape{X: 11, Y: 1, W: 7, H: 3, Ink: '%', Filled: true},
+			LineShape{X1: 0, Y1: 9, X2: 19, Y2: 6, Ink: '*'},
+		)}},
+		{"scene_nested", &Scene{Width: 44, Height: 12, Root: NewGroup(SceneTranslate(1, 1),
+			sceneHouse(),
+			NewGroup(SceneTranslate(14, 0).Then(SceneScale(2, 1)), sceneHouse()),
+			CircleShape{CX: 38, CY: 2, Radius: 2, Ink: 'o'},
+		)}},
+		{"scene_ellipse_flipped", &Scene{Width: 30, Height: 9, Root: NewGroup(SceneTranslate(29, 0).Then(SceneScale(-1, 1)),
+			NewGroup(SceneScale(3, 1), CircleShape{CX: 4, CY: 4, Radius: 3, Ink: '.'}),
+			LineShape{X1: 0, Y1: 0, X2: 5, Y2: 8, Ink: '\\'},
+		)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertGolden(t, tt.name, tt.scene.Draw())
+		})
+	}
+}
+
+func TestTransformThen(t *testing.T) {
+	parent := SceneTranslate(10, 5).Then(SceneScale(2, 3))
+	child := SceneTranslate(1, 1)
+	x, y := parent.Then(child).Apply(2, 2)
+	px, py := parent.Apply(child.Apply(2, 2))
+	if x != px || y != py {
+		t.Errorf("Then(child).Apply = (%v, %v); expected (%v, %v)", x, y, px, py)
+	}
+	if x != 16 || y != 14 {
+		t.Errorf("Apply = (%v, %v); expected (16, 14)", x, y)
+	}
+}
+
+func TestSceneInCanvas(t *testing.T) {
+	canvas := Canvas{Shapes: []Graphic{
+		Circle{},
+		&Scene{Width: 3, Height: 1, Root: LineShape{X1: 0, Y1: 0, X2: 2, Y2: 0, Ink: '-'}},
+	}}
+	if got := canvas.Draw(); got != "()---\n" {
+		t.Errorf("Draw() = %q; expected %q", got, "()---\n")
+	}
+}
@@ -0,0 +1,100 @@
+// Code generated by "enumgen -type=Color"; DO NOT EDIT.
+
+package patterns
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the enumgen command to generate them again.
+	var x [1]struct{}
+	_ = x[Red-0]
+	_ = x[Green-1]
+	_ = x[Blue-2]
+}
+
+var _ColorValues = []Color{Red, Green, Blue}
+
+// String returns the name of the Color value.
+func (i Color) String() string {
+	switch i {
+	case Red:
+		return "Red"
+	case Green:
+		return "Green"
+	case Blue:
+		return "Blue"
+	}
+	return "Color(" + strconv.FormatInt(int64(i), 10) + ")"
+}
+
+// ParseColor returns the Color value with the name.
+func ParseColor(s string) (Color, error) {
+	switch s {
+	case "Red":
+		return Red, nil
+	case "Green":
+		return Green, nil
+	case "Blue":
+		return Blue, nil
+	}
+	return 0, fmt.Errorf("invalid Color: %q", s)
+}
+
+// ColorValues returns all the Color values, in declaration order.
+func ColorValues() []Color {
+	return append([]Color(nil), _ColorValues...)
+}
+
+// IsValid reports whether the value is one of the Color constants.
+func (i Color) IsValid() bool {
+	switch i {
+	case Red, Green, Blue:
+		return true
+	}
+	return false
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (i Color) MarshalText() ([]byte, error) {
+	if !i.IsValid() {
+		return nil, fmt.Errorf("invalid Color: %d", i)
+	}
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (i *Color) UnmarshalText(text []byte) error {
+	v, err := ParseColor(string(text))
+	if err != nil {
+		return err
+	}
+	*i = v
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (i Color) MarshalJSON() ([]byte, error) {
+	if !i.IsValid() {
+		return nil, fmt.Errorf("invalid Color: %d", i)
+	}
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (i *Color) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("Color should be a string, got %s", data)
+	}
+	v, err := ParseColor(s)
+	if err != nil {
+		return err
+	}
+	*i = v
+	return nil
+}
@@ -5,7 +5,10 @@
 
 package patterns
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // Defining a Custom Type
 // We will define a custom type to represent out enumerated type.
@@ -21,24 +24,32 @@ const (
 
 // Defining an Array with All Enum Values
 // We can define an array to hold all the enum values.
+// The generated ColorValues function (see below) returns the same values, and it is updated automatically.
 var Colors = [3]Color{Red, Green, Blue}
 
-// String Map
-// We will define a map to convert the enum values to string representations.
+// String Method
+// We could write the String method by hand, with a map from the enum values to their names:
+var _ = `
 var colorToString = map[Color]string{
 	Red:   "Red",
 	Green: "Green",
 	Blue:  "Blue",
 }
 
-// String Method
-// We will implement a String method to convert the enum value to a string representation.
 func (c Color) String() string {
 	if str, ok := colorToString[c]; ok {
 		return str
 	}
 	return "Unknown"
 }
+`
+
+// Generated Methods
+// However, the map must be updated by hand whenever a value is added.
+// Instead, the enumgen tool (see tools/enumgen) generates the methods in the color_enum.go file:
+// String, ParseColor, ColorValues, IsValid, and the text and JSON marshaling methods.
+//
+//go:generate enumgen -type=Color
 
 // Using Enum
 // This function demonstrates how to use the enum type and its methods.
@@ -47,15 +58,34 @@ func UsingEnum() {
 	// Get Enum Value
 	// We can get the enum value by accessing the enum constant directly.
 	x := Red
-	fmt.Println("x:", x) // Output: x: 0
+	fmt.Println("x:", int(x)) // Output: x: 0
 
 	// Get Enum String Value
 	// We can get the string representation of the enum value by calling the String method.
+	// The fmt functions call the String method automatically.
 	fmt.Println("x:", x.String()) // Output: x: Red
+	fmt.Println("x:", x)          // Output: x: Red
 
 	// Iterate Over Enum Values
 	// We can iterate over the enum values using a for loop.
-	for _, c := range Colors {
-		fmt.Println(c, c.String()) // Output: 0 Red, 1 Green, 2 Blue
+	for _, c := range ColorValues() {
+		fmt.Println(int(c), c.String()) // Output: 0 Red, 1 Green, 2 Blue
 	}
+
+	// Parse and Validate
+	// The names can be converted back to enum values, and invalid values can be detected.
+	c, err := ParseColor("Green")
+	fmt.Println(c, err) // Output: Green <nil>
+	_, err = ParseColor("Purple")
+	fmt.Println(err)                // Output: invalid Color: "Purple"
+	fmt.Println(Color(7).IsValid()) // Output: false
+	fmt.Println(Color(7))           // Output: Color(7)
+
+	// JSON
+	// The enum values are encoded by name, which is easier to read, and does not break when the values are reordered.
+	data, _ := json.Marshal(map[string]Color{"background": Blue})
+	fmt.Println(string(data)) // Output: {"background":"Blue"}
+	var decoded struct{ Color Color }
+	err = json.Unmarshal([]byte(`{"Color": "Yellow"}`), &decoded)
+	fmt.Println(err) // Output: invalid Color: "Yellow"
 }
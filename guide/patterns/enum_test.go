@@ -0,0 +1,51 @@
+package patterns
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestColorEnum(t *testing.T) {
+	if !slices.Equal(ColorValues(), Colors[:]) {
+		t.Errorf("ColorValues() = %v; expected %v", ColorValues(), Colors)
+	}
+	for _, c := range Colors {
+		if !c.IsValid() {
+			t.Errorf("%v.IsValid() = false", c)
+		}
+		if p, err := ParseColor(c.String()); err != nil || p != c {
+			t.Errorf("ParseColor(%q) = %v, %v; expected %v", c.String(), p, err, c)
+		}
+	}
+	if Color(3).IsValid() || Color(3).String() != "Color(3)" {
+		t.Errorf("Color(3) must be invalid")
+	}
+	// The returned slice is a copy.
+	ColorValues()[0] = Blue
+	if ColorValues()[0] != Red {
+		t.Errorf("ColorValues() must return a copy")
+	}
+}
+
+func TestColorJSON(t *testing.T) {
+	type palette struct {
+		Primary Color
+		Others  map[Color]int
+	}
+	in := palette{Primary: Green, Others: map[Color]int{Red: 1, Blue: 2}}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Primary":"Green","Others":{"Blue":2,"Red":1}}`; string(data) != want {
+		t.Errorf("Marshal = %s; expected %s", data, want)
+	}
+	var out palette
+	if err := json.Unmarshal(data, &out); err != nil || out.Primary != Green || out.Others[Blue] != 2 {
+		t.Errorf("Unmarshal = %+v, %v", out, err)
+	}
+	if err := json.Unmarshal([]byte(`{"Primary":"Pink"}`), &out); err == nil || err.Error() != `invalid Color: "Pink"` {
+		t.Errorf("Unmarshal error = %v", err)
+	}
+}
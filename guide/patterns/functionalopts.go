@@ -7,67 +7,506 @@
 
 package patterns
 
-import "fmt"
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Struct
 // The struct below will be used to demonstrate the functional options pattern.
 // We will define a single constructor function that takes a variable number of options to configure the struct.
+// The struct tags are read by the buildergen tool (see tools/buildergen), which generates the ServerBuilder.
+// This way, we can compare both approaches: functional options and builders.
+//
+//go:generate buildergen -type=Server
 type Server struct {
-	Host string
-	Port int
-	TLS  bool
+	Host        string `builder:"default=localhost"`
+	Port        int    `builder:"default=8080"`
+	TLS         bool
+	CertFile    string
+	KeyFile     string
+	ReadTimeout time.Duration `builder:"default=5 * time.Second"`
 }
 
-// Option
-// The Option type is a function that takes a pointer to the Server struct.
-// This allows us to define various options that can be applied to the Server struct.
+// Validate
+// The method below validates the server configuration as a whole.
+// Each option validates its own value, but some rules depend on more than one field (e.g., TLS needs certificates).
+// It is also called by the generated builder when building the server.
+func (s *Server) Validate() error {
+	var errs []error
+	if s.Port < 0 || s.Port > 65535 {
+		errs = append(errs, fmt.Errorf("invalid port: %d", s.Port))
+	}
+	if s.TLS && (s.CertFile == "" || s.KeyFile == "") {
+		errs = append(errs, errors.New("tls requires a certificate and a key file"))
+	}
+	return errors.Join(errs...)
+}
+
+// Naive Option
+// The simplest option is a function that changes the server.
+// However, it cannot fail, so invalid values like WithPort(-1) are silently accepted:
+var _ = `
 type Option func(*Server)
 
+func WithPort(port int) Option {
+	return func(s *Server) {
+		s.Port = port
+	}
+}
+`
+
+// Option
+// Instead, the Option below is an interface, whose Apply method returns an error.
+// This allows us to define various options that can be applied to the Server struct, and validate their values.
+// Any function with the right signature can be used as an option, by converting it to OptionFunc.
+type Option interface {
+	Apply(s *Server) error
+}
+
+// Option Function
+// The OptionFunc type allows using plain functions as options.
+type OptionFunc func(*Server) error
+
+func (f OptionFunc) Apply(s *Server) error { return f(s) }
+func (f OptionFunc) String() string        { return "custom option" }
+
+// Setting
+// The setting is the option returned by the option functions below.
+// Besides changing the server, it keeps the name and the value of the option, and where it came from (its source).
+// This way, options can be printed for diagnostics, and the errors tell which option is invalid.
+type setting struct {
+	name   string
+	value  any
+	source string
+	fn     func(*Server) error
+}
+
+func (o setting) Apply(s *Server) error {
+	if err := o.fn(s); err != nil {
+		return fmt.Errorf("%v: %w", o, err)
+	}
+	return nil
+}
+func (o setting) String() string {
+	return fmt.Sprintf("%s=%v (%s)", o.name, o.value, o.source)
+}
+
+// Option Set
+// The option set groups options, and it is an option itself, so sets can be nested.
+// Applying the set applies all its options, and reports all the errors at once.
+// Printing the set prints one option per line.
+type OptionSet []Option
+
+func (set OptionSet) Apply(s *Server) error {
+	var errs []error
+	for _, o := range set {
+		if err := o.Apply(s); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+func (set OptionSet) String() string {
+	lines := make([]string, 0, len(set))
+	for _, o := range set {
+		if s := fmt.Sprint(o); s != "" {
+			lines = append(lines, s)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 // Option Functions
 // These functions return the Option type and allow us to set various fields in the Server struct.
-// Each function takes a pointer to the Server struct and modifies it accordingly.
+// Each option validates its value before changing the server.
 func WithHost(host string) Option {
-	return func(s *Server) {
-		s.Host = host
-	}
+	return withHost(host, "code")
 }
 func WithPort(port int) Option {
-	return func(s *Server) {
-		s.Port = port
-	}
+	return withPort(port, "code")
 }
 func WithTLS(enabled bool) Option {
-	return func(s *Server) {
+	return withTLS(enabled, "code")
+}
+func WithCertificate(certFile, keyFile string) Option {
+	return OptionSet{withCertFile(certFile, "code"), withKeyFile(keyFile, "code")}
+}
+func WithReadTimeout(timeout time.Duration) Option {
+	return withReadTimeout(timeout, "code")
+}
+
+// Option Implementations
+// The functions below create the settings, with the source of the value.
+// They are shared by the option functions, the environment variables and the config files.
+func withHost(host, source string) Option {
+	return setting{"host", host, source, func(s *Server) error {
+		if host == "" {
+			return errors.New("cannot be empty")
+		}
+		if strings.Contains(host, "/") {
+			return errors.New("must be a host name, not a URL")
+		}
+		s.Host = host
+		return nil
+	}}
+}
+func withPort(port int, source string) Option {
+	return setting{"port", port, source, func(s *Server) error {
+		if port < 0 || port > 65535 {
+			return errors.New("must be between 0 and 65535")
+		}
+		s.Port = port
+		return nil
+	}}
+}
+func withTLS(enabled bool, source string) Option {
+	return setting{"tls", enabled, source, func(s *Server) error {
 		s.TLS = enabled
+		return nil
+	}}
+}
+func withCertFile(file, source string) Option {
+	return setting{"cert_file", file, source, func(s *Server) error {
+		if file == "" {
+			return errors.New("cannot be empty")
+		}
+		s.CertFile = file
+		return nil
+	}}
+}
+func withKeyFile(file, source string) Option {
+	return setting{"key_file", file, source, func(s *Server) error {
+		if file == "" {
+			return errors.New("cannot be empty")
+		}
+		s.KeyFile = file
+		return nil
+	}}
+}
+func withReadTimeout(timeout time.Duration, source string) Option {
+	return setting{"read_timeout", timeout, source, func(s *Server) error {
+		if timeout < 0 {
+			return errors.New("cannot be negative")
+		}
+		s.ReadTimeout = timeout
+		return nil
+	}}
+}
+
+// Keys
+// The keys below are the names of the options in environment variables and config files.
+// Each key parses the text value, and returns the option.
+// Parsing errors are returned by an invalid option, so they are reported by NewServer with the other errors.
+var serverKeys = []struct {
+	name  string
+	parse func(value, source string) Option
+}{
+	{"host", withHost},
+	{"port", func(value, source string) Option {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return invalid("port", value, source, errors.New("must be a number"))
+		}
+		return withPort(port, source)
+	}},
+	{"tls", func(value, source string) Option {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return invalid("tls", value, source, errors.New("must be a boolean"))
+		}
+		return withTLS(enabled, source)
+	}},
+	{"cert_file", withCertFile},
+	{"key_file", withKeyFile},
+	{"read_timeout", func(value, source string) Option {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return invalid("read_timeout", value, source, errors.New("must be a duration (e.g., 5s)"))
+		}
+		return withReadTimeout(timeout, source)
+	}},
+}
+
+// Invalid Option
+// The function below returns an option that always fails.
+func invalid(name string, value any, source string, err error) Option {
+	return setting{name, value, source, func(*Server) error { return err }}
+}
+
+// Parse Key
+// The function below finds the key, and parses its value.
+func parseKey(key, value, source string) Option {
+	for _, k := range serverKeys {
+		if k.name == key {
+			return k.parse(value, source)
+		}
+	}
+	return invalid(key, value, source, errors.New("unknown option"))
+}
+
+// Environment Variables
+// The function below reads the options from the environment variables with the prefix.
+// For example, with the "SERVER" prefix, the port is read from the SERVER_PORT variable.
+// The variables are read when the function is called, and the missing variables are ignored.
+func FromEnv(prefix string) OptionSet {
+	var opts OptionSet
+	for _, k := range serverKeys {
+		name := strings.ToUpper(prefix + "_" + k.name)
+		if value, ok := os.LookupEnv(name); ok {
+			opts = append(opts, k.parse(value, "env "+name))
+		}
+	}
+	return opts
+}
+
+// Config Files
+// The function below reads the options from a config file.
+// Two formats are supported: a JSON object, or a TOML-like file with one "key = value" per line.
+// The TOML-like file can have comments (#), quoted strings and a [server] section.
+// Errors reading the file are reported when the options are applied.
+func FromFile(path string) OptionSet {
+	f, err := os.Open(path)
+	if err != nil {
+		return OptionSet{invalid("file", path, "file", err)}
+	}
+	defer f.Close()
+	return FromConfig(path, f)
+}
+
+// Config Reader
+// The function below reads the options from a reader, the name is used as the source of the options.
+// JSON objects are detected by their first character.
+func FromConfig(name string, r io.Reader) OptionSet {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return nil // Empty config
+		}
+		if b == ' ' || b == '\t' || b == '\r' || b == '\n' {
+			continue
+		}
+		_ = br.UnreadByte()
+		if b == '{' {
+			return fromJSON(name, br)
+		}
+		return fromTOML(name, br)
+	}
+}
+
+// JSON Config
+// The JSON values are converted to text, and parsed in the same way as environment variables.
+// JSON objects are unordered, so the order of the file is not kept: the known keys are applied in the order of
+// serverKeys, and then the unknown keys (reported as invalid) in sorted order.
+func fromJSON(name string, r io.Reader) OptionSet {
+	var values map[string]any
+	if err := json.NewDecoder(r).Decode(&values); err != nil {
+		return OptionSet{invalid("file", name, name, err)}
+	}
+	var opts OptionSet
+	for _, k := range serverKeys {
+		if v, ok := values[k.name]; ok {
+			opts = append(opts, k.parse(fmt.Sprint(v), name))
+			delete(values, k.name)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		opts = append(opts, parseKey(key, fmt.Sprint(values[key]), name))
+	}
+	return opts
+}
+
+// TOML-like Config
+// Each line is parsed separately, and the source of each option has the line number (e.g., server.toml:3).
+func fromTOML(name string, r io.Reader) OptionSet {
+	var opts OptionSet
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		source := fmt.Sprintf("%s:%d", name, n)
+		if line == "" || strings.HasPrefix(line, "#") || line == "[server]" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			opts = append(opts, invalid("line", line, source, errors.New(`expected "key = value"`)))
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		opts = append(opts, parseKey(key, value, source))
 	}
+	if err := scanner.Err(); err != nil {
+		opts = append(opts, invalid("file", name, name, err))
+	}
+	return opts
 }
 
 // Constructor
 // Note that the constructor below is more dynamic, since it can accept any number of options.
 // This allows us to create a Server instance with various configurations without needing to define multiple constructors.
-func NewServer(opts ...Option) *Server {
+// The options are applied in order, over the default values, so the later options override the earlier ones.
+// This allows layering the configuration: defaults, then config file, then environment, then code.
+// All the invalid options are reported at once, instead of only the first one.
+func NewServer(opts ...Option) (*Server, error) {
 	s := &Server{
-		Host: "localhost",
-		Port: 8080,
-		TLS:  false,
+		Host:        "localhost",
+		Port:        8080,
+		TLS:         false,
+		ReadTimeout: 5 * time.Second,
+	}
+	if err := OptionSet(opts).Apply(s); err != nil {
+		return nil, err
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// HTTP Server
+// The methods below start a net/http server with the configuration.
+// Serve accepts connections from the listener, until the context is canceled.
+// Then, it shuts down the server gracefully, waiting for the active requests (up to ShutdownTimeout).
+// The shutdown is registered with context.AfterFunc, so nothing is left waiting for the context when the server
+// fails before it is canceled.
+const ShutdownTimeout = 10 * time.Second
+
+func (s *Server) Addr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+func (s *Server) Listen() (net.Listener, error) {
+	return net.Listen("tcp", s.Addr())
+}
+func (s *Server) Serve(ctx context.Context, l net.Listener, handler http.Handler) error {
+	hs := &http.Server{Handler: handler, ReadTimeout: s.ReadTimeout}
+	shutdown := make(chan error, 1)
+	stop := context.AfterFunc(ctx, func() {
+		sctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+		defer cancel()
+		shutdown <- hs.Shutdown(sctx)
+	})
+	var err error
+	if s.TLS {
+		err = hs.ServeTLS(l, s.CertFile, s.KeyFile)
+	} else {
+		err = hs.Serve(l)
 	}
-	for _, opt := range opts {
-		opt(s)
+	if !errors.Is(err, http.ErrServerClosed) {
+		stop()
+		return err
 	}
-	return s
+	return <-shutdown
+}
+func (s *Server) ListenAndServe(ctx context.Context, handler http.Handler) error {
+	l, err := s.Listen()
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, l, handler)
 }
 
 // Test Functional Options
 // In this test, we will create two Server instances with different configurations using the functional options pattern.
 func TestFunctionalOpts() {
-	s1 := NewServer(
-		WithHost("https://test.com"),
+	s1, _ := NewServer(
+		WithHost("test.com"),
 		WithPort(443),
 		WithTLS(true),
+		WithCertificate("cert.pem", "key.pem"),
 	)
-	s2 := NewServer(
-		WithTLS(true),
+	s2, _ := NewServer(
+		WithPort(9090),
 	)
-	fmt.Println(s1) // Output: &{https://test.com 443 true}
-	fmt.Println(s2) // Output: &{localhost 8080 true}
+	fmt.Println(s1) // Output: &{test.com 443 true cert.pem key.pem 5s}
+	fmt.Println(s2) // Output: &{localhost 9090 false   5s}
+
+	// Invalid Options
+	// All the invalid options are reported, with their values.
+	_, err := NewServer(WithHost("https://test.com"), WithPort(-1))
+	fmt.Println(err)
+	// Output:
+	// host=https://test.com (code): must be a host name, not a URL
+	// port=-1 (code): must be between 0 and 65535
+
+	// Layers
+	// The options below are read from a config file, then from the environment, then from the code.
+	// Printing the options shows where each value came from.
+	// The variable is restored at the end, so the demo does not change the environment of the process.
+	if old, ok := os.LookupEnv("DEMO_READ_TIMEOUT"); ok {
+		defer os.Setenv("DEMO_READ_TIMEOUT", old)
+	} else {
+		defer os.Unsetenv("DEMO_READ_TIMEOUT")
+	}
+	os.Setenv("DEMO_READ_TIMEOUT", "10s")
+	opts := OptionSet{
+		FromConfig("server.toml", strings.NewReader("[server]\nhost = \"example.com\"\nport = 8443\n")),
+		FromEnv("DEMO"),
+		WithPort(9443),
+	}
+	s3, _ := NewServer(opts)
+	fmt.Println(opts)
+	fmt.Println(s3)
+	// Output:
+	// host=example.com (server.toml:2)
+	// port=8443 (server.toml:3)
+	// read_timeout=10s (env DEMO_READ_TIMEOUT)
+	// port=9443 (code)
+	// &{example.com 9443 false   10s}
+
+	// Generated Builder
+	// The same configuration can be created with the generated builder, which also validates the server.
+	s4, _ := NewServerBuilder().WithHost("test.com").WithPort(443).Build()
+	_, err = NewServerBuilder().WithPort(-1).WithTLS(true).Build()
+	fmt.Println(s4) // Output: &{test.com 443 false   5s}
+	fmt.Println(err)
+	// Output:
+	// invalid port: -1
+	// tls requires a certificate and a key file
+}
+
+// Test HTTP Server
+// The function below starts an HTTP server on a free port (port 0), sends a request, and stops the server.
+func TestHTTPServer() {
+	s, err := NewServer(WithHost("127.0.0.1"), WithPort(0), WithReadTimeout(time.Second))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	l, err := s.Listen()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- s.Serve(ctx, l, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "hello from %s", r.URL.Path)
+		}))
+	}()
+
+	resp, err := http.Get("http://" + l.Addr().String() + "/options")
+	if err == nil {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		fmt.Println(string(body)) // Output: hello from /options
+	}
+	cancel()
+	fmt.Println(<-done) // Output: <nil>
 }
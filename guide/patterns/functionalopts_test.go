@@ -0,0 +1,209 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewServerDefaults(t *testing.T) {
+	s, err := NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Server{Host: "localhost", Port: 8080, ReadTimeout: 5 * time.Second}
+	if *s != want {
+		t.Errorf("NewServer() = %+v; expected %+v", *s, want)
+	}
+	// The defaults of the generated builder must match the defaults of the constructor.
+	b, err := NewServerBuilder().Build()
+	if err != nil || *b != want {
+		t.Errorf("NewServerBuilder().Build() = %+v, %v; expected %+v", b, err, want)
+	}
+}
+
+func TestNewServerReportsAllErrors(t *testing.T) {
+	_, err := NewServer(
+		WithHost(""),
+		WithPort(70000),
+		WithReadTimeout(-time.Second),
+		OptionFunc(func(*Server) error { return errors.New("custom") }),
+	)
+	want := []string{
+		"host= (code): cannot be empty",
+		"port=70000 (code): must be between 0 and 65535",
+		"read_timeout=-1s (code): cannot be negative",
+		"custom",
+	}
+	if err == nil || err.Error() != strings.Join(want, "\n") {
+		t.Errorf("NewServer error = %v; expected %q", err, want)
+	}
+
+	// Cross-field rules are checked only when all the options are valid.
+	_, err = NewServer(WithTLS(true))
+	if err == nil || err.Error() != "tls requires a certificate and a key file" {
+		t.Errorf("NewServer(WithTLS(true)) = %v", err)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("APP_HOST", "example.com")
+	t.Setenv("APP_PORT", "not-a-port")
+	t.Setenv("APP_TLS", "yes")
+	opts := FromEnv("app")
+	want := "host=example.com (env APP_HOST)\nport=not-a-port (env APP_PORT)\ntls=yes (env APP_TLS)"
+	if opts.String() != want {
+		t.Errorf("FromEnv = %q; expected %q", opts, want)
+	}
+	_, err := NewServer(opts)
+	want = "port=not-a-port (env APP_PORT): must be a number\ntls=yes (env APP_TLS): must be a boolean"
+	if err == nil || err.Error() != want {
+		t.Errorf("NewServer error = %v; expected %q", err, want)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	toml := `
+# Server configuration
+[server]
+host = "example.com"
+port = 8443
+tls = true
+cert_file = cert.pem
+key_file = "key.pem"
+read_timeout = "2s"
+`
+	json := `{"read_timeout": "2s", "host": "example.com", "port": 8443, "tls": true, "cert_file": "cert.pem", "key_file": "key.pem"}`
+	want := Server{Host: "example.com", Port: 8443, TLS: true, CertFile: "cert.pem", KeyFile: "key.pem", ReadTimeout: 2 * time.Second}
+	for name, src := range map[string]string{"server.toml": toml, "server.json": json} {
+		s, err := NewServer(FromConfig(name, strings.NewReader(src)))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if *s != want {
+			t.Errorf("%s: NewServer = %+v; expected %+v", name, *s, want)
+		}
+	}
+
+	_, err := NewServer(FromConfig("bad.toml", strings.NewReader("port = 80\nhots = x\nnonsense\n")))
+	want2 := "hots=x (bad.toml:2): unknown option\n" + `line=nonsense (bad.toml:3): expected "key = value"`
+	if err == nil || err.Error() != want2 {
+		t.Errorf("NewServer error = %v; expected %q", err, want2)
+	}
+	if _, err := NewServer(FromConfig("bad.json", strings.NewReader("{"))); err == nil {
+		t.Errorf("expected an error for invalid JSON")
+	}
+}
+
+func TestLayering(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.toml")
+	if err := os.WriteFile(path, []byte("host = file.com\nport = 1000\nread_timeout = 1s\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LAYER_PORT", "2000")
+	t.Setenv("LAYER_READ_TIMEOUT", "3s")
+	s, err := NewServer(FromFile(path), FromEnv("LAYER"), WithReadTimeout(4*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Server{Host: "file.com", Port: 2000, ReadTimeout: 4 * time.Second}
+	if *s != want {
+		t.Errorf("NewServer = %+v; expected %+v", *s, want)
+	}
+	if _, err := NewServer(FromFile(filepath.Join(t.TempDir(), "missing.toml"))); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("FromFile(missing) = %v; expected %v", err, os.ErrNotExist)
+	}
+}
+
+func TestServe(t *testing.T) {
+	s, err := NewServer(WithHost("127.0.0.1"), WithPort(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := s.Listen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- s.Serve(ctx, l, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "ok")
+		}))
+	}()
+	resp, err := http.Get("http://" + l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "ok" {
+		t.Errorf("body = %q; expected %q", body, "ok")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Serve = %v; expected nil after shutdown", err)
+	}
+}
+
+// The active requests are finished on shutdown, even when the read timeout is 0 (no timeout).
+func TestServeGracefulShutdown(t *testing.T) {
+	s, err := NewServer(WithHost("127.0.0.1"), WithPort(0), WithReadTimeout(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := s.Listen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- s.Serve(ctx, l, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			io.WriteString(w, "ok")
+		}))
+	}()
+	resp := make(chan string)
+	go func() {
+		r, err := http.Get("http://" + l.Addr().String())
+		if err != nil {
+			resp <- err.Error()
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		r.Body.Close()
+		resp <- string(body)
+	}()
+	<-started
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	if body := <-resp; body != "ok" {
+		t.Errorf("body = %q; expected %q", body, "ok")
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Serve = %v; expected nil after shutdown", err)
+	}
+}
+
+func TestServeError(t *testing.T) {
+	s, _ := NewServer(WithHost("127.0.0.1"), WithPort(0))
+	l, err := s.Listen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+	if err := s.Serve(context.Background(), l, http.NotFoundHandler()); err == nil {
+		t.Errorf("Serve on a closed listener must fail")
+	}
+}
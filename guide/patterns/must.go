@@ -6,7 +6,14 @@
 
 package patterns
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+)
 
 // Struct
 // The struct below will be used to demonstrate the Must pattern.
@@ -38,9 +45,221 @@ func MustNewConfig(name, value string) *Config {
 	return config
 }
 
+// Generic Must
+// Writing a Must function for each constructor is repetitive.
+// With generics, a single Must function works for any function that returns a value and an error.
+// The panic value is a MustError, which keeps the original error and the position of the caller,
+// so the panic message tells where the failing call is (e.g., "must: main.go:12: name cannot be empty").
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(newMustError(err, 2))
+	}
+	return v
+}
+
+// Must0
+// The function below is the Must function for functions that return only an error.
+func Must0(err error) {
+	if err != nil {
+		panic(newMustError(err, 2))
+	}
+}
+
+// Must Error
+// The error below is the panic value of the Must functions.
+// Since it wraps the original error, the recovered value can be checked with "errors.Is" and "errors.As".
+type MustError struct {
+	Err  error
+	File string
+	Line int
+}
+
+func (e *MustError) Error() string {
+	return "must: " + e.File + ":" + strconv.Itoa(e.Line) + ": " + e.Err.Error()
+}
+func (e *MustError) Unwrap() error {
+	return e.Err
+}
+
+// Must Error Constructor
+// The skip argument is the number of stack frames to skip, to find the caller of the Must function.
+func newMustError(err error, skip int) *MustError {
+	e := &MustError{Err: err, File: "???"}
+	if _, file, line, ok := runtime.Caller(skip); ok {
+		e.File, e.Line = filepath.Base(file), line
+	}
+	return e
+}
+
+// Lazy
+// Package-level variables are initialized when the program starts, even if they are never used.
+// Expensive values (e.g., connections, parsed templates) can be initialized lazily, on the first use, instead.
+// The Lazy type below calls the initializer only once, even when used by many goroutines at the same time.
+// Unlike sync.OnceValues, a panic in the initializer is captured as an error, so it does not crash the caller.
+// The result (value or error) is kept, and returned by all the following calls.
+type Lazy[T any] struct {
+	once  sync.Once
+	init  func() (T, error)
+	value T
+	err   error
+}
+
+// Lazy Constructor
+// The constructor receives the initializer, which is not called until the value is needed.
+func NewLazy[T any](init func() (T, error)) *Lazy[T] {
+	return &Lazy[T]{init: init}
+}
+
+// Get
+// The method below returns the value, calling the initializer on the first call.
+func (l *Lazy[T]) Get() (T, error) {
+	l.once.Do(func() {
+		// When the panic value is an error, it is wrapped, so it can be checked with errors.Is and errors.As.
+		defer func() {
+			switch r := recover().(type) {
+			case nil:
+			case error:
+				l.err = fmt.Errorf("lazy initialization panicked: %w", r)
+			default:
+				l.err = fmt.Errorf("lazy initialization panicked: %v", r)
+			}
+		}()
+		l.value, l.err = l.init()
+	})
+	return l.value, l.err
+}
+
+// Must Get
+// The method below returns the value, and panics if the initialization failed.
+func (l *Lazy[T]) MustGet() T {
+	v, err := l.Get()
+	if err != nil {
+		panic(newMustError(err, 2))
+	}
+	return v
+}
+
+// Config Registry
+// The Must functions panic on the first invalid value, so we only see one error at a time.
+// The registry below collects the configs, and validates all of them together, reporting all the errors at once.
+// Each entry keeps the position where it was added, so the errors tell which line must be fixed.
+type ConfigRegistry struct {
+	rules   []ConfigRule
+	entries []configEntry
+}
+type configEntry struct {
+	config *Config
+	file   string
+	line   int
+}
+
+// Config Rule
+// A rule validates a config, besides the validation done by the NewConfig constructor.
+type ConfigRule func(*Config) error
+
+// Registry Constructor
+// The constructor receives the rules that all the configs must follow.
+func NewConfigRegistry(rules ...ConfigRule) *ConfigRegistry {
+	return &ConfigRegistry{rules: rules}
+}
+
+// Add
+// The method below adds a config to the registry, and returns it.
+// The config is not validated until the Validate method is called.
+func (r *ConfigRegistry) Add(name, value string) *Config {
+	e := configEntry{config: &Config{Name: name, Value: value}, file: "???"}
+	if _, file, line, ok := runtime.Caller(1); ok {
+		e.file, e.line = filepath.Base(file), line
+	}
+	r.entries = append(r.entries, e)
+	return e.config
+}
+
+// Validate
+// The method below validates all the configs, and returns all the errors joined.
+// Each error has the position where the invalid config was added.
+func (r *ConfigRegistry) Validate() error {
+	var errs []error
+	seen := map[string]string{}
+	for _, e := range r.entries {
+		pos := e.file + ":" + strconv.Itoa(e.line)
+		fail := func(err error) {
+			errs = append(errs, fmt.Errorf("%s: config %q: %w", pos, e.config.Name, err))
+		}
+		if _, err := NewConfig(e.config.Name, e.config.Value); err != nil {
+			fail(err)
+			continue
+		}
+		if prev, ok := seen[e.config.Name]; ok {
+			fail(fmt.Errorf("duplicate name, first added at %s", prev))
+		}
+		seen[e.config.Name] = pos
+		for _, rule := range r.rules {
+			if err := rule(e.config); err != nil {
+				fail(err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Rules
+// The rules below are used by the preset configurations.
+func RequireValue(c *Config) error {
+	if c.Value == "" {
+		return errors.New("value cannot be empty")
+	}
+	return nil
+}
+func ValidPort(c *Config) error {
+	if c.Name != "port" {
+		return nil
+	}
+	if port, err := strconv.Atoi(c.Value); err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port %q", c.Value)
+	}
+	return nil
+}
+
 // Preset Configurations
-// Since we're sure these values are valid, we use the Must function directly.
+// The configs are added to the registry, which validates all of them when the package is initialized.
+// If any config is invalid, the program panics before main, with all the errors and their positions.
+var configs = NewConfigRegistry(RequireValue, ValidPort)
+
 var Configs = []*Config{
-	MustNewConfig("host", "localhost"),
-	MustNewConfig("port", "8080"),
+	configs.Add("host", "localhost"),
+	configs.Add("port", "8080"),
+}
+
+func init() {
+	Must0(configs.Validate())
+}
+
+// Test Must
+// The function below demonstrates the generic Must functions, and how to recover from their panics.
+func TestMust() {
+	c := Must(NewConfig("timeout", "5s"))
+	fmt.Println(c.Name, c.Value) // Output: timeout 5s
+
+	defer func() {
+		err, _ := recover().(error)
+		var mustErr *MustError
+		fmt.Println(errors.As(err, &mustErr)) // Output: true
+		fmt.Println(mustErr.Err)              // Output: name cannot be empty
+	}()
+	Must(NewConfig("", "5s"))
+}
+
+// Test Lazy
+// The initializer below is called only on the first call to Get.
+func TestLazy() {
+	calls := 0
+	greeting := NewLazy(func() (string, error) {
+		calls++
+		return "hello", nil
+	})
+	fmt.Println(calls)              // Output: 0
+	fmt.Println(greeting.MustGet()) // Output: hello
+	fmt.Println(greeting.MustGet()) // Output: hello
+	fmt.Println(calls)              // Output: 1
 }
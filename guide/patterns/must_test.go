@@ -0,0 +1,160 @@
+package patterns
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Catch
+// The helper below calls the function, and returns the recovered panic value.
+func catch(fn func()) (r any) {
+	defer func() { r = recover() }()
+	fn()
+	return nil
+}
+
+// Line
+// The helper below returns the line of its caller, so the tests do not depend on line numbers.
+func line() int {
+	_, _, l, _ := runtime.Caller(1)
+	return l
+}
+
+func TestMustPanicMessage(t *testing.T) {
+	errBoom := errors.New("boom")
+	var want int
+	r := catch(func() {
+		want = line() + 1
+		Must(0, errBoom)
+	})
+	err, ok := r.(error)
+	if !ok {
+		t.Fatalf("panic value = %#v; expected an error", r)
+	}
+	if msg := fmt.Sprintf("must: must_test.go:%d: boom", want); err.Error() != msg {
+		t.Errorf("panic message = %q; expected %q", err, msg)
+	}
+	if !errors.Is(err, errBoom) {
+		t.Errorf("panic value must wrap the original error")
+	}
+
+	r = catch(func() {
+		want = line() + 1
+		Must0(errBoom)
+	})
+	var mustErr *MustError
+	if !errors.As(r.(error), &mustErr) || mustErr.File != "must_test.go" || mustErr.Line != want {
+		t.Errorf("Must0 panic = %v; expected must_test.go:%d", r, want)
+	}
+}
+
+func TestMustNoPanic(t *testing.T) {
+	if r := catch(func() {
+		if v := Must(NewConfig("a", "b")); v.Name != "a" {
+			t.Errorf("Must returned %v", v)
+		}
+		Must0(nil)
+	}); r != nil {
+		t.Errorf("unexpected panic: %v", r)
+	}
+}
+
+func TestLazyOnce(t *testing.T) {
+	var calls int
+	var mu sync.Mutex
+	l := NewLazy(func() ([]int, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return []int{1, 2, 3}, nil
+	})
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v := l.MustGet(); len(v) != 3 {
+				t.Errorf("MustGet = %v", v)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("initializer called %d times; expected 1", calls)
+	}
+}
+
+func TestLazyErrors(t *testing.T) {
+	errInit := errors.New("no connection")
+	calls := 0
+	l := NewLazy(func() (int, error) {
+		calls++
+		return 0, errInit
+	})
+	for range 2 {
+		if _, err := l.Get(); !errors.Is(err, errInit) {
+			t.Errorf("Get = %v; expected %v", err, errInit)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("failed initializers must not be retried: calls = %d", calls)
+	}
+	var want int
+	r := catch(func() {
+		want = line() + 1
+		l.MustGet()
+	})
+	if msg := fmt.Sprintf("must: must_test.go:%d: no connection", want); fmt.Sprint(r) != msg {
+		t.Errorf("MustGet panic = %v; expected %q", r, msg)
+	}
+
+	// Panics in the initializer are captured as errors.
+	p := NewLazy(func() (int, error) { panic("kaboom") })
+	if _, err := p.Get(); err == nil || err.Error() != "lazy initialization panicked: kaboom" {
+		t.Errorf("Get = %v; expected the captured panic", err)
+	}
+	// Panics with an error value are wrapped.
+	var m map[string]int
+	e := NewLazy(func() (int, error) { m["a"] = 1; return 0, nil })
+	var re runtime.Error
+	if _, err := e.Get(); !errors.As(err, &re) {
+		t.Errorf("Get = %v; expected a wrapped runtime.Error", err)
+	}
+}
+
+func TestConfigRegistry(t *testing.T) {
+	r := NewConfigRegistry(RequireValue, ValidPort)
+	first := line() + 1
+	r.Add("host", "localhost")
+	r.Add("", "value")
+	r.Add("port", "http")
+	r.Add("host", "")
+	err := r.Validate()
+	want := []string{
+		fmt.Sprintf(`must_test.go:%d: config "": name cannot be empty`, first+1),
+		fmt.Sprintf(`must_test.go:%d: config "port": invalid port "http"`, first+2),
+		fmt.Sprintf(`must_test.go:%d: config "host": duplicate name, first added at must_test.go:%d`, first+3, first),
+		fmt.Sprintf(`must_test.go:%d: config "host": value cannot be empty`, first+3),
+	}
+	if err == nil || err.Error() != strings.Join(want, "\n") {
+		t.Errorf("Validate =\n%v\nexpected\n%s", err, strings.Join(want, "\n"))
+	}
+
+	// Validating at init time panics with all the errors.
+	if r := catch(func() { Must0(r.Validate()) }); r == nil || strings.Count(fmt.Sprint(r), "\n") != 3 {
+		t.Errorf("Must0(Validate) panic = %v; expected all the errors", r)
+	}
+}
+
+func TestPresetConfigs(t *testing.T) {
+	if err := configs.Validate(); err != nil {
+		t.Errorf("preset configs are invalid: %v", err)
+	}
+	if len(Configs) != 2 || Configs[1].Name != "port" {
+		t.Errorf("Configs = %v", Configs)
+	}
+}
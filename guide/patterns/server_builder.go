@@ -0,0 +1,73 @@
+// Code generated by "buildergen -type=Server"; DO NOT EDIT.
+
+package patterns
+
+import (
+	"errors"
+	"time"
+)
+
+// ServerBuilder builds Server values.
+// Each With method sets a field and returns the builder, so the calls can be chained.
+type ServerBuilder struct {
+	value Server
+}
+
+// NewServerBuilder returns a builder with the default values.
+func NewServerBuilder() *ServerBuilder {
+	b := &ServerBuilder{}
+	b.value.Host = "localhost"
+	b.value.Port = 8080
+	b.value.ReadTimeout = 5 * time.Second
+	return b
+}
+
+// WithHost sets the Host field.
+func (b *ServerBuilder) WithHost(host string) *ServerBuilder {
+	b.value.Host = host
+	return b
+}
+
+// WithPort sets the Port field.
+func (b *ServerBuilder) WithPort(port int) *ServerBuilder {
+	b.value.Port = port
+	return b
+}
+
+// WithTLS sets the TLS field.
+func (b *ServerBuilder) WithTLS(tls bool) *ServerBuilder {
+	b.value.TLS = tls
+	return b
+}
+
+// WithCertFile sets the CertFile field.
+func (b *ServerBuilder) WithCertFile(certFile string) *ServerBuilder {
+	b.value.CertFile = certFile
+	return b
+}
+
+// WithKeyFile sets the KeyFile field.
+func (b *ServerBuilder) WithKeyFile(keyFile string) *ServerBuilder {
+	b.value.KeyFile = keyFile
+	return b
+}
+
+// WithReadTimeout sets the ReadTimeout field.
+func (b *ServerBuilder) WithReadTimeout(readTimeout time.Duration) *ServerBuilder {
+	b.value.ReadTimeout = readTimeout
+	return b
+}
+
+// Build returns a copy of the built Server.
+// It reports all the missing required fields and validation errors together.
+func (b *ServerBuilder) Build() (*Server, error) {
+	var errs []error
+	v := b.value
+	if err := v.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
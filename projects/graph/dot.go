@@ -0,0 +1,57 @@
+// DOT Export
+// DOT is the text format of Graphviz, a tool that draws graphs.
+// The output can be rendered with: dot -Tsvg graph.dot -o graph.svg
+// Example:
+//   digraph deps {
+//     "app" -> "lib" [label="2"];
+//   }
+
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Writing DOT
+// The method below writes the graph in DOT format. The nodes and the edges are written in insertion order,
+// the names are quoted, and the weights different from 1 are written as labels.
+// In undirected graphs, each edge is written once.
+func (g *Graph[N]) WriteDOT(w io.Writer, name string) error {
+	kind, arrow := "graph", "--"
+	if g.directed {
+		kind, arrow = "digraph", "->"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s {\n", kind, strconv.Quote(name))
+	for _, n := range g.nodes {
+		fmt.Fprintf(&sb, "  %s;\n", quote(n))
+	}
+	for _, n := range g.nodes {
+		for _, e := range g.edges[n] {
+			if !g.directed && g.index[e.To] < g.index[n] {
+				continue
+			}
+			fmt.Fprintf(&sb, "  %s %s %s", quote(e.From), arrow, quote(e.To))
+			if e.Weight != 1 {
+				fmt.Fprintf(&sb, " [label=%s]", strconv.Quote(strconv.FormatFloat(e.Weight, 'g', -1, 64)))
+			}
+			sb.WriteString(";\n")
+		}
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (g *Graph[N]) DOT(name string) string {
+	var sb strings.Builder
+	g.WriteDOT(&sb, name)
+	return sb.String()
+}
+
+func quote(v any) string {
+	return strconv.Quote(fmt.Sprint(v))
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"graph"
+)
+
+func main() {
+	g := graph.NewDirected[string]()
+	g.AddEdge("main", "http", 1)
+	g.AddEdge("main", "config", 1)
+	g.AddEdge("http", "log", 1)
+	g.AddEdge("config", "log", 1)
+
+	order, err := g.TopologicalSort()
+	fmt.Println("Build order:", order, err)
+
+	path, dist, err := g.Dijkstra("main", "log")
+	fmt.Println("Path:", path, dist, err)
+
+	g.WriteDOT(os.Stdout, "deps")
+}
@@ -0,0 +1,148 @@
+// Graph
+// A graph is a set of nodes connected by edges. It models networks, dependencies, maps, state machines, etc.
+// The edges of a directed graph have a direction (from -> to), like the dependencies of a build, and the edges of an
+// undirected graph go both ways, like roads. Weighted edges have a cost (e.g., a distance or a time).
+// The graph below stores the edges of each node in a map of adjacency lists, which is compact for sparse graphs,
+// and lists the neighbors of a node in O(degree).
+// The nodes can be of any comparable type (e.g., strings, ints, or structs), thanks to generics.
+// The nodes and the edges are kept in insertion order, so the traversals and the outputs are deterministic.
+
+package graph
+
+import (
+	"errors"
+	"iter"
+	"slices"
+)
+
+// Errors
+// The errors below are returned by the algorithms when the graph does not meet their requirements.
+var (
+	ErrNodeNotFound   = errors.New("graph: node not found")
+	ErrNoPath         = errors.New("graph: no path")
+	ErrNegativeWeight = errors.New("graph: negative edge weight")
+	ErrCycle          = errors.New("graph: cycle")
+	ErrUndirected     = errors.New("graph: undirected graph")
+)
+
+// Declaring the Graph Type
+// The index of each node is its position in the insertion order.
+type Graph[N comparable] struct {
+	directed bool
+	nodes    []N
+	index    map[N]int
+	edges    map[N][]Edge[N]
+}
+
+// Edge
+// The edge goes from the node "From" to the node "To". In undirected graphs, each edge is stored in both nodes,
+// with the nodes swapped.
+type Edge[N comparable] struct {
+	From, To N
+	Weight   float64
+}
+
+// Creating Graphs
+// The functions below create empty directed and undirected graphs.
+func NewDirected[N comparable]() *Graph[N] {
+	return &Graph[N]{directed: true, index: map[N]int{}, edges: map[N][]Edge[N]{}}
+}
+func NewUndirected[N comparable]() *Graph[N] {
+	return &Graph[N]{index: map[N]int{}, edges: map[N][]Edge[N]{}}
+}
+
+func (g *Graph[N]) Directed() bool {
+	return g.directed
+}
+
+// Adding Nodes and Edges
+// AddEdge adds the missing nodes, and AddNode adds isolated nodes (without edges).
+// Adding an existing node has no effect, and adding an existing edge updates its weight.
+func (g *Graph[N]) AddNode(n N) {
+	if _, ok := g.index[n]; !ok {
+		g.index[n] = len(g.nodes)
+		g.nodes = append(g.nodes, n)
+	}
+}
+func (g *Graph[N]) AddEdge(from, to N, weight float64) {
+	g.AddNode(from)
+	g.AddNode(to)
+	g.setEdge(from, to, weight)
+	if !g.directed && from != to {
+		g.setEdge(to, from, weight)
+	}
+}
+func (g *Graph[N]) setEdge(from, to N, weight float64) {
+	i := slices.IndexFunc(g.edges[from], func(e Edge[N]) bool { return e.To == to })
+	if i >= 0 {
+		g.edges[from][i].Weight = weight
+		return
+	}
+	g.edges[from] = append(g.edges[from], Edge[N]{From: from, To: to, Weight: weight})
+}
+
+// Removing Edges
+// The method below removes the edge (in both directions, for undirected graphs), and reports whether it existed.
+func (g *Graph[N]) RemoveEdge(from, to N) bool {
+	removed := g.deleteEdge(from, to)
+	if !g.directed && from != to {
+		g.deleteEdge(to, from)
+	}
+	return removed
+}
+func (g *Graph[N]) deleteEdge(from, to N) bool {
+	n := len(g.edges[from])
+	g.edges[from] = slices.DeleteFunc(g.edges[from], func(e Edge[N]) bool { return e.To == to })
+	return len(g.edges[from]) < n
+}
+
+// Querying the Graph
+// The methods below return the nodes and the edges, in insertion order.
+func (g *Graph[N]) Len() int {
+	return len(g.nodes)
+}
+func (g *Graph[N]) HasNode(n N) bool {
+	_, ok := g.index[n]
+	return ok
+}
+func (g *Graph[N]) Nodes() []N {
+	return slices.Clone(g.nodes)
+}
+func (g *Graph[N]) Edge(from, to N) (Edge[N], bool) {
+	i := slices.IndexFunc(g.edges[from], func(e Edge[N]) bool { return e.To == to })
+	if i < 0 {
+		return Edge[N]{}, false
+	}
+	return g.edges[from][i], true
+}
+func (g *Graph[N]) Edges(n N) []Edge[N] {
+	return slices.Clone(g.edges[n])
+}
+
+// Iterating Neighbors
+// The iterator below yields the nodes reached by the edges of the node.
+func (g *Graph[N]) Neighbors(n N) iter.Seq[N] {
+	return func(yield func(N) bool) {
+		for _, e := range g.edges[n] {
+			if !yield(e.To) {
+				return
+			}
+		}
+	}
+}
+
+// Reversing a Graph
+// The reverse of a directed graph has all the edges in the opposite direction.
+// The reverse of an undirected graph is a copy of it.
+func (g *Graph[N]) Reverse() *Graph[N] {
+	r := &Graph[N]{directed: g.directed, nodes: slices.Clone(g.nodes), index: map[N]int{}, edges: map[N][]Edge[N]{}}
+	for i, n := range g.nodes {
+		r.index[n] = i
+	}
+	for _, n := range g.nodes {
+		for _, e := range g.edges[n] {
+			r.edges[e.To] = append(r.edges[e.To], Edge[N]{From: e.To, To: e.From, Weight: e.Weight})
+		}
+	}
+	return r
+}
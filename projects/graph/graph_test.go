@@ -0,0 +1,307 @@
+package graph
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAddRemoveEdges(t *testing.T) {
+	g := NewUndirected[string]()
+	g.AddEdge("a", "b", 2)
+	g.AddEdge("b", "c", 1)
+	g.AddNode("d")
+	g.AddNode("a")
+	g.AddEdge("b", "a", 5)
+	if got := g.Nodes(); !slices.Equal(got, []string{"a", "b", "c", "d"}) || g.Len() != 4 {
+		t.Errorf("Nodes = %v", got)
+	}
+	if e, ok := g.Edge("a", "b"); !ok || e.Weight != 5 {
+		t.Errorf("Edge(a, b) = %v, %v; the weight must be updated in both directions", e, ok)
+	}
+	if got := slices.Collect(g.Neighbors("b")); !slices.Equal(got, []string{"a", "c"}) {
+		t.Errorf("Neighbors(b) = %v", got)
+	}
+	if !g.RemoveEdge("c", "b") || g.RemoveEdge("c", "b") {
+		t.Errorf("RemoveEdge must report whether the edge existed")
+	}
+	if _, ok := g.Edge("b", "c"); ok {
+		t.Errorf("undirected edges must be removed in both directions")
+	}
+	if g.HasNode("z") || !g.HasNode("d") || len(g.Edges("d")) != 0 {
+		t.Errorf("HasNode or Edges is wrong")
+	}
+
+	d := NewDirected[int]()
+	d.AddEdge(1, 2, 1)
+	if _, ok := d.Edge(2, 1); ok || !d.Directed() {
+		t.Errorf("directed edges must have a single direction")
+	}
+	r := d.Reverse()
+	if _, ok := r.Edge(2, 1); !ok || !slices.Equal(r.Nodes(), []int{1, 2}) {
+		t.Errorf("Reverse must swap the edges")
+	}
+}
+
+// grid returns an undirected graph of a w x h grid, where each node is connected to its right and bottom nodes.
+func grid(w, h int) *Graph[[2]int] {
+	g := NewUndirected[[2]int]()
+	for y := range h {
+		for x := range w {
+			g.AddNode([2]int{x, y})
+			if x+1 < w {
+				g.AddEdge([2]int{x, y}, [2]int{x + 1, y}, 1)
+			}
+			if y+1 < h {
+				g.AddEdge([2]int{x, y}, [2]int{x, y + 1}, 1)
+			}
+		}
+	}
+	return g
+}
+
+func TestTraversals(t *testing.T) {
+	g := NewDirected[string]()
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("a", "c", 1)
+	g.AddEdge("b", "d", 1)
+	g.AddEdge("c", "d", 1)
+	g.AddEdge("d", "a", 1)
+	g.AddEdge("e", "a", 1)
+	if got := slices.Collect(g.BFS("a")); !slices.Equal(got, []string{"a", "b", "c", "d"}) {
+		t.Errorf("BFS = %v", got)
+	}
+	if got := slices.Collect(g.DFS("a")); !slices.Equal(got, []string{"a", "b", "d", "c"}) {
+		t.Errorf("DFS = %v", got)
+	}
+	if got := slices.Collect(g.BFS("z")); len(got) != 0 {
+		t.Errorf("BFS from a missing node = %v", got)
+	}
+	for n := range g.DFS("a") {
+		if n == "b" {
+			break
+		}
+	}
+
+	// BFS visits the nodes by distance, so the Manhattan distance never decreases on a grid.
+	last := 0
+	count := 0
+	for n := range grid(8, 8).BFS([2]int{0, 0}) {
+		if d := n[0] + n[1]; d < last {
+			t.Fatalf("BFS visited %v after distance %d", n, last)
+		} else {
+			last = d
+		}
+		count++
+	}
+	if count != 64 {
+		t.Errorf("BFS visited %d nodes; expected 64", count)
+	}
+}
+
+func TestShortestPaths(t *testing.T) {
+	g := NewDirected[string]()
+	g.AddEdge("a", "b", 4)
+	g.AddEdge("a", "c", 1)
+	g.AddEdge("c", "b", 2)
+	g.AddEdge("b", "d", 1)
+	g.AddEdge("c", "d", 5)
+	g.AddNode("island")
+	path, dist, err := g.Dijkstra("a", "d")
+	if err != nil || dist != 4 || !slices.Equal(path, []string{"a", "c", "b", "d"}) {
+		t.Errorf("Dijkstra = %v, %v, %v", path, dist, err)
+	}
+	if path, dist, err := g.Dijkstra("a", "a"); err != nil || dist != 0 || !slices.Equal(path, []string{"a"}) {
+		t.Errorf("Dijkstra(a, a) = %v, %v, %v", path, dist, err)
+	}
+	if _, _, err := g.Dijkstra("a", "island"); !errors.Is(err, ErrNoPath) {
+		t.Errorf("Dijkstra to an unreachable node = %v", err)
+	}
+	if _, _, err := g.Dijkstra("a", "z"); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("Dijkstra to a missing node = %v", err)
+	}
+	g.AddEdge("a", "e", -1)
+	if _, _, err := g.Dijkstra("a", "d"); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("Dijkstra with a negative weight = %v", err)
+	}
+}
+
+func TestAStar(t *testing.T) {
+	g := grid(20, 20)
+	// A wall in the middle of the grid, with a gap at the bottom.
+	for y := range 19 {
+		g.RemoveEdge([2]int{9, y}, [2]int{10, y})
+	}
+	from, to := [2]int{0, 0}, [2]int{19, 0}
+	manhattan := func(n [2]int) float64 {
+		return math.Abs(float64(n[0]-to[0])) + math.Abs(float64(n[1]-to[1]))
+	}
+	path, dist, err := g.AStar(from, to, manhattan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, want, _ := g.Dijkstra(from, to)
+	if dist != want || dist != 19+2*19 || len(path) != int(dist)+1 {
+		t.Errorf("AStar distance = %v; Dijkstra = %v", dist, want)
+	}
+	for i := 1; i < len(path); i++ {
+		if _, ok := g.Edge(path[i-1], path[i]); !ok {
+			t.Fatalf("the path has no edge %v -> %v", path[i-1], path[i])
+		}
+	}
+}
+
+func TestAStarInconsistentHeuristic(t *testing.T) {
+	// The heuristic is admissible (it never overestimates), but not consistent: h(A) = 4 > weight(A, B) + h(B).
+	// So B is visited first through S -> B, and it must be reopened when the shorter path through A is found.
+	g := NewDirected[string]()
+	g.AddEdge("S", "A", 1)
+	g.AddEdge("A", "B", 1)
+	g.AddEdge("S", "B", 3)
+	g.AddEdge("B", "G", 3)
+	h := func(n string) float64 {
+		if n == "A" {
+			return 4
+		}
+		return 0
+	}
+	path, dist, err := g.AStar("S", "G", h)
+	if err != nil || dist != 5 || !slices.Equal(path, []string{"S", "A", "B", "G"}) {
+		t.Errorf("AStar = %v, %v, %v; expected [S A B G] with cost 5", path, dist, err)
+	}
+}
+
+func TestTopologicalSort(t *testing.T) {
+	g := NewDirected[string]()
+	g.AddEdge("shirt", "tie", 1)
+	g.AddEdge("tie", "jacket", 1)
+	g.AddEdge("pants", "shoes", 1)
+	g.AddEdge("pants", "belt", 1)
+	g.AddEdge("belt", "jacket", 1)
+	g.AddNode("watch")
+	order, err := g.TopologicalSort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"shirt", "pants", "watch", "tie", "shoes", "belt", "jacket"}; !slices.Equal(order, want) {
+		t.Errorf("order = %v; expected %v", order, want)
+	}
+	position := map[string]int{}
+	for i, n := range order {
+		position[n] = i
+	}
+	for _, n := range g.Nodes() {
+		for _, e := range g.Edges(n) {
+			if position[e.From] > position[e.To] {
+				t.Errorf("%v comes after %v", e.From, e.To)
+			}
+		}
+	}
+	if g.HasCycle() {
+		t.Errorf("DAG must not have cycles")
+	}
+	g.AddEdge("jacket", "shirt", 1)
+	if _, err := g.TopologicalSort(); !errors.Is(err, ErrCycle) || !strings.Contains(err.Error(), "[shirt tie jacket shirt]") {
+		t.Errorf("TopologicalSort with a cycle = %v", err)
+	}
+	if _, err := NewUndirected[int]().TopologicalSort(); !errors.Is(err, ErrUndirected) {
+		t.Errorf("TopologicalSort of an undirected graph = %v", err)
+	}
+}
+
+func TestFindCycle(t *testing.T) {
+	d := NewDirected[int]()
+	d.AddEdge(1, 2, 1)
+	d.AddEdge(2, 3, 1)
+	d.AddEdge(1, 3, 1)
+	if c := d.FindCycle(); c != nil {
+		t.Errorf("FindCycle of a DAG = %v", c)
+	}
+	d.AddEdge(3, 4, 1)
+	d.AddEdge(4, 2, 1)
+	if c := d.FindCycle(); !slices.Equal(c, []int{2, 3, 4, 2}) {
+		t.Errorf("FindCycle = %v", c)
+	}
+
+	u := NewUndirected[int]()
+	u.AddEdge(1, 2, 1)
+	u.AddEdge(2, 3, 1)
+	if c := u.FindCycle(); c != nil {
+		t.Errorf("an undirected path must not be a cycle, got %v", c)
+	}
+	u.AddEdge(3, 1, 1)
+	if c := u.FindCycle(); len(c) != 4 || c[0] != c[3] {
+		t.Errorf("FindCycle = %v", c)
+	}
+	loop := NewUndirected[string]()
+	loop.AddEdge("a", "a", 1)
+	if c := loop.FindCycle(); !slices.Equal(c, []string{"a", "a"}) {
+		t.Errorf("self-loop = %v", c)
+	}
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	g := NewDirected[string]()
+	for _, e := range [][2]string{
+		{"a", "b"}, {"b", "c"}, {"c", "a"}, // Component {a b c}
+		{"c", "d"},
+		{"d", "e"}, {"e", "d"}, // Component {d e}
+		{"e", "f"}, // Component {f}
+	} {
+		g.AddEdge(e[0], e[1], 1)
+	}
+	got := g.StronglyConnectedComponents()
+	want := [][]string{{"f"}, {"d", "e"}, {"a", "b", "c"}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("SCC = %v; expected %v", got, want)
+	}
+
+	u := NewUndirected[int]()
+	u.AddEdge(1, 2, 1)
+	u.AddEdge(3, 4, 1)
+	u.AddNode(5)
+	if got := u.StronglyConnectedComponents(); len(got) != 3 {
+		t.Errorf("connected components = %v", got)
+	}
+}
+
+func TestDOT(t *testing.T) {
+	d := NewDirected[string]()
+	d.AddEdge("app", "lib", 2.5)
+	d.AddEdge("app", `say "hi"`, 1)
+	want := `digraph "deps" {
+  "app";
+  "lib";
+  "say \"hi\"";
+  "app" -> "lib" [label="2.5"];
+  "app" -> "say \"hi\"";
+}
+`
+	if got := d.DOT("deps"); got != want {
+		t.Errorf("DOT =\n%s\nexpected\n%s", got, want)
+	}
+	u := NewUndirected[int]()
+	u.AddEdge(1, 2, 1)
+	u.AddEdge(2, 2, 1)
+	if got := u.DOT("g"); got != "graph \"g\" {\n  \"1\";\n  \"2\";\n  \"1\" -- \"2\";\n  \"2\" -- \"2\";\n}\n" {
+		t.Errorf("undirected edges must be written once, got\n%s", got)
+	}
+}
+
+func BenchmarkDijkstra(b *testing.B) {
+	g := grid(100, 100)
+	for b.Loop() {
+		g.Dijkstra([2]int{0, 0}, [2]int{99, 99})
+	}
+}
+
+func BenchmarkAStar(b *testing.B) {
+	g := grid(100, 100)
+	to := [2]int{99, 99}
+	h := func(n [2]int) float64 { return float64(to[0] - n[0] + to[1] - n[1]) }
+	for b.Loop() {
+		g.AStar([2]int{0, 0}, to, h)
+	}
+}
@@ -0,0 +1,157 @@
+// Topological Sort and Cycles
+// A topological order of a directed graph lists each node before the nodes it points to (e.g., each task before
+// the tasks that depend on it). It exists only when the graph has no cycles (a directed acyclic graph, or DAG).
+// Kahn's algorithm repeatedly takes a node without incoming edges, and removes its edges. When no such node is
+// left, but some nodes remain, the remaining nodes are in cycles.
+// The cycles are found with a DFS: a cycle exists when the search reaches a node that is still on the current path.
+
+package graph
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Topological Sort
+// The method below returns the nodes in topological order, or ErrCycle if the graph has a cycle.
+// The ready nodes are taken in insertion order, so the result is deterministic.
+func (g *Graph[N]) TopologicalSort() ([]N, error) {
+	if !g.directed {
+		return nil, fmt.Errorf("%w: topological sort requires a directed graph", ErrUndirected)
+	}
+	incoming := map[N]int{}
+	for _, n := range g.nodes {
+		for _, e := range g.edges[n] {
+			incoming[e.To]++
+		}
+	}
+	var ready []N
+	for _, n := range g.nodes {
+		if incoming[n] == 0 {
+			ready = append(ready, n)
+		}
+	}
+	order := make([]N, 0, len(g.nodes))
+	for len(ready) > 0 {
+		n := ready[0]
+		ready = ready[1:]
+		order = append(order, n)
+		for _, e := range g.edges[n] {
+			incoming[e.To]--
+			if incoming[e.To] == 0 {
+				ready = append(ready, e.To)
+			}
+		}
+	}
+	if len(order) < len(g.nodes) {
+		return nil, fmt.Errorf("%w: %v", ErrCycle, g.FindCycle())
+	}
+	return order, nil
+}
+
+// Finding Cycles
+// The method below returns the nodes of a cycle, with the first node repeated at the end (e.g., [a b c a]),
+// or nil if the graph has no cycles.
+// The nodes are colored during the DFS: white (not visited), gray (on the current path) and black (done).
+// In directed graphs, an edge to a gray node closes a cycle.
+// In undirected graphs, each edge is seen in both directions, so the edge back to the parent is not a cycle,
+// unless it is a self-loop.
+func (g *Graph[N]) FindCycle() []N {
+	const (
+		white = iota
+		gray
+		black
+	)
+	color := map[N]int{}
+	parent := map[N]N{}
+	var cycle []N
+	var visit func(n N, from *N) bool
+	visit = func(n N, from *N) bool {
+		color[n] = gray
+		skippedParent := false
+		for _, e := range g.edges[n] {
+			if !g.directed && from != nil && e.To == *from && !skippedParent {
+				skippedParent = true
+				continue
+			}
+			switch color[e.To] {
+			case gray:
+				cycle = []N{e.To}
+				for m := n; m != e.To; m = parent[m] {
+					cycle = append(cycle, m)
+				}
+				cycle = append(cycle, e.To)
+				slices.Reverse(cycle)
+				return true
+			case white:
+				parent[e.To] = n
+				if visit(e.To, &n) {
+					return true
+				}
+			}
+		}
+		color[n] = black
+		return false
+	}
+	for _, n := range g.nodes {
+		if color[n] == white && visit(n, nil) {
+			return cycle
+		}
+	}
+	return nil
+}
+
+func (g *Graph[N]) HasCycle() bool {
+	return g.FindCycle() != nil
+}
+
+// Strongly Connected Components
+// In a directed graph, a strongly connected component (SCC) is a maximal group of nodes where each node can reach
+// all the others. Each cycle is inside a component, and the graph of the components is a DAG.
+// Tarjan's algorithm finds the components with a single DFS: each node gets an index (the visit order) and a
+// low-link (the smallest index reachable from it through the nodes on the stack). A node whose low-link is its
+// own index is the root of a component, made of the nodes above it on the stack.
+// The components are returned in reverse topological order (a component before the components that reach it).
+// In undirected graphs, the components are the connected components.
+func (g *Graph[N]) StronglyConnectedComponents() [][]N {
+	index := map[N]int{}
+	low := map[N]int{}
+	onStack := map[N]bool{}
+	var stack []N
+	var components [][]N
+	var visit func(n N)
+	visit = func(n N) {
+		index[n] = len(index)
+		low[n] = index[n]
+		stack = append(stack, n)
+		onStack[n] = true
+		for _, e := range g.edges[n] {
+			if _, visited := index[e.To]; !visited {
+				visit(e.To)
+				low[n] = min(low[n], low[e.To])
+			} else if onStack[e.To] {
+				low[n] = min(low[n], index[e.To])
+			}
+		}
+		if low[n] == index[n] {
+			var component []N
+			for {
+				m := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[m] = false
+				component = append(component, m)
+				if m == n {
+					break
+				}
+			}
+			slices.Reverse(component)
+			components = append(components, component)
+		}
+	}
+	for _, n := range g.nodes {
+		if _, visited := index[n]; !visited {
+			visit(n)
+		}
+	}
+	return components
+}
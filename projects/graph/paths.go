@@ -0,0 +1,115 @@
+// Shortest Paths
+// The shortest path between two nodes is the path with the smallest sum of edge weights.
+// Dijkstra's algorithm visits the nodes in order of distance from the start node, using a priority queue, so when
+// a node is visited, its distance is final. It requires non-negative weights, and runs in O((V + E) log V).
+// A* (A-star) is Dijkstra's algorithm guided by a heuristic: an estimate of the distance from each node to the
+// target (e.g., the straight-line distance on a map). The nodes are visited in order of distance plus estimate,
+// so the search goes towards the target and visits fewer nodes.
+// The path is optimal when the heuristic never overestimates the real distance (an admissible heuristic).
+// When the heuristic is also consistent (h(a) <= weight(a, b) + h(b) for every edge, like the straight-line
+// distance), each node is visited once. Otherwise, a visited node may be reached later by a shorter path, and it
+// is reopened: it is queued again with the shorter distance, so the path is still optimal.
+// With a heuristic that always returns 0, A* is the same as Dijkstra's algorithm.
+
+package graph
+
+import (
+	"container/heap"
+	"fmt"
+	"slices"
+)
+
+// Dijkstra
+// The method below returns the shortest path from the node "from" to the node "to", with its total weight.
+func (g *Graph[N]) Dijkstra(from, to N) ([]N, float64, error) {
+	return g.AStar(from, to, func(N) float64 { return 0 })
+}
+
+// A*
+// The method below returns the shortest path from the node "from" to the node "to", with its total weight,
+// using the heuristic to estimate the distance from each node to "to".
+// The negative weights are reported with ErrNegativeWeight when the search reaches them.
+func (g *Graph[N]) AStar(from, to N, heuristic func(N) float64) ([]N, float64, error) {
+	for _, n := range []N{from, to} {
+		if !g.HasNode(n) {
+			return nil, 0, fmt.Errorf("%w: %v", ErrNodeNotFound, n)
+		}
+	}
+	dist := map[N]float64{from: 0}
+	prev := map[N]N{}
+	done := map[N]bool{}
+	queue := &pathQueue[N]{}
+	heap.Push(queue, pathItem[N]{node: from, priority: heuristic(from)})
+	for queue.Len() > 0 {
+		n := heap.Pop(queue).(pathItem[N]).node
+		if done[n] {
+			// The node was queued again with a shorter distance, and this is an outdated item.
+			continue
+		}
+		if n == to {
+			return g.path(prev, from, to), dist[to], nil
+		}
+		done[n] = true
+		for _, e := range g.edges[n] {
+			if e.Weight < 0 {
+				return nil, 0, fmt.Errorf("%w: %v -> %v (%v)", ErrNegativeWeight, e.From, e.To, e.Weight)
+			}
+			d := dist[n] + e.Weight
+			if old, ok := dist[e.To]; !ok || d < old {
+				// A visited node is only improved with an inconsistent heuristic, and it is reopened.
+				delete(done, e.To)
+				dist[e.To] = d
+				prev[e.To] = n
+				heap.Push(queue, pathItem[N]{node: e.To, priority: d + heuristic(e.To)})
+			}
+		}
+	}
+	return nil, 0, fmt.Errorf("%w: %v -> %v", ErrNoPath, from, to)
+}
+
+// path rebuilds the path by following the previous nodes back from the target.
+func (g *Graph[N]) path(prev map[N]N, from, to N) []N {
+	path := []N{to}
+	for n := to; n != from; {
+		n = prev[n]
+		path = append(path, n)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// Priority Queue
+// The queue below implements heap.Interface, ordered by priority. The nodes are pushed again when a shorter
+// distance is found (instead of updating their priority), and the outdated items are skipped when popped.
+// The ties are broken by insertion order, so the results are deterministic.
+type pathItem[N comparable] struct {
+	node     N
+	priority float64
+	seq      int
+}
+type pathQueue[N comparable] struct {
+	items []pathItem[N]
+	seq   int
+}
+
+func (q *pathQueue[N]) Len() int {
+	return len(q.items)
+}
+func (q *pathQueue[N]) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	return a.priority < b.priority || (a.priority == b.priority && a.seq < b.seq)
+}
+func (q *pathQueue[N]) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+}
+func (q *pathQueue[N]) Push(x any) {
+	it := x.(pathItem[N])
+	it.seq = q.seq
+	q.seq++
+	q.items = append(q.items, it)
+}
+func (q *pathQueue[N]) Pop() any {
+	it := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return it
+}
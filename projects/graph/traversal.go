@@ -0,0 +1,69 @@
+// Traversals
+// A traversal visits all the nodes reachable from a start node, once each.
+// Breadth-first search (BFS) visits the nodes by distance (number of edges): first the start node, then its
+// neighbors, then the neighbors of the neighbors, and so on. It uses a queue.
+// Depth-first search (DFS) follows each path as deep as possible, before going back to try the other paths.
+// It uses a stack (or recursion).
+// Both are O(V + E), where V is the number of nodes and E the number of edges.
+
+package graph
+
+import "iter"
+
+// Breadth-First Search
+// The iterator below yields the nodes in BFS order. The nodes are marked as visited when they are queued,
+// so each node is queued once. The iteration stops when the loop breaks, so the search can stop early
+// (e.g., when the target is found).
+func (g *Graph[N]) BFS(start N) iter.Seq[N] {
+	return func(yield func(N) bool) {
+		if !g.HasNode(start) {
+			return
+		}
+		visited := map[N]bool{start: true}
+		queue := []N{start}
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+			if !yield(n) {
+				return
+			}
+			for _, e := range g.edges[n] {
+				if !visited[e.To] {
+					visited[e.To] = true
+					queue = append(queue, e.To)
+				}
+			}
+		}
+	}
+}
+
+// Depth-First Search
+// The iterator below yields the nodes in DFS preorder (each node before its descendants).
+// The stack is explicit, instead of recursion, so deep graphs do not grow the goroutine stack.
+// The neighbors are pushed in reverse order, so they are visited in insertion order, like the recursive version.
+func (g *Graph[N]) DFS(start N) iter.Seq[N] {
+	return func(yield func(N) bool) {
+		if !g.HasNode(start) {
+			return
+		}
+		visited := map[N]bool{}
+		stack := []N{start}
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if visited[n] {
+				continue
+			}
+			visited[n] = true
+			if !yield(n) {
+				return
+			}
+			edges := g.edges[n]
+			for i := len(edges) - 1; i >= 0; i-- {
+				if !visited[edges[i].To] {
+					stack = append(stack, edges[i].To)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package linkedlist
+
+import (
+	"container/heap"
+	"container/list"
+	"context"
+	"math/rand/v2"
+	"testing"
+)
+
+// The benchmarks below compare the containers with the standard library.
+// Each iteration pushes and pops 1000 values.
+
+const benchSize = 1000
+
+func BenchmarkDeque(b *testing.B) {
+	for b.Loop() {
+		var d Deque[int]
+		for i := range benchSize {
+			d.PushBack(i)
+		}
+		for range benchSize {
+			d.PopFront()
+		}
+	}
+}
+
+func BenchmarkDoublyList(b *testing.B) {
+	for b.Loop() {
+		l := NewDoublyList[int]()
+		for i := range benchSize {
+			l.PushBack(i)
+		}
+		for range benchSize {
+			l.PopFront()
+		}
+	}
+}
+
+func BenchmarkContainerList(b *testing.B) {
+	for b.Loop() {
+		l := list.New()
+		for i := range benchSize {
+			l.PushBack(i)
+		}
+		for range benchSize {
+			l.Remove(l.Front())
+		}
+	}
+}
+
+func BenchmarkQueue(b *testing.B) {
+	ctx := context.Background()
+	for b.Loop() {
+		q := NewQueue[int](0)
+		for i := range benchSize {
+			q.Push(ctx, i)
+		}
+		for range benchSize {
+			q.Pop(ctx)
+		}
+	}
+}
+
+func BenchmarkPriorityQueue(b *testing.B) {
+	values := randomInts(benchSize)
+	for b.Loop() {
+		pq := NewPriorityQueue(func(a, b int) int { return a - b })
+		for _, v := range values {
+			pq.Push(v)
+		}
+		for range benchSize {
+			pq.Pop()
+		}
+	}
+}
+
+// intHeap is the usual container/heap implementation.
+type intHeap []int
+
+func (h intHeap) Len() int           { return len(h) }
+func (h intHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *intHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+func BenchmarkContainerHeap(b *testing.B) {
+	values := randomInts(benchSize)
+	for b.Loop() {
+		h := &intHeap{}
+		for _, v := range values {
+			heap.Push(h, v)
+		}
+		for range benchSize {
+			heap.Pop(h)
+		}
+	}
+}
+
+func randomInts(n int) []int {
+	r := rand.New(rand.NewPCG(7, 8))
+	s := make([]int, n)
+	for i := range s {
+		s[i] = r.IntN(1 << 20)
+	}
+	return s
+}
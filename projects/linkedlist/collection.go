@@ -0,0 +1,28 @@
+package linkedlist
+
+import "iter"
+
+// Collection is the iteration interface shared by all the containers of the package.
+// Values yields the values in the order of the container (e.g., front to back, or top to bottom).
+type Collection[T any] interface {
+	Len() int
+	Values() iter.Seq[T]
+}
+
+var (
+	_ Collection[int] = (*List[int])(nil)
+	_ Collection[int] = (*DoublyList[int])(nil)
+	_ Collection[int] = (*Deque[int])(nil)
+	_ Collection[int] = (*Stack[int])(nil)
+	_ Collection[int] = (*Queue[int])(nil)
+	_ Collection[int] = (*PriorityQueue[int])(nil)
+)
+
+// Collect returns the values of the collection as a slice.
+func Collect[T any](c Collection[T]) []T {
+	s := make([]T, 0, c.Len())
+	for v := range c.Values() {
+		s = append(s, v)
+	}
+	return s
+}
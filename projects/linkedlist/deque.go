@@ -0,0 +1,182 @@
+package linkedlist
+
+import "iter"
+
+// Deque is a double-ended queue backed by a ring buffer.
+// Pushing and popping at both ends is O(1) amortized, and the values are stored contiguously,
+// so it allocates much less than a linked list.
+// The zero value is an empty deque ready to use.
+type Deque[T any] struct {
+	buf  []T
+	head int
+	len  int
+}
+
+// NewDeque returns an empty deque. The zero value of Deque is also an empty deque ready to use.
+func NewDeque[T any]() *Deque[T] {
+	return &Deque[T]{}
+}
+
+// Len returns the number of values, in O(1).
+func (d *Deque[T]) Len() int {
+	return d.len
+}
+
+// Empty reports whether the deque has no values.
+func (d *Deque[T]) Empty() bool {
+	return d.len == 0
+}
+
+// index returns the position in the buffer of the i-th value.
+// The buffer length is always a power of two, so the modulo is a mask.
+func (d *Deque[T]) index(i int) int {
+	return (d.head + i) & (len(d.buf) - 1)
+}
+
+// grow doubles the buffer when it is full, and moves the values to its start.
+func (d *Deque[T]) grow() {
+	if d.len < len(d.buf) {
+		return
+	}
+	buf := make([]T, max(8, 2*len(d.buf)))
+	for i := range d.len {
+		buf[i] = d.buf[d.index(i)]
+	}
+	d.buf, d.head = buf, 0
+}
+
+// PushBack adds the value at the back of the deque.
+func (d *Deque[T]) PushBack(value T) {
+	d.grow()
+	d.buf[d.index(d.len)] = value
+	d.len++
+}
+
+// PushFront adds the value at the front of the deque.
+func (d *Deque[T]) PushFront(value T) {
+	d.grow()
+	d.head = d.index(len(d.buf) - 1)
+	d.buf[d.head] = value
+	d.len++
+}
+
+// PopFront removes and returns the first value, or false if the deque is empty.
+func (d *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.len == 0 {
+		return zero, false
+	}
+	v := d.buf[d.head]
+	d.buf[d.head] = zero // Releases the reference for the garbage collector.
+	d.head = d.index(1)
+	d.len--
+	return v, true
+}
+
+// PopBack removes and returns the last value, or false if the deque is empty.
+func (d *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.len == 0 {
+		return zero, false
+	}
+	i := d.index(d.len - 1)
+	v := d.buf[i]
+	d.buf[i] = zero
+	d.len--
+	return v, true
+}
+
+// Front returns the first value without removing it, or false if the deque is empty.
+func (d *Deque[T]) Front() (T, bool) {
+	return d.At(0)
+}
+
+// Back returns the last value without removing it, or false if the deque is empty.
+func (d *Deque[T]) Back() (T, bool) {
+	return d.At(d.len - 1)
+}
+
+// At returns the value at the index (0 is the front), or false if the index is out of range.
+func (d *Deque[T]) At(i int) (T, bool) {
+	if i < 0 || i >= d.len {
+		var zero T
+		return zero, false
+	}
+	return d.buf[d.index(i)], true
+}
+
+// Clear removes all the values, and keeps the buffer for the next values.
+func (d *Deque[T]) Clear() {
+	clear(d.buf)
+	d.head, d.len = 0, 0
+}
+
+// All returns an iterator over the indexes and values, from the front to the back.
+func (d *Deque[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := range d.len {
+			if !yield(i, d.buf[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the values, from the front to the back.
+func (d *Deque[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := range d.len {
+			if !yield(d.buf[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// Stack is a last-in, first-out container.
+// The zero value is an empty stack ready to use.
+type Stack[T any] struct {
+	items Deque[T]
+}
+
+// NewStack returns an empty stack. The zero value of Stack is also an empty stack ready to use.
+func NewStack[T any]() *Stack[T] {
+	return &Stack[T]{}
+}
+
+// Len returns the number of values, in O(1).
+func (s *Stack[T]) Len() int {
+	return s.items.Len()
+}
+
+// Empty reports whether the stack has no values.
+func (s *Stack[T]) Empty() bool {
+	return s.items.Empty()
+}
+
+// Push adds the value at the top of the stack.
+func (s *Stack[T]) Push(value T) {
+	s.items.PushBack(value)
+}
+
+// Pop removes and returns the top value, or false if the stack is empty.
+func (s *Stack[T]) Pop() (T, bool) {
+	return s.items.PopBack()
+}
+
+// Peek returns the top value without removing it, or false if the stack is empty.
+func (s *Stack[T]) Peek() (T, bool) {
+	return s.items.Back()
+}
+
+// Values returns an iterator over the values, from the top to the bottom.
+func (s *Stack[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := s.items.Len() - 1; i >= 0; i-- {
+			v, _ := s.items.At(i)
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
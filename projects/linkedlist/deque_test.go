@@ -0,0 +1,93 @@
+package linkedlist
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestDequeRandomOperations(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	var d Deque[int]
+	var model []int
+	for i := range 10000 {
+		switch r.IntN(5) {
+		case 0, 1:
+			d.PushBack(i)
+			model = append(model, i)
+		case 2:
+			d.PushFront(i)
+			model = slices.Insert(model, 0, i)
+		case 3:
+			v, ok := d.PopFront()
+			if ok != (len(model) > 0) || (ok && v != model[0]) {
+				t.Fatalf("PopFront = %v, %v; expected %v", v, ok, model)
+			}
+			if ok {
+				model = model[1:]
+			}
+		case 4:
+			v, ok := d.PopBack()
+			if ok != (len(model) > 0) || (ok && v != model[len(model)-1]) {
+				t.Fatalf("PopBack = %v, %v", v, ok)
+			}
+			if ok {
+				model = model[:len(model)-1]
+			}
+		}
+		if d.Len() != len(model) {
+			t.Fatalf("Len = %d; expected %d", d.Len(), len(model))
+		}
+	}
+	if got := Collect[int](&d); !slices.Equal(got, model) {
+		t.Fatalf("values = %v; expected %v", got, model)
+	}
+	for i, v := range d.All() {
+		if w, _ := d.At(i); w != v || v != model[i] {
+			t.Fatalf("At(%d) = %v; expected %v", i, w, v)
+		}
+	}
+}
+
+func TestDequeEmpty(t *testing.T) {
+	d := NewDeque[string]()
+	if _, ok := d.PopFront(); ok {
+		t.Errorf("PopFront on an empty deque must return false")
+	}
+	if _, ok := d.Back(); ok {
+		t.Errorf("Back on an empty deque must return false")
+	}
+	d.PushFront("A")
+	d.PushBack("B")
+	if f, _ := d.Front(); f != "A" {
+		t.Errorf("Front = %q", f)
+	}
+	if _, ok := d.At(2); ok {
+		t.Errorf("At out of range must return false")
+	}
+	d.Clear()
+	if !d.Empty() || len(Collect[string](d)) != 0 {
+		t.Errorf("Clear must empty the deque")
+	}
+}
+
+func TestStack(t *testing.T) {
+	var s Stack[int]
+	for i := range 3 {
+		s.Push(i)
+	}
+	if got := Collect[int](&s); !slices.Equal(got, []int{2, 1, 0}) {
+		t.Errorf("values = %v; expected top to bottom", got)
+	}
+	if v, _ := s.Peek(); v != 2 {
+		t.Errorf("Peek = %d", v)
+	}
+	for want := 2; want >= 0; want-- {
+		if v, ok := s.Pop(); !ok || v != want {
+			t.Errorf("Pop = %d, %v; expected %d", v, ok, want)
+		}
+	}
+	if _, ok := s.Pop(); ok || !s.Empty() {
+		t.Errorf("Pop on an empty stack must return false")
+	}
+}
@@ -0,0 +1,210 @@
+package linkedlist
+
+import "iter"
+
+// DoublyList is a doubly linked list.
+// Unlike List, the nodes are exposed in both directions, and they are the handles of the values: any node can be
+// removed, or have nodes inserted around it, in O(1).
+type DoublyList[T comparable] struct {
+	front *DoublyNode[T]
+	back  *DoublyNode[T]
+	len   int
+}
+
+// DoublyNode is an element of a DoublyList.
+type DoublyNode[T any] struct {
+	Value T
+	next  *DoublyNode[T]
+	prev  *DoublyNode[T]
+	// list is the owner of the node, so nodes of other lists (or removed ones) are rejected.
+	list any
+}
+
+// Next returns the next node, or nil if n is the last node.
+func (n *DoublyNode[T]) Next() *DoublyNode[T] {
+	return n.next
+}
+
+// Prev returns the previous node, or nil if n is the first node.
+func (n *DoublyNode[T]) Prev() *DoublyNode[T] {
+	return n.prev
+}
+
+// NewDoublyList returns an empty list. The zero value of DoublyList is also an empty list ready to use.
+func NewDoublyList[T comparable]() *DoublyList[T] {
+	return &DoublyList[T]{}
+}
+
+// Len returns the number of nodes, in O(1).
+func (l *DoublyList[T]) Len() int {
+	return l.len
+}
+
+// Empty reports whether the list has no nodes.
+func (l *DoublyList[T]) Empty() bool {
+	return l.len == 0
+}
+
+// Front returns the first node, or nil if the list is empty.
+func (l *DoublyList[T]) Front() *DoublyNode[T] {
+	return l.front
+}
+
+// Back returns the last node, or nil if the list is empty.
+func (l *DoublyList[T]) Back() *DoublyNode[T] {
+	return l.back
+}
+
+// PushFront adds the value at the front of the list, and returns its node.
+func (l *DoublyList[T]) PushFront(value T) *DoublyNode[T] {
+	return l.insert(value, nil, l.front)
+}
+
+// PushBack adds the value at the back of the list, and returns its node.
+func (l *DoublyList[T]) PushBack(value T) *DoublyNode[T] {
+	return l.insert(value, l.back, nil)
+}
+
+// InsertBefore inserts the value before the node, and returns the new node.
+// It returns nil if the node does not belong to the list.
+func (l *DoublyList[T]) InsertBefore(value T, mark *DoublyNode[T]) *DoublyNode[T] {
+	if !l.owns(mark) {
+		return nil
+	}
+	return l.insert(value, mark.prev, mark)
+}
+
+// InsertAfter inserts the value after the node, and returns the new node.
+// It returns nil if the node does not belong to the list.
+func (l *DoublyList[T]) InsertAfter(value T, mark *DoublyNode[T]) *DoublyNode[T] {
+	if !l.owns(mark) {
+		return nil
+	}
+	return l.insert(value, mark, mark.next)
+}
+
+func (l *DoublyList[T]) insert(value T, prev, next *DoublyNode[T]) *DoublyNode[T] {
+	n := &DoublyNode[T]{Value: value, prev: prev, next: next, list: l}
+	if prev == nil {
+		l.front = n
+	} else {
+		prev.next = n
+	}
+	if next == nil {
+		l.back = n
+	} else {
+		next.prev = n
+	}
+	l.len++
+	return n
+}
+
+// Remove removes the node from the list, and reports whether it was removed.
+func (l *DoublyList[T]) Remove(n *DoublyNode[T]) bool {
+	if !l.owns(n) {
+		return false
+	}
+	if n.prev == nil {
+		l.front = n.next
+	} else {
+		n.prev.next = n.next
+	}
+	if n.next == nil {
+		l.back = n.prev
+	} else {
+		n.next.prev = n.prev
+	}
+	n.next, n.prev, n.list = nil, nil, nil
+	l.len--
+	return true
+}
+
+func (l *DoublyList[T]) owns(n *DoublyNode[T]) bool {
+	return n != nil && n.list == l
+}
+
+// PopFront removes the first node and returns it, or nil if the list is empty.
+func (l *DoublyList[T]) PopFront() *DoublyNode[T] {
+	n := l.front
+	l.Remove(n)
+	return n
+}
+
+// PopBack removes the last node and returns it, or nil if the list is empty.
+func (l *DoublyList[T]) PopBack() *DoublyNode[T] {
+	n := l.back
+	l.Remove(n)
+	return n
+}
+
+// Get returns the node at the index, or nil if the index is out of range.
+// The list is walked from the nearest end.
+func (l *DoublyList[T]) Get(index int) *DoublyNode[T] {
+	if index < 0 || index >= l.len {
+		return nil
+	}
+	if index < l.len/2 {
+		n := l.front
+		for range index {
+			n = n.next
+		}
+		return n
+	}
+	n := l.back
+	for range l.len - 1 - index {
+		n = n.prev
+	}
+	return n
+}
+
+// Find returns the first node with the value, or nil if there is none.
+func (l *DoublyList[T]) Find(value T) *DoublyNode[T] {
+	for n := l.front; n != nil; n = n.next {
+		if n.Value == value {
+			return n
+		}
+	}
+	return nil
+}
+
+// Contains reports whether a node has the value, in O(n).
+func (l *DoublyList[T]) Contains(value T) bool {
+	return l.Find(value) != nil
+}
+
+// Clear removes all the nodes. The removed nodes are detached, so they are rejected by the list.
+func (l *DoublyList[T]) Clear() {
+	for n := l.front; n != nil; {
+		next := n.next
+		n.next, n.prev, n.list = nil, nil, nil
+		n = next
+	}
+	l.front, l.back, l.len = nil, nil, 0
+}
+
+// All returns an iterator over the indexes and values, from the front to the back.
+// The next node is read before yielding, so the current node can be removed during the iteration.
+func (l *DoublyList[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for n := l.front; n != nil; {
+			next := n.next
+			if !yield(i, n.Value) {
+				return
+			}
+			n = next
+			i++
+		}
+	}
+}
+
+// Values returns an iterator over the values, from the front to the back.
+func (l *DoublyList[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range l.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package linkedlist
+
+import (
+	"slices"
+	"testing"
+)
+
+// checkDoubly verifies the values in both directions, and the cached length.
+func checkDoubly[T comparable](t *testing.T, l *DoublyList[T], want ...T) {
+	t.Helper()
+	var forward, backward []T
+	for n := l.Front(); n != nil; n = n.Next() {
+		forward = append(forward, n.Value)
+	}
+	for n := l.Back(); n != nil; n = n.Prev() {
+		backward = append(backward, n.Value)
+	}
+	slices.Reverse(backward)
+	if !slices.Equal(forward, want) || !slices.Equal(backward, want) {
+		t.Fatalf("forward = %v, backward = %v; expected %v", forward, backward, want)
+	}
+	if l.Len() != len(want) || l.Empty() != (len(want) == 0) {
+		t.Fatalf("Len = %d; expected %d", l.Len(), len(want))
+	}
+}
+
+func TestDoublyPush(t *testing.T) {
+	l := NewDoublyList[int]()
+	checkDoubly(t, l)
+	two := l.PushBack(2)
+	l.PushFront(1)
+	l.PushBack(4)
+	l.InsertAfter(3, two)
+	l.InsertBefore(0, l.Front())
+	l.InsertAfter(5, l.Back())
+	checkDoubly(t, l, 0, 1, 2, 3, 4, 5)
+	for i := range 6 {
+		if n := l.Get(i); n == nil || n.Value != i {
+			t.Errorf("Get(%d) = %v", i, n)
+		}
+	}
+	if l.Get(-1) != nil || l.Get(6) != nil {
+		t.Errorf("Get out of range must be nil")
+	}
+	if l.Find(3) != l.Get(3) || l.Find(9) != nil || !l.Contains(5) || l.Contains(6) {
+		t.Errorf("Find or Contains is wrong")
+	}
+}
+
+func TestDoublyRemove(t *testing.T) {
+	l := NewDoublyList[string]()
+	a := l.PushBack("A")
+	b := l.PushBack("B")
+	c := l.PushBack("C")
+	if !l.Remove(b) {
+		t.Fatalf("Remove(B) = false")
+	}
+	checkDoubly(t, l, "A", "C")
+	if l.Remove(b) || l.Remove(nil) {
+		t.Errorf("Remove of a removed node must return false")
+	}
+	if l.InsertAfter("X", b) != nil {
+		t.Errorf("InsertAfter a removed node must return nil")
+	}
+	other := NewDoublyList[string]()
+	if other.Remove(a) || other.InsertBefore("X", a) != nil {
+		t.Errorf("nodes of other lists must not be changed")
+	}
+	l.Remove(a)
+	l.Remove(c)
+	checkDoubly(t, l)
+}
+
+func TestDoublyPop(t *testing.T) {
+	l := NewDoublyList[int]()
+	if l.PopFront() != nil || l.PopBack() != nil {
+		t.Errorf("Pop on an empty list must return nil")
+	}
+	for i := range 4 {
+		l.PushBack(i)
+	}
+	if n := l.PopFront(); n.Value != 0 || n.Next() != nil || n.Prev() != nil {
+		t.Errorf("PopFront = %v", n)
+	}
+	if n := l.PopBack(); n.Value != 3 {
+		t.Errorf("PopBack = %v", n)
+	}
+	checkDoubly(t, l, 1, 2)
+	l.PopBack()
+	l.PopBack()
+	checkDoubly(t, l)
+	l.PushFront(7)
+	checkDoubly(t, l, 7)
+}
+
+func TestDoublyClear(t *testing.T) {
+	l := NewDoublyList[int]()
+	n := l.PushBack(1)
+	l.PushBack(2)
+	l.Clear()
+	checkDoubly(t, l)
+	if l.Remove(n) {
+		t.Errorf("nodes must be detached by Clear")
+	}
+}
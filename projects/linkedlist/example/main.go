@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"linkedlist"
+)
+
+func main() {
+	list := linkedlist.NewList[string]()
+	list.Append("A")
+	list.Append("B")
+	list.Append("C")
+	list.Prepend("D")
+	list.Prepend("E")
+	list.Print()
+	l := list.Len()
+	fmt.Println("Len:", l)
+	n := list.Get(3)
+	fmt.Println("Node:", n.Value)
+	p := list.Pop()
+	fmt.Println("Node:", p.Value)
+	list.Print()
+
+	list.SortFunc(strings.Compare)
+	for i, v := range list.All() {
+		fmt.Print(i, "=", v, " ")
+	}
+	fmt.Println()
+
+	dl := linkedlist.NewDoublyList[string]()
+	b := dl.PushBack("B")
+	dl.PushFront("A")
+	dl.InsertAfter("C", b)
+	for n := dl.Back(); n != nil; n = n.Prev() {
+		fmt.Print(n.Value, " ")
+	}
+	fmt.Println()
+}
@@ -0,0 +1,3 @@
+module linkedlist
+
+go 1.24.1
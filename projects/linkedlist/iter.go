@@ -0,0 +1,166 @@
+package linkedlist
+
+import "iter"
+
+// FromSlice returns a new list with the values of the slice, in order.
+func FromSlice[T comparable](s []T) *List[T] {
+	l := NewList[T]()
+	for _, v := range s {
+		l.Append(v)
+	}
+	return l
+}
+
+// ToSlice returns the values of the list, in order.
+func (l *List[T]) ToSlice() []T {
+	s := make([]T, 0, l.len)
+	for v := range l.Values() {
+		s = append(s, v)
+	}
+	return s
+}
+
+// All returns an iterator over the indexes and values of the list, from the first to the last node.
+// The next node is read before yielding, so the current node can be removed during the iteration.
+func (l *List[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for n := l.root; n != nil; {
+			next := n.next
+			if !yield(i, n.Value) {
+				return
+			}
+			n = next
+			i++
+		}
+	}
+}
+
+// Values returns an iterator over the values of the list, from the first to the last node.
+func (l *List[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range l.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the indexes and values of the list, from the last to the first node.
+func (l *List[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := l.len - 1
+		for n := l.tail; n != nil; {
+			prev := n.prev
+			if !yield(i, n.Value) {
+				return
+			}
+			n = prev
+			i--
+		}
+	}
+}
+
+// IndexOf returns the index of the first node with the value, or -1 if there is none.
+func (l *List[T]) IndexOf(value T) int {
+	for i, v := range l.All() {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// InsertAt inserts the value at the index, so Get(index) returns its node.
+// The index must be in the range [0, Len()], and InsertAt reports whether the value was inserted.
+func (l *List[T]) InsertAt(index int, value T) bool {
+	switch {
+	case index < 0 || index > l.len:
+		return false
+	case index == l.len:
+		l.Append(value)
+		return true
+	case index == 0:
+		l.Prepend(value)
+		return true
+	}
+	next := l.Get(index)
+	n := &Node[T]{Value: value, prev: next.prev, next: next}
+	next.prev.next = n
+	next.prev = n
+	l.len++
+	return true
+}
+
+// RemoveValue removes the first node with the value, and reports whether it was removed.
+func (l *List[T]) RemoveValue(value T) bool {
+	for n := l.root; n != nil; n = n.next {
+		if n.Value == value {
+			l.unlink(n)
+			return true
+		}
+	}
+	return false
+}
+
+// Reverse reverses the order of the nodes in place.
+func (l *List[T]) Reverse() {
+	for n := l.root; n != nil; n = n.prev {
+		n.next, n.prev = n.prev, n.next
+	}
+	l.root, l.tail = l.tail, l.root
+}
+
+// SortFunc sorts the list in place with a stable merge sort, in O(n log n) time.
+// The cmp function returns a negative number when a < b, a positive number when a > b, and zero when a == b,
+// like the functions of the "cmp" and "slices" packages.
+// The nodes are relinked, not copied, so they keep their values.
+func (l *List[T]) SortFunc(cmp func(a, b T) int) {
+	l.root = mergeSort(l.root, l.len, cmp)
+	var prev *Node[T]
+	for n := l.root; n != nil; n = n.next {
+		n.prev = prev
+		prev = n
+	}
+	l.tail = prev
+}
+
+// mergeSort sorts the first size nodes of the chain, following only the next links.
+func mergeSort[T any](head *Node[T], size int, cmp func(a, b T) int) *Node[T] {
+	if size < 2 {
+		if head != nil {
+			head.next = nil
+		}
+		return head
+	}
+	half := size / 2
+	mid := head
+	for range half {
+		mid = mid.next
+	}
+	left := mergeSort(head, half, cmp)
+	right := mergeSort(mid, size-half, cmp)
+	return merge(left, right, cmp)
+}
+
+// merge merges two sorted chains.
+// On equal values, the left node comes first, which keeps the sort stable.
+func merge[T any](left, right *Node[T], cmp func(a, b T) int) *Node[T] {
+	var head Node[T]
+	tail := &head
+	for left != nil && right != nil {
+		if cmp(left.Value, right.Value) <= 0 {
+			tail.next, left = left, left.next
+		} else {
+			tail.next, right = right, right.next
+		}
+		tail = tail.next
+	}
+	if left != nil {
+		tail.next = left
+	} else {
+		tail.next = right
+	}
+	return head.next
+}
@@ -0,0 +1,181 @@
+package linkedlist
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"testing/quick"
+)
+
+func TestIterators(t *testing.T) {
+	l := FromSlice([]string{"A", "B", "C"})
+	var got []string
+	for i, v := range l.All() {
+		got = append(got, string(rune('0'+i))+v)
+	}
+	for i, v := range l.Backward() {
+		got = append(got, string(rune('0'+i))+v)
+	}
+	if want := []string{"0A", "1B", "2C", "2C", "1B", "0A"}; !slices.Equal(got, want) {
+		t.Errorf("All + Backward = %v; expected %v", got, want)
+	}
+	for v := range l.Values() {
+		if v == "B" {
+			break
+		}
+	}
+	// The current node can be removed while iterating.
+	for _, v := range l.All() {
+		l.RemoveValue(v)
+	}
+	checkList(t, l)
+	if s := l.ToSlice(); s == nil || len(s) != 0 {
+		t.Errorf("ToSlice of an empty list = %#v; expected an empty slice", s)
+	}
+}
+
+func TestInsertAt(t *testing.T) {
+	l := NewList[int]()
+	if l.InsertAt(1, 0) || l.InsertAt(-1, 0) {
+		t.Errorf("InsertAt out of range must return false")
+	}
+	l.InsertAt(0, 2)
+	l.InsertAt(0, 0)
+	l.InsertAt(1, 1)
+	l.InsertAt(3, 4)
+	l.InsertAt(3, 3)
+	checkList(t, l, 0, 1, 2, 3, 4)
+}
+
+func TestReverseSort(t *testing.T) {
+	l := FromSlice([]int{3, 1, 2})
+	l.Reverse()
+	checkList(t, l, 2, 1, 3)
+	l.SortFunc(cmp.Compare[int])
+	checkList(t, l, 1, 2, 3)
+	l.SortFunc(func(a, b int) int { return b - a })
+	checkList(t, l, 3, 2, 1)
+	empty := NewList[int]()
+	empty.Reverse()
+	empty.SortFunc(cmp.Compare[int])
+	checkList(t, empty)
+}
+
+// The property tests below compare the list with the same operations on a slice.
+
+func TestQuickSlices(t *testing.T) {
+	roundTrip := func(s []int) bool {
+		return slices.Equal(FromSlice(s).ToSlice(), s)
+	}
+	reverse := func(s []int) bool {
+		l := FromSlice(s)
+		l.Reverse()
+		// Walking the reversed list backward gives the original order.
+		var backward []int
+		for i, v := range l.Backward() {
+			if i != len(s)-1-len(backward) {
+				return false
+			}
+			backward = append(backward, v)
+		}
+		reversed := slices.Clone(s)
+		slices.Reverse(reversed)
+		return slices.Equal(backward, s) && slices.Equal(l.ToSlice(), reversed)
+	}
+	indexOf := func(s []int8, v int8) bool {
+		return FromSlice(s).IndexOf(v) == slices.Index(s, v)
+	}
+	for _, f := range []any{roundTrip, reverse, indexOf} {
+		if err := quick.Check(f, nil); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+type pair struct {
+	Key   int8
+	Order int
+}
+
+func TestQuickSortStable(t *testing.T) {
+	byKey := func(a, b pair) int { return cmp.Compare(a.Key, b.Key) }
+	f := func(keys []int8) bool {
+		s := make([]pair, len(keys))
+		for i, k := range keys {
+			// Few distinct keys, so there are many equal values, whose order must be kept.
+			s[i] = pair{k % 4, i}
+		}
+		l := FromSlice(s)
+		l.SortFunc(byKey)
+		slices.SortStableFunc(s, byKey)
+		return slices.Equal(l.ToSlice(), s)
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRandomOperations(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	l := NewList[int]()
+	var model []int
+	for range 5000 {
+		v := r.IntN(20)
+		switch op := r.IntN(7); op {
+		case 0:
+			l.Append(v)
+			model = append(model, v)
+		case 1:
+			l.Prepend(v)
+			model = slices.Insert(model, 0, v)
+		case 2:
+			i := r.IntN(len(model) + 2)
+			ok := l.InsertAt(i, v)
+			if ok != (i <= len(model)) {
+				t.Fatalf("InsertAt(%d) = %v with %d values", i, ok, len(model))
+			}
+			if ok {
+				model = slices.Insert(model, i, v)
+			}
+		case 3:
+			i := slices.Index(model, v)
+			if l.RemoveValue(v) != (i >= 0) {
+				t.Fatalf("RemoveValue(%d) is wrong", v)
+			}
+			if i >= 0 {
+				model = slices.Delete(model, i, i+1)
+			}
+		case 4:
+			n := l.Pop()
+			if (n != nil) != (len(model) > 1) {
+				t.Fatalf("Pop = %v with %d values", n, len(model))
+			}
+			if n != nil {
+				model = model[:len(model)-1]
+			}
+		case 5:
+			l.Reverse()
+			slices.Reverse(model)
+		case 6:
+			l.SortFunc(cmp.Compare[int])
+			slices.Sort(model)
+		}
+		if l.IndexOf(v) != slices.Index(model, v) {
+			t.Fatalf("IndexOf(%d) is wrong", v)
+		}
+		checkList(t, l, model...)
+	}
+}
+
+func BenchmarkSortFunc(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 2))
+	s := make([]int, 10000)
+	for i := range s {
+		s[i] = r.Int()
+	}
+	for b.Loop() {
+		l := FromSlice(s)
+		l.SortFunc(cmp.Compare[int])
+	}
+}
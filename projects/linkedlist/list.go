@@ -0,0 +1,168 @@
+// Package linkedlist implements generic linked lists.
+//
+// List is an indexed linked list that keeps track of its tail and length, so Append, Len, Last and Pop are O(1).
+// DoublyList is a node-based doubly linked list, with O(1) insertion and removal at both ends and around any node.
+//
+// The package also provides other containers: Deque (a ring buffer), Stack, Queue (a blocking queue
+// for producers and consumers) and PriorityQueue (a binary heap).
+// All of them implement the Collection interface.
+package linkedlist
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// List is a linked list of values, accessed by index.
+// The nodes are linked in both directions, so the tail can be popped in O(1) and the list iterated backwards,
+// but only the next links are exposed. Use DoublyList to hold the nodes, and insert or remove around them in O(1).
+type List[T comparable] struct {
+	root *Node[T]
+	tail *Node[T]
+	len  int
+}
+
+// Node is an element of a List.
+// The prev link is only used by the list, to pop the tail in O(1) and to iterate backwards.
+type Node[T any] struct {
+	Value T
+	next  *Node[T]
+	prev  *Node[T]
+}
+
+// Next returns the next node, or nil if n is the last node.
+func (n *Node[T]) Next() *Node[T] {
+	return n.next
+}
+
+// NewList returns an empty list. The zero value of List is also an empty list ready to use.
+func NewList[T comparable]() *List[T] {
+	return &List[T]{}
+}
+
+// Append adds the value at the end of the list, in O(1).
+func (l *List[T]) Append(value T) {
+	n := &Node[T]{Value: value}
+	if l.root == nil {
+		l.root = n
+	} else {
+		n.prev = l.tail
+		l.tail.next = n
+	}
+	l.tail = n
+	l.len++
+}
+
+// Prepend adds the value at the start of the list, in O(1).
+func (l *List[T]) Prepend(value T) {
+	n := &Node[T]{Value: value}
+	if l.root == nil {
+		l.tail = n
+	} else {
+		n.next = l.root
+		l.root.prev = n
+	}
+	l.root = n
+	l.len++
+}
+
+// Last returns the last node, or nil if the list is empty.
+func (l *List[T]) Last() *Node[T] {
+	return l.tail
+}
+
+// First returns the first node, or nil if the list is empty.
+func (l *List[T]) First() *Node[T] {
+	return l.root
+}
+
+// Contains reports whether a node has the value, in O(n).
+func (l *List[T]) Contains(value T) bool {
+	for n := l.root; n != nil; n = n.next {
+		if n.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of nodes, in O(1).
+func (l *List[T]) Len() int {
+	return l.len
+}
+
+// Get returns the node at the index, or nil if the index is out of range.
+func (l *List[T]) Get(index int) *Node[T] {
+	if index < 0 || index >= l.len {
+		return nil
+	}
+	n := l.root
+	for range index {
+		n = n.next
+	}
+	return n
+}
+
+// Delete removes the node at the index, and reports whether it was removed.
+func (l *List[T]) Delete(index int) bool {
+	n := l.Get(index)
+	if n == nil {
+		return false
+	}
+	l.unlink(n)
+	return true
+}
+
+// Pop removes the last node and returns it.
+// The root is never removed, so Pop returns nil when the list has less than two nodes.
+func (l *List[T]) Pop() *Node[T] {
+	if l.len < 2 {
+		return nil
+	}
+	n := l.tail
+	l.unlink(n)
+	return n
+}
+
+func (l *List[T]) unlink(n *Node[T]) {
+	if n.prev == nil {
+		l.root = n.next
+	} else {
+		n.prev.next = n.next
+	}
+	if n.next == nil {
+		l.tail = n.prev
+	} else {
+		n.next.prev = n.prev
+	}
+	n.next, n.prev = nil, nil
+	l.len--
+}
+
+// Empty reports whether the list has no nodes.
+func (l *List[T]) Empty() bool {
+	return l.root == nil
+}
+
+// Clear removes all the nodes.
+func (l *List[T]) Clear() {
+	l.root, l.tail, l.len = nil, nil, 0
+}
+
+// Print writes the index and value of each node to the standard output.
+func (l *List[T]) Print() {
+	l.fprint(os.Stdout)
+}
+
+func (l *List[T]) fprint(w io.Writer) {
+	if l.root == nil {
+		fmt.Fprintln(w, "<empty>")
+		return
+	}
+	i := 0
+	for n := l.root; n != nil; n = n.next {
+		fmt.Fprintln(w, i, ":", n.Value)
+		i++
+	}
+}
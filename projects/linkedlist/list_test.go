@@ -0,0 +1,167 @@
+package linkedlist
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func values[T comparable](l *List[T]) []T {
+	var res []T
+	for n := l.First(); n != nil; n = n.Next() {
+		res = append(res, n.Value)
+	}
+	return res
+}
+
+// checkList verifies the links, the tail and the cached length against a full walk.
+func checkList[T comparable](t *testing.T, l *List[T], want ...T) {
+	t.Helper()
+	if got := values(l); !slices.Equal(got, want) {
+		t.Fatalf("values = %v; expected %v", got, want)
+	}
+	if l.Len() != len(want) || l.Empty() != (len(want) == 0) {
+		t.Fatalf("Len = %d, Empty = %v; expected %d", l.Len(), l.Empty(), len(want))
+	}
+	var prev *Node[T]
+	for n := l.First(); n != nil; n = n.Next() {
+		if n.prev != prev {
+			t.Fatalf("broken prev link at %v", n.Value)
+		}
+		prev = n
+	}
+	if l.Last() != prev {
+		t.Fatalf("Last = %v; expected %v", l.Last(), prev)
+	}
+}
+
+func TestAppendPrepend(t *testing.T) {
+	l := NewList[string]()
+	checkList(t, l)
+	l.Append("A")
+	checkList(t, l, "A")
+	l.Append("B")
+	l.Prepend("C")
+	l.Prepend("D")
+	checkList(t, l, "D", "C", "A", "B")
+	if l.First().Value != "D" || l.Last().Value != "B" {
+		t.Errorf("First = %v, Last = %v", l.First().Value, l.Last().Value)
+	}
+	l = NewList[string]()
+	l.Prepend("A")
+	l.Append("B")
+	checkList(t, l, "A", "B")
+}
+
+func TestGetContains(t *testing.T) {
+	l := NewList[int]()
+	for i := range 5 {
+		l.Append(i * 10)
+	}
+	for i := range 5 {
+		if n := l.Get(i); n == nil || n.Value != i*10 {
+			t.Errorf("Get(%d) = %v", i, n)
+		}
+	}
+	for _, i := range []int{-1, 5, 100} {
+		if l.Get(i) != nil {
+			t.Errorf("Get(%d) must be nil", i)
+		}
+	}
+	if !l.Contains(40) || l.Contains(15) || NewList[int]().Contains(0) {
+		t.Errorf("Contains is wrong")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	l := NewList[int]()
+	for i := range 5 {
+		l.Append(i)
+	}
+	if l.Delete(-1) || l.Delete(5) {
+		t.Errorf("Delete out of range must return false")
+	}
+	l.Delete(2)
+	checkList(t, l, 0, 1, 3, 4)
+	l.Delete(0)
+	checkList(t, l, 1, 3, 4)
+	l.Delete(2)
+	checkList(t, l, 1, 3)
+	l.Append(5)
+	checkList(t, l, 1, 3, 5)
+	l.Delete(0)
+	l.Delete(0)
+	l.Delete(0)
+	checkList(t, l)
+	if l.Delete(0) {
+		t.Errorf("Delete on an empty list must return false")
+	}
+}
+
+func TestPop(t *testing.T) {
+	l := NewList[string]()
+	if l.Pop() != nil {
+		t.Errorf("Pop on an empty list must return nil")
+	}
+	l.Append("A")
+	if l.Pop() != nil {
+		t.Errorf("Pop on a single node list must return nil")
+	}
+	checkList(t, l, "A")
+	l.Append("B")
+	l.Append("C")
+	if n := l.Pop(); n == nil || n.Value != "C" || n.Next() != nil {
+		t.Errorf("Pop = %v; expected C", n)
+	}
+	checkList(t, l, "A", "B")
+	if n := l.Pop(); n == nil || n.Value != "B" {
+		t.Errorf("Pop = %v; expected B", n)
+	}
+	// The root is never removed.
+	if l.Pop() != nil {
+		t.Errorf("Pop must not remove the root")
+	}
+	checkList(t, l, "A")
+	l.Append("D")
+	checkList(t, l, "A", "D")
+}
+
+func TestClear(t *testing.T) {
+	l := NewList[int]()
+	l.Append(1)
+	l.Append(2)
+	l.Clear()
+	checkList(t, l)
+	l.Append(3)
+	checkList(t, l, 3)
+}
+
+func TestPrint(t *testing.T) {
+	var sb strings.Builder
+	l := NewList[string]()
+	l.fprint(&sb)
+	l.Append("A")
+	l.Append("B")
+	l.fprint(&sb)
+	if want := "<empty>\n0 : A\n1 : B\n"; sb.String() != want {
+		t.Errorf("Print = %q; expected %q", sb.String(), want)
+	}
+}
+
+func BenchmarkAppend(b *testing.B) {
+	for b.Loop() {
+		l := NewList[int]()
+		for i := range 1000 {
+			l.Append(i)
+		}
+	}
+}
+
+func BenchmarkPop(b *testing.B) {
+	l := NewList[int]()
+	l.Append(0)
+	for b.Loop() {
+		l.Append(1)
+		l.Pop()
+	}
+}
@@ -0,0 +1,150 @@
+package linkedlist
+
+import "iter"
+
+// PriorityQueue is a binary heap, where Pop returns the smallest value according to the cmp function.
+// Push returns an Item handle, so the value (and so its priority) can be updated, or removed, in O(log n).
+type PriorityQueue[T any] struct {
+	items []*Item[T]
+	cmp   func(a, b T) int
+}
+
+// Item is a value in a PriorityQueue.
+type Item[T any] struct {
+	Value T
+	index int // The position in the heap, or -1 when the item is not in the queue.
+}
+
+// NewPriorityQueue returns a queue ordered by the cmp function, like the functions of the "cmp" package.
+// For a max-heap, the arguments of cmp can be swapped.
+func NewPriorityQueue[T any](cmp func(a, b T) int) *PriorityQueue[T] {
+	return &PriorityQueue[T]{cmp: cmp}
+}
+
+// Len returns the number of values, in O(1).
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.items)
+}
+
+// Empty reports whether the queue has no values.
+func (pq *PriorityQueue[T]) Empty() bool {
+	return len(pq.items) == 0
+}
+
+// Push adds the value in O(log n), and returns its item, which can be used to update or remove the value.
+func (pq *PriorityQueue[T]) Push(value T) *Item[T] {
+	it := &Item[T]{Value: value, index: len(pq.items)}
+	pq.items = append(pq.items, it)
+	pq.up(it.index)
+	return it
+}
+
+// Peek returns the smallest value without removing it, or false if the queue is empty.
+func (pq *PriorityQueue[T]) Peek() (T, bool) {
+	if len(pq.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return pq.items[0].Value, true
+}
+
+// Pop removes and returns the smallest value, or false if the queue is empty.
+func (pq *PriorityQueue[T]) Pop() (T, bool) {
+	if len(pq.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	it := pq.items[0]
+	pq.Remove(it)
+	return it.Value, true
+}
+
+// Update sets the value of the item, and restores the heap order.
+// It reports whether the item belongs to the queue.
+func (pq *PriorityQueue[T]) Update(it *Item[T], value T) bool {
+	if !pq.owns(it) {
+		return false
+	}
+	it.Value = value
+	pq.fix(it.index)
+	return true
+}
+
+// Remove removes the item from the queue, and reports whether it belonged to the queue.
+func (pq *PriorityQueue[T]) Remove(it *Item[T]) bool {
+	if !pq.owns(it) {
+		return false
+	}
+	i, last := it.index, len(pq.items)-1
+	pq.swap(i, last)
+	pq.items[last] = nil
+	pq.items = pq.items[:last]
+	if i < last {
+		pq.fix(i)
+	}
+	it.index = -1
+	return true
+}
+
+func (pq *PriorityQueue[T]) owns(it *Item[T]) bool {
+	return it != nil && it.index >= 0 && it.index < len(pq.items) && pq.items[it.index] == it
+}
+
+// Values returns an iterator over the values in heap order, which is not sorted.
+// The smallest value is always the first one.
+func (pq *PriorityQueue[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, it := range pq.items {
+			if !yield(it.Value) {
+				return
+			}
+		}
+	}
+}
+
+func (pq *PriorityQueue[T]) less(i, j int) bool {
+	return pq.cmp(pq.items[i].Value, pq.items[j].Value) < 0
+}
+
+func (pq *PriorityQueue[T]) swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	pq.items[i].index = i
+	pq.items[j].index = j
+}
+
+func (pq *PriorityQueue[T]) fix(i int) {
+	if !pq.down(i) {
+		pq.up(i)
+	}
+}
+
+func (pq *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.less(i, parent) {
+			return
+		}
+		pq.swap(i, parent)
+		i = parent
+	}
+}
+
+// down moves the item at i down the heap, and reports whether it moved.
+func (pq *PriorityQueue[T]) down(i int) bool {
+	start := i
+	for {
+		child := 2*i + 1
+		if child >= len(pq.items) {
+			break
+		}
+		if right := child + 1; right < len(pq.items) && pq.less(right, child) {
+			child = right
+		}
+		if !pq.less(child, i) {
+			break
+		}
+		pq.swap(i, child)
+		i = child
+	}
+	return i > start
+}
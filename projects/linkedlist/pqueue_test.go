@@ -0,0 +1,97 @@
+package linkedlist
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+type task struct {
+	name     string
+	priority int
+}
+
+func byPriority(a, b task) int {
+	return cmp.Compare(a.priority, b.priority)
+}
+
+func TestPriorityQueue(t *testing.T) {
+	pq := NewPriorityQueue(byPriority)
+	write := pq.Push(task{"write", 3})
+	pq.Push(task{"read", 2})
+	test := pq.Push(task{"test", 5})
+	pq.Push(task{"deploy", 9})
+	if v, _ := pq.Peek(); v.name != "read" {
+		t.Errorf("Peek = %v", v)
+	}
+	pq.Update(test, task{"test", 1})
+	pq.Update(write, task{"write", 10})
+	var order []string
+	for !pq.Empty() {
+		v, _ := pq.Pop()
+		order = append(order, v.name)
+	}
+	if want := []string{"test", "read", "deploy", "write"}; !slices.Equal(order, want) {
+		t.Errorf("order = %v; expected %v", order, want)
+	}
+	if _, ok := pq.Pop(); ok {
+		t.Errorf("Pop on an empty queue must return false")
+	}
+	if pq.Update(test, task{}) || pq.Remove(test) || pq.Remove(nil) {
+		t.Errorf("popped items must not belong to the queue")
+	}
+	other := NewPriorityQueue(byPriority)
+	it := other.Push(task{"other", 1})
+	pq.Push(task{"mine", 1})
+	if pq.Remove(it) || pq.Update(it, task{}) {
+		t.Errorf("items of other queues must not be changed")
+	}
+}
+
+func TestPriorityQueueRandomOperations(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	pq := NewPriorityQueue(cmp.Compare[int])
+	var items []*Item[int]
+	for range 5000 {
+		switch r.IntN(4) {
+		case 0, 1:
+			items = append(items, pq.Push(r.IntN(1000)))
+		case 2:
+			if len(items) > 0 {
+				i := r.IntN(len(items))
+				pq.Update(items[i], r.IntN(1000))
+			}
+		case 3:
+			if len(items) > 0 {
+				i := r.IntN(len(items))
+				if !pq.Remove(items[i]) {
+					t.Fatalf("Remove must succeed")
+				}
+				items = slices.Delete(items, i, i+1)
+			}
+		}
+		if pq.Len() != len(items) {
+			t.Fatalf("Len = %d; expected %d", pq.Len(), len(items))
+		}
+	}
+	var want []int
+	for _, it := range items {
+		want = append(want, it.Value)
+	}
+	slices.Sort(want)
+	if first, _ := pq.Peek(); len(want) > 0 && first != want[0] {
+		t.Fatalf("Peek = %d; expected %d", first, want[0])
+	}
+	if got := Collect[int](pq); len(got) != len(want) {
+		t.Fatalf("Values has %d values; expected %d", len(got), len(want))
+	}
+	var got []int
+	for !pq.Empty() {
+		v, _ := pq.Pop()
+		got = append(got, v)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("pop order = %v; expected %v", got, want)
+	}
+}
@@ -0,0 +1,154 @@
+package linkedlist
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+)
+
+// ErrClosed is returned when pushing to a closed queue, or popping from a closed and drained queue.
+var ErrClosed = errors.New("linkedlist: queue closed")
+
+// Queue is a first-in, first-out queue, safe for concurrent use by producers and consumers.
+// It behaves like a channel: Push blocks while the queue is full, Pop blocks while it is empty,
+// and after Close, the remaining values can still be popped.
+// Unlike a channel, the capacity can be unbounded, and the queue can be inspected (Len and Values).
+type Queue[T any] struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	items    Deque[T]
+	capacity int
+	closed   bool
+}
+
+// NewQueue returns a queue with the capacity, or an unbounded queue if the capacity is not positive.
+func NewQueue[T any](capacity int) *Queue[T] {
+	q := &Queue[T]{capacity: capacity}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *Queue[T]) full() bool {
+	return q.capacity > 0 && q.items.Len() >= q.capacity
+}
+
+// wait waits for a signal, or for the context to be done.
+// The condition variable does not support contexts, so the context wakes up all the waiters when it is done.
+func (q *Queue[T]) wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		q.cond.Broadcast()
+	})
+	defer stop()
+	q.cond.Wait()
+	return ctx.Err()
+}
+
+// Push adds the value to the back of the queue, and blocks while the queue is full.
+// It returns ErrClosed if the queue is closed, or the error of the context if it is done first.
+func (q *Queue[T]) Push(ctx context.Context, value T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for !q.closed && q.full() {
+		if err := q.wait(ctx); err != nil {
+			return err
+		}
+	}
+	if q.closed {
+		return ErrClosed
+	}
+	q.items.PushBack(value)
+	q.cond.Broadcast()
+	return nil
+}
+
+// Pop removes and returns the value at the front of the queue, and blocks while the queue is empty.
+// It returns ErrClosed if the queue is closed and empty, or the error of the context if it is done first.
+func (q *Queue[T]) Pop(ctx context.Context) (T, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var zero T
+	for !q.closed && q.items.Empty() {
+		if err := q.wait(ctx); err != nil {
+			return zero, err
+		}
+	}
+	v, ok := q.items.PopFront()
+	if !ok {
+		return zero, ErrClosed
+	}
+	q.cond.Broadcast()
+	return v, nil
+}
+
+// TryPush adds the value without blocking, and reports whether it was added.
+func (q *Queue[T]) TryPush(value T) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed || q.full() {
+		return false
+	}
+	q.items.PushBack(value)
+	q.cond.Broadcast()
+	return true
+}
+
+// TryPop removes and returns the front value without blocking, or false if the queue is empty.
+func (q *Queue[T]) TryPop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	v, ok := q.items.PopFront()
+	if ok {
+		q.cond.Broadcast()
+	}
+	return v, ok
+}
+
+// Close closes the queue, and wakes up the blocked producers and consumers.
+// Closing a closed queue has no effect.
+func (q *Queue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.cond.Broadcast()
+}
+
+// Len returns the number of values waiting in the queue.
+func (q *Queue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.Len()
+}
+
+// Values returns an iterator over a snapshot of the values, from the front to the back.
+// The lock is not held while iterating, so the queue can be used in the loop.
+func (q *Queue[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		q.mu.Lock()
+		values := Collect[T](&q.items)
+		q.mu.Unlock()
+		for _, v := range values {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Drain returns an iterator that pops the values until the queue is closed and empty, or the context is done.
+// It is the equivalent of ranging over a channel.
+func (q *Queue[T]) Drain(ctx context.Context) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			v, err := q.Pop(ctx)
+			if err != nil || !yield(v) {
+				return
+			}
+		}
+	}
+}
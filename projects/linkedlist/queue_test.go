@@ -0,0 +1,119 @@
+package linkedlist
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueueProducersConsumers(t *testing.T) {
+	const producers, perProducer = 4, 500
+	q := NewQueue[int](8)
+	var wg sync.WaitGroup
+	for p := range producers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perProducer {
+				if err := q.Push(context.Background(), p*perProducer+i); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	results := make(chan []int)
+	for range 3 {
+		go func() {
+			var got []int
+			for v := range q.Drain(context.Background()) {
+				got = append(got, v)
+			}
+			results <- got
+		}()
+	}
+	wg.Wait()
+	q.Close()
+	var all []int
+	for range 3 {
+		all = append(all, <-results...)
+	}
+	slices.Sort(all)
+	if len(all) != producers*perProducer || all[0] != 0 || all[len(all)-1] != producers*perProducer-1 {
+		t.Fatalf("consumed %d values", len(all))
+	}
+	for i := range all {
+		if all[i] != i {
+			t.Fatalf("value %d is missing or duplicated", i)
+		}
+	}
+}
+
+func TestQueueOrderAndClose(t *testing.T) {
+	q := NewQueue[string](0)
+	for _, v := range []string{"A", "B", "C"} {
+		if !q.TryPush(v) {
+			t.Fatalf("TryPush on an unbounded queue must succeed")
+		}
+	}
+	if got := Collect[string](q); !slices.Equal(got, []string{"A", "B", "C"}) || q.Len() != 3 {
+		t.Errorf("values = %v", got)
+	}
+	q.Close()
+	q.Close()
+	if err := q.Push(context.Background(), "D"); !errors.Is(err, ErrClosed) || q.TryPush("D") {
+		t.Errorf("Push on a closed queue = %v", err)
+	}
+	// The remaining values can be popped after Close.
+	if v, err := q.Pop(context.Background()); v != "A" || err != nil {
+		t.Errorf("Pop = %q, %v", v, err)
+	}
+	if v, ok := q.TryPop(); v != "B" || !ok {
+		t.Errorf("TryPop = %q, %v", v, ok)
+	}
+	q.Pop(context.Background())
+	if _, err := q.Pop(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("Pop on a closed and empty queue = %v", err)
+	}
+}
+
+func TestQueueBlocking(t *testing.T) {
+	q := NewQueue[int](1)
+	q.Push(context.Background(), 1)
+	if q.TryPush(2) {
+		t.Fatalf("TryPush on a full queue must fail")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := q.Push(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Push on a full queue = %v; expected the context error", err)
+	}
+
+	pushed := make(chan error)
+	go func() { pushed <- q.Push(context.Background(), 2) }()
+	select {
+	case err := <-pushed:
+		t.Fatalf("Push must block while the queue is full, got %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	if v, _ := q.TryPop(); v != 1 {
+		t.Errorf("TryPop = %d", v)
+	}
+	if err := <-pushed; err != nil {
+		t.Errorf("Push = %v", err)
+	}
+
+	q.TryPop()
+	popped := make(chan error)
+	go func() {
+		_, err := q.Pop(context.Background())
+		popped <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	q.Close()
+	if err := <-popped; !errors.Is(err, ErrClosed) {
+		t.Errorf("Close must wake up blocked consumers, got %v", err)
+	}
+}
@@ -0,0 +1,3 @@
+module wordle
+
+go 1.24.1
@@ -1,15 +1,35 @@
 package main
 
 import (
+	_ "embed"
+	"errors"
 	"fmt"
 	"math/rand"
+	"slices"
 	"strings"
+
+	"wordle/trie"
+)
+
+// The word list is embedded in the binary, and loaded into a trie on start.
+// The guesses are looked up in the trie, and the secret words are picked from the same list.
+//
+//go:embed words.txt
+var wordList string
+
+var (
+	dictionary = mustLoadWords(wordList)
+	words      = slices.Collect(dictionary.Keys())
 )
 
-var words = []string{
-	"PLACES", "MARKET", "SUMMER", "OBJECT", "FUTURE", "BUTTON", "NUMBER",
-	"PLAYER", "WINTER", "POCKET", "JACKET", "SCHOOL", "WINDOW", "SCREEN",
-	"FRIEND",
+var ErrUnknownWord = errors.New("not in the word list")
+
+func mustLoadWords(list string) *trie.Trie[struct{}] {
+	t, err := trie.LoadWords(strings.NewReader(list), strings.ToUpper)
+	if err != nil {
+		panic(err)
+	}
+	return t
 }
 
 const (
@@ -46,6 +66,9 @@ func (w *Wordle) Guess(guess string) (bool, string, error) {
 		return false, "", fmt.Errorf("Word must have exactly %d characters", WordLen)
 	}
 	guess = strings.ToUpper(guess)
+	if !dictionary.Contains(guess) {
+		return false, "", fmt.Errorf("%w: %s", ErrUnknownWord, guess)
+	}
 	res := ""
 	for i := range WordLen {
 		wChar := w.word[i]
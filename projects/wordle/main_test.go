@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDictionary(t *testing.T) {
+	if len(words) < 500 {
+		t.Errorf("the word list has %d words", len(words))
+	}
+	for _, w := range words {
+		if len(w) != WordLen {
+			t.Errorf("%q must have %d letters", w, WordLen)
+		}
+	}
+}
+
+func TestGuess(t *testing.T) {
+	w := NewWordle()
+	w.word = "MARKET"
+	cases := []struct {
+		guess, result string
+		won           bool
+	}{
+		{"market", "OOOOOO", true},
+		{"MASTER", "OO-XOX", false},
+		{"BUTTON", "--XX--", false},
+	}
+	for _, c := range cases {
+		won, res, err := w.Guess(c.guess)
+		if err != nil || won != c.won || res != c.result {
+			t.Errorf("Guess(%q) = %v, %q, %v; expected %v, %q", c.guess, won, res, err, c.won, c.result)
+		}
+	}
+	if _, _, err := w.Guess("QWERTY"); !errors.Is(err, ErrUnknownWord) {
+		t.Errorf("Guess of a word that is not in the list = %v", err)
+	}
+	if _, _, err := w.Guess("CAT"); err == nil || errors.Is(err, ErrUnknownWord) {
+		t.Errorf("Guess of a short word = %v", err)
+	}
+}
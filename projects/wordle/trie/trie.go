@@ -0,0 +1,278 @@
+// Package trie implements a generic rune trie (prefix tree).
+//
+// Trie
+// A trie stores strings by their characters: each node is a character, and each path from the root is a prefix.
+// Finding a key takes O(len(key)), whatever the number of keys, and all the keys with the same prefix are in the
+// same subtree, so the prefix search and the autocomplete only visit the matching keys.
+// The children of each node are kept in a slice sorted by rune, and found with a binary search. This uses much
+// less memory than a map per node, and the keys are visited in sorted order.
+// Example:
+//
+//	(root)
+//	  └─ C
+//	     ├─ A ─ T (CAT)
+//	     └─ O ─ W (COW)
+package trie
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Wildcard matches any single rune in the patterns of Match.
+const Wildcard = '?'
+
+type node[V any] struct {
+	runes    []rune
+	children []*node[V]
+	value    V
+	terminal bool
+}
+
+// child returns the child of the rune r, or nil.
+func (n *node[V]) child(r rune) *node[V] {
+	i, ok := slices.BinarySearch(n.runes, r)
+	if !ok {
+		return nil
+	}
+	return n.children[i]
+}
+
+// Trie maps strings to values of type V. The zero value is an empty trie ready to use.
+type Trie[V any] struct {
+	root node[V]
+	len  int
+}
+
+func New[V any]() *Trie[V] {
+	return &Trie[V]{}
+}
+
+// Insert
+// The method below adds the key with its value, or replaces the value if the key exists.
+// It reports whether the key is new.
+func (t *Trie[V]) Insert(key string, value V) bool {
+	n := &t.root
+	for _, r := range key {
+		i, ok := slices.BinarySearch(n.runes, r)
+		if !ok {
+			n.runes = slices.Insert(n.runes, i, r)
+			n.children = slices.Insert(n.children, i, &node[V]{})
+		}
+		n = n.children[i]
+	}
+	n.value = value
+	if n.terminal {
+		return false
+	}
+	n.terminal = true
+	t.len++
+	return true
+}
+
+// find returns the node of the prefix, or nil.
+func (t *Trie[V]) find(prefix string) *node[V] {
+	n := &t.root
+	for _, r := range prefix {
+		if n = n.child(r); n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+func (t *Trie[V]) Get(key string) (V, bool) {
+	n := t.find(key)
+	if n == nil || !n.terminal {
+		var zero V
+		return zero, false
+	}
+	return n.value, true
+}
+
+func (t *Trie[V]) Contains(key string) bool {
+	_, ok := t.Get(key)
+	return ok
+}
+
+// HasPrefix reports whether any key starts with the prefix.
+func (t *Trie[V]) HasPrefix(prefix string) bool {
+	return t.find(prefix) != nil
+}
+
+func (t *Trie[V]) Len() int {
+	return t.len
+}
+
+// Delete
+// The method below removes the key, and the nodes that are no longer on the path of another key.
+// It reports whether the key existed.
+func (t *Trie[V]) Delete(key string) bool {
+	// The path keeps each node with the index of the child taken, to remove the empty nodes bottom-up.
+	type step struct {
+		n *node[V]
+		i int
+	}
+	var path []step
+	n := &t.root
+	for _, r := range key {
+		i, ok := slices.BinarySearch(n.runes, r)
+		if !ok {
+			return false
+		}
+		path = append(path, step{n, i})
+		n = n.children[i]
+	}
+	if !n.terminal {
+		return false
+	}
+	var zero V
+	n.value, n.terminal = zero, false
+	t.len--
+	for j := len(path) - 1; j >= 0 && len(n.runes) == 0 && !n.terminal; j-- {
+		p := path[j]
+		p.n.runes = slices.Delete(p.n.runes, p.i, p.i+1)
+		p.n.children = slices.Delete(p.n.children, p.i, p.i+1)
+		n = p.n
+	}
+	return true
+}
+
+// Prefix Search
+// The method below returns the keys starting with the prefix, with their values, in sorted order.
+// Only the subtree of the prefix is visited, so this is the autocomplete of the prefix.
+func (t *Trie[V]) WithPrefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		n := t.find(prefix)
+		if n == nil {
+			return
+		}
+		buf := []rune(prefix)
+		n.walk(&buf, yield)
+	}
+}
+
+// All returns all the keys with their values, in sorted order.
+func (t *Trie[V]) All() iter.Seq2[string, V] {
+	return t.WithPrefix("")
+}
+
+// Keys returns all the keys in sorted order.
+func (t *Trie[V]) Keys() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for k := range t.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// walk yields the keys of the subtree in preorder, where buf holds the runes of the path to n.
+// It returns false when the iteration was stopped.
+func (n *node[V]) walk(buf *[]rune, yield func(string, V) bool) bool {
+	if n.terminal && !yield(string(*buf), n.value) {
+		return false
+	}
+	for i, r := range n.runes {
+		*buf = append(*buf, r)
+		ok := n.children[i].walk(buf, yield)
+		*buf = (*buf)[:len(*buf)-1]
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Pattern Matching
+// The method below returns the keys matching the pattern, in sorted order, where the Wildcard matches any single
+// rune (e.g., "C?T" matches "CAT" and "COT", but not "CART").
+// At a wildcard, all the children are visited; at other runes, only one child, so the search skips the subtrees
+// that cannot match. Word games use it to find the words with some known letters.
+func (t *Trie[V]) Match(pattern string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		var buf []rune
+		t.root.match(pattern, &buf, yield)
+	}
+}
+
+func (n *node[V]) match(pattern string, buf *[]rune, yield func(string, V) bool) bool {
+	if pattern == "" {
+		return !n.terminal || yield(string(*buf), n.value)
+	}
+	r, size := utf8.DecodeRuneInString(pattern)
+	rest := pattern[size:]
+	if r != Wildcard {
+		c := n.child(r)
+		if c == nil {
+			return true
+		}
+		*buf = append(*buf, r)
+		ok := c.match(rest, buf, yield)
+		*buf = (*buf)[:len(*buf)-1]
+		return ok
+	}
+	for i, r := range n.runes {
+		*buf = append(*buf, r)
+		ok := n.children[i].match(rest, buf, yield)
+		*buf = (*buf)[:len(*buf)-1]
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Longest Prefix
+// The method below returns the longest key that is a prefix of s, with its value (e.g., to split a text into
+// the words of a dictionary, or to match the routes of a URL).
+func (t *Trie[V]) LongestPrefix(s string) (string, V, bool) {
+	var (
+		value V
+		end   = -1
+	)
+	n := &t.root
+	if n.terminal {
+		value, end = n.value, 0
+	}
+	for i, r := range s {
+		if n = n.child(r); n == nil {
+			break
+		}
+		if n.terminal {
+			value, end = n.value, i+utf8.RuneLen(r)
+		}
+	}
+	if end < 0 {
+		return "", value, false
+	}
+	return s[:end], value, true
+}
+
+// Loading Word Lists
+// The function below reads a word list with one word per line, and returns a trie of the words.
+// The spaces around the words are trimmed, and the empty lines and the lines starting with '#' are skipped.
+// The transform function (e.g., strings.ToUpper) normalizes the words, and can be nil.
+func LoadWords(r io.Reader, transform func(string) string) (*Trie[struct{}], error) {
+	t := New[struct{}]()
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		w := strings.TrimSpace(s.Text())
+		if w == "" || strings.HasPrefix(w, "#") {
+			continue
+		}
+		if transform != nil {
+			w = transform(w)
+		}
+		t.Insert(w, struct{}{})
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
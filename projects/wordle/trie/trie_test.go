@@ -0,0 +1,263 @@
+package trie
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func keys[V any](t *Trie[V], prefix string) []string {
+	var out []string
+	for k := range t.WithPrefix(prefix) {
+		out = append(out, k)
+	}
+	return out
+}
+
+func TestInsertGet(t *testing.T) {
+	tr := New[int]()
+	for i, k := range []string{"car", "cart", "cat", "dog", "", "café"} {
+		if !tr.Insert(k, i) {
+			t.Errorf("Insert(%q) must report a new key", k)
+		}
+	}
+	if tr.Insert("cat", 10) || tr.Len() != 6 {
+		t.Errorf("Insert of an existing key must replace the value, Len = %d", tr.Len())
+	}
+	for k, want := range map[string]int{"car": 0, "cart": 1, "cat": 10, "": 4, "café": 5} {
+		if v, ok := tr.Get(k); !ok || v != want {
+			t.Errorf("Get(%q) = %v, %v; expected %v", k, v, ok, want)
+		}
+	}
+	for _, k := range []string{"ca", "carts", "d", "caf"} {
+		if tr.Contains(k) {
+			t.Errorf("Contains(%q) must be false", k)
+		}
+	}
+	if !tr.HasPrefix("ca") || !tr.HasPrefix("caf") || tr.HasPrefix("cb") {
+		t.Errorf("HasPrefix is wrong")
+	}
+	var zero Trie[string]
+	if zero.Contains("a") || zero.Len() != 0 || !zero.Insert("a", "x") || !zero.Contains("a") {
+		t.Errorf("the zero value must be an empty trie")
+	}
+}
+
+func TestWithPrefix(t *testing.T) {
+	tr := New[struct{}]()
+	for _, k := range []string{"tea", "ten", "to", "inn", "in", "tenth", "té"} {
+		tr.Insert(k, struct{}{})
+	}
+	if got := keys(tr, "te"); !slices.Equal(got, []string{"tea", "ten", "tenth"}) {
+		t.Errorf("WithPrefix(te) = %v", got)
+	}
+	if got := slices.Collect(tr.Keys()); !slices.Equal(got, []string{"in", "inn", "tea", "ten", "tenth", "to", "té"}) {
+		t.Errorf("Keys = %v; the keys must be sorted by rune", got)
+	}
+	if got := keys(tr, "x"); got != nil {
+		t.Errorf("WithPrefix(x) = %v", got)
+	}
+	for k := range tr.WithPrefix("t") {
+		if k == "ten" {
+			break
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tr := New[int]()
+	for i, k := range []string{"CAT", "COT", "CART", "CUT", "COAT", "BAT", "CA"} {
+		tr.Insert(k, i)
+	}
+	match := func(p string) []string {
+		return slices.Collect(func(yield func(string) bool) {
+			for k := range tr.Match(p) {
+				if !yield(k) {
+					return
+				}
+			}
+		})
+	}
+	cases := map[string][]string{
+		"C?T":  {"CAT", "COT", "CUT"},
+		"?AT":  {"BAT", "CAT"},
+		"????": {"CART", "COAT"},
+		"CAT":  {"CAT"},
+		"C?":   {"CA"},
+		"X??":  nil,
+		"":     nil,
+	}
+	for p, want := range cases {
+		if got := match(p); !slices.Equal(got, want) {
+			t.Errorf("Match(%q) = %v; expected %v", p, got, want)
+		}
+	}
+	for k, v := range tr.Match("???") {
+		if k != "BAT" || v != 5 {
+			t.Errorf("Match must yield the values, got %q %v", k, v)
+		}
+		break
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tr := New[int]()
+	for i, k := range []string{"a", "ab", "abc", "abd", "b"} {
+		tr.Insert(k, i)
+	}
+	if tr.Delete("x") || tr.Delete("abcd") || tr.Delete("") {
+		t.Errorf("Delete of a missing key must return false")
+	}
+	if !tr.Delete("abc") || tr.Contains("abc") || !tr.Contains("abd") || !tr.Contains("ab") {
+		t.Errorf("Delete(abc) is wrong: %v", keys(tr, ""))
+	}
+	if !tr.Delete("abd") || !tr.Delete("ab") || tr.HasPrefix("ab") {
+		t.Errorf("the empty nodes must be removed")
+	}
+	if !tr.Contains("a") || tr.Len() != 2 {
+		t.Errorf("Len = %d, keys = %v", tr.Len(), keys(tr, ""))
+	}
+}
+
+func TestLongestPrefix(t *testing.T) {
+	tr := New[string]()
+	tr.Insert("/api", "api")
+	tr.Insert("/api/users", "users")
+	tr.Insert("/é", "accent")
+	cases := []struct {
+		s, key, value string
+		ok            bool
+	}{
+		{"/api/users/42", "/api/users", "users", true},
+		{"/api/user", "/api", "api", true},
+		{"/éx", "/é", "accent", true},
+		{"/ap", "", "", false},
+	}
+	for _, c := range cases {
+		if k, v, ok := tr.LongestPrefix(c.s); k != c.key || v != c.value || ok != c.ok {
+			t.Errorf("LongestPrefix(%q) = %q, %q, %v", c.s, k, v, ok)
+		}
+	}
+}
+
+func TestLoadWords(t *testing.T) {
+	list := "# comment\napple\n\n  Banana  \ncherry\napple\n"
+	tr, err := LoadWords(strings.NewReader(list), strings.ToUpper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := slices.Collect(tr.Keys()); !slices.Equal(got, []string{"APPLE", "BANANA", "CHERRY"}) {
+		t.Errorf("LoadWords = %v", got)
+	}
+}
+
+// TestModel compares the trie with a map after random operations.
+func TestModel(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	tr := New[int]()
+	model := map[string]int{}
+	for i := range 5000 {
+		k := randomWord(r, 1+r.IntN(4), "abc")
+		switch r.IntN(3) {
+		case 0, 1:
+			_, exists := model[k]
+			if tr.Insert(k, i) == exists {
+				t.Fatalf("Insert(%q) reported a wrong result", k)
+			}
+			model[k] = i
+		case 2:
+			_, exists := model[k]
+			if tr.Delete(k) != exists {
+				t.Fatalf("Delete(%q) reported a wrong result", k)
+			}
+			delete(model, k)
+		}
+	}
+	want := slices.Sorted(maps.Keys(model))
+	if got := keys(tr, ""); !slices.Equal(got, want) || tr.Len() != len(model) {
+		t.Fatalf("keys = %v; expected %v", got, want)
+	}
+	for k, v := range tr.All() {
+		if model[k] != v {
+			t.Fatalf("value of %q = %v; expected %v", k, v, model[k])
+		}
+	}
+}
+
+func randomWord(r *rand.Rand, n int, letters string) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[r.IntN(len(letters))]
+	}
+	return string(b)
+}
+
+// words returns n distinct random words of 4 to 10 letters.
+func words(n int) []string {
+	r := rand.New(rand.NewPCG(1, 2))
+	seen := make(map[string]struct{}, n)
+	for len(seen) < n {
+		seen[randomWord(r, 4+r.IntN(7), "ABCDEFGHIJKLMNOPQRSTUVWXYZ")] = struct{}{}
+	}
+	out := slices.Collect(maps.Keys(seen))
+	sort.Strings(out)
+	return out
+}
+
+// The benchmarks below look up the words of a 100k-word list, in the trie and in a map.
+// The map is faster for exact lookups (a hash and a comparison), but the trie also answers the prefix and
+// pattern queries without scanning all the words.
+func BenchmarkLookup(b *testing.B) {
+	list := words(100_000)
+	tr := New[struct{}]()
+	set := make(map[string]struct{}, len(list))
+	for _, w := range list {
+		tr.Insert(w, struct{}{})
+		set[w] = struct{}{}
+	}
+	b.Run("Trie", func(b *testing.B) {
+		i := 0
+		for b.Loop() {
+			tr.Contains(list[i%len(list)])
+			i++
+		}
+	})
+	b.Run("Map", func(b *testing.B) {
+		i := 0
+		for b.Loop() {
+			_ = set[list[i%len(list)]]
+			i++
+		}
+	})
+	b.Run("Prefix", func(b *testing.B) {
+		i := 0
+		for b.Loop() {
+			for range tr.WithPrefix(list[i%len(list)][:3]) {
+			}
+			i++
+		}
+	})
+	b.Run("Match", func(b *testing.B) {
+		for b.Loop() {
+			for range tr.Match("A??E??") {
+			}
+		}
+	})
+}
+
+func ExampleTrie_Match() {
+	tr := New[struct{}]()
+	for _, w := range []string{"CAT", "COT", "CART", "DOG"} {
+		tr.Insert(w, struct{}{})
+	}
+	for w := range tr.Match("C?T") {
+		fmt.Println(w)
+	}
+	// Output:
+	// CAT
+	// COT
+}
@@ -0,0 +1,417 @@
+// Buildergen
+// Buildergen is a tool to automate the creation of builders (see the Builder pattern) for structs.
+// Given the name of a struct type T, buildergen creates a new self-contained Go source file with a builder for T.
+// The builder has a fluent "With<Field>" method for each exported field, and a "Build() (*T, error)" method.
+// The file is created in the same package and directory as the package that defines T.
+// It has helpful defaults designed for use with go generate, like the stringer tool.
+//
+// The fields are configured with the "builder" struct tag:
+//   - builder:"required" reports an error from Build when the field was not set.
+//   - builder:"default=<value>" initializes the field with the value (strings are quoted automatically).
+//   - builder:"-" skips the field.
+//
+// When T has a "Validate() error" method, Build calls it after checking the required fields.
+// All the errors are reported together, using errors.Join.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Setup
+// The tool can be installed using the command below, and used with the //go:generate directive.
+var _ = `
+  go install ./buildergen
+  //go:generate buildergen -type=Person
+`
+
+// Flags
+// The flags below configure the generated builder.
+var (
+	typeName    = flag.String("type", "", "struct type name; must be set")
+	builderName = flag.String("name", "", "builder type name; default <type>Builder")
+	output      = flag.String("output", "", "output file name; default srcdir/<type>_builder.go")
+)
+
+// Usage
+// The function below replaces the default usage message.
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of buildergen:\n")
+	fmt.Fprintf(os.Stderr, "\tbuildergen [flags] -type T [directory]\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("buildergen: ")
+	flag.Usage = usage
+	flag.Parse()
+	if *typeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+	name := *builderName
+	if name == "" {
+		name = *typeName + "Builder"
+	}
+	out := *output
+	if out == "" {
+		out = filepath.Join(dir, strings.ToLower(*typeName)+"_builder.go")
+	}
+	src, err := Generate(dir, *typeName, name, filepath.Base(out), "buildergen "+strings.Join(os.Args[1:], " "))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Field
+// The struct below describes a field of the struct, as read from the source code.
+type Field struct {
+	Name     string
+	Param    string
+	Type     string
+	Required bool
+	Default  string // Go expression, empty when there is no default value
+}
+
+// Model
+// The struct below describes the struct type that will have a builder.
+type Model struct {
+	Package  string
+	Type     string
+	Builder  string
+	Fields   []Field
+	Imports  []string // Import specs used by the field types
+	Validate bool
+}
+
+// Generate
+// The function below parses the package in the directory, and returns the formatted source code of the builder.
+// The skip file (usually the output file) is not parsed, so an outdated builder does not affect the new one.
+func Generate(dir, typ, builder, skip, command string) ([]byte, error) {
+	m, err := Load(dir, typ, skip)
+	if err != nil {
+		return nil, err
+	}
+	m.Builder = builder
+	return Render(m, command)
+}
+
+// Load
+// The function below parses the non-test Go files in the directory, and finds the struct type.
+func Load(dir, typ, skip string) (*Model, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == skip {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+
+	m := &Model{Package: files[0].Name.Name, Type: typ}
+	var found *ast.File
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					ts, ok := spec.(*ast.TypeSpec)
+					if !ok || ts.Name.Name != typ {
+						continue
+					}
+					st, ok := ts.Type.(*ast.StructType)
+					if !ok {
+						return nil, fmt.Errorf("%s is not a struct type", typ)
+					}
+					if ts.TypeParams != nil {
+						return nil, fmt.Errorf("%s: generic types are not supported", typ)
+					}
+					if m.Fields, err = fields(fset, st); err != nil {
+						return nil, fmt.Errorf("%s.%w", typ, err)
+					}
+					found = f
+				}
+			case *ast.FuncDecl:
+				if isValidate(d, typ) {
+					m.Validate = true
+				}
+			}
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("type %s not found in %s", typ, dir)
+	}
+	m.Imports = imports(found, m.Fields)
+	return m, nil
+}
+
+// Fields
+// The function below reads the exported fields of the struct, and their tags.
+func fields(fset *token.FileSet, st *ast.StructType) ([]Field, error) {
+	var res []Field
+	for _, f := range st.Fields.List {
+		var tag string
+		if f.Tag != nil {
+			raw, _ := strconv.Unquote(f.Tag.Value)
+			tag = reflect.StructTag(raw).Get("builder")
+		}
+		if tag == "-" || len(f.Names) == 0 { // Embedded fields are skipped
+			continue
+		}
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, f.Type); err != nil {
+			return nil, err
+		}
+		for _, n := range f.Names {
+			if !n.IsExported() {
+				continue
+			}
+			field := Field{Name: n.Name, Param: param(n.Name), Type: buf.String()}
+			for opt := range strings.SplitSeq(tag, ",") {
+				switch key, value, _ := strings.Cut(strings.TrimSpace(opt), "="); key {
+				case "":
+				case "required":
+					field.Required = true
+				case "default":
+					def, err := defaultValue(field.Type, value)
+					if err != nil {
+						return nil, fmt.Errorf("%s: %w", n.Name, err)
+					}
+					field.Default = def
+				default:
+					return nil, fmt.Errorf("%s: unknown builder option %q", n.Name, key)
+				}
+			}
+			if field.Required && field.Default != "" {
+				return nil, fmt.Errorf("%s: required fields cannot have a default value", n.Name)
+			}
+			res = append(res, field)
+		}
+	}
+	return res, nil
+}
+
+// Default Value
+// The function below converts the default value of the tag into a Go expression.
+// Strings are quoted, and the other values must be valid Go expressions (e.g., 8080, true, 5 * time.Second).
+func defaultValue(typ, value string) (string, error) {
+	if value == "" {
+		return "", errors.New("empty default value")
+	}
+	if typ == "string" {
+		return strconv.Quote(value), nil
+	}
+	if _, err := parser.ParseExpr(value); err != nil {
+		return "", fmt.Errorf("invalid default value %q", value)
+	}
+	return value, nil
+}
+
+// Param
+// The function below returns the parameter name for the field (e.g., ID -> id, HTTPPort -> httpPort).
+func param(field string) string {
+	r := []rune(field)
+	n := 0
+	for n < len(r) && unicode.IsUpper(r[n]) {
+		n++
+	}
+	if n > 1 && n < len(r) {
+		n-- // Keep the first letter of the next word (HTTPPort -> httpPort)
+	}
+	for i := range n {
+		r[i] = unicode.ToLower(r[i])
+	}
+	name := string(r)
+	if token.IsKeyword(name) || name == "b" || name == "errs" {
+		name += "Value"
+	}
+	return name
+}
+
+// Validate Check
+// The function below reports whether the function declaration is a "Validate() error" method of the type.
+func isValidate(d *ast.FuncDecl, typ string) bool {
+	if d.Recv == nil || len(d.Recv.List) != 1 || d.Name.Name != "Validate" {
+		return false
+	}
+	recv := d.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	if id, ok := recv.(*ast.Ident); !ok || id.Name != typ {
+		return false
+	}
+	res := d.Type.Results
+	if d.Type.Params.NumFields() != 0 || res.NumFields() != 1 {
+		return false
+	}
+	id, ok := res.List[0].Type.(*ast.Ident)
+	return ok && id.Name == "error"
+}
+
+// Imports
+// The function below returns the imports of the file that are used by the field types or default values.
+func imports(f *ast.File, fields []Field) []string {
+	used := map[string]bool{}
+	for _, field := range fields {
+		for _, expr := range []string{field.Type, field.Default} {
+			e, err := parser.ParseExpr(expr)
+			if err != nil {
+				continue
+			}
+			ast.Inspect(e, func(n ast.Node) bool {
+				if sel, ok := n.(*ast.SelectorExpr); ok {
+					if id, ok := sel.X.(*ast.Ident); ok {
+						used[id.Name] = true
+					}
+				}
+				return true
+			})
+		}
+	}
+	var res []string
+	for _, spec := range f.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if !used[name] {
+			continue
+		}
+		if spec.Name != nil {
+			res = append(res, spec.Name.Name+" "+spec.Path.Value)
+		} else {
+			res = append(res, spec.Path.Value)
+		}
+	}
+	return res
+}
+
+// Render
+// The function below writes the builder source code, and formats it with gofmt.
+func Render(m *Model, command string) ([]byte, error) {
+	var b bytes.Buffer
+	p := func(format string, args ...any) { fmt.Fprintf(&b, format+"\n", args...) }
+
+	p("// Code generated by %q; DO NOT EDIT.", command)
+	p("")
+	p("package %s", m.Package)
+	p("")
+	imports := slices.Sorted(slices.Values(append([]string{`"errors"`}, m.Imports...)))
+	if len(imports) == 1 {
+		p("import %s", imports[0])
+	} else {
+		p("import (")
+		for _, imp := range imports {
+			p("\t%s", imp)
+		}
+		p(")")
+	}
+	p("")
+
+	required := slices.ContainsFunc(m.Fields, func(f Field) bool { return f.Required })
+	p("// %s builds %s values.", m.Builder, m.Type)
+	p("// Each With method sets a field and returns the builder, so the calls can be chained.")
+	p("type %s struct {", m.Builder)
+	p("\tvalue %s", m.Type)
+	if required {
+		p("\tset   map[string]bool")
+	}
+	p("}")
+	p("")
+
+	p("// New%s returns a builder with the default values.", m.Builder)
+	p("func New%s() *%s {", m.Builder, m.Builder)
+	if required {
+		p("\tb := &%s{set: map[string]bool{}}", m.Builder)
+	} else {
+		p("\tb := &%s{}", m.Builder)
+	}
+	for _, f := range m.Fields {
+		if f.Default != "" {
+			p("\tb.value.%s = %s", f.Name, f.Default)
+		}
+	}
+	p("\treturn b")
+	p("}")
+	p("")
+
+	for _, f := range m.Fields {
+		p("// With%s sets the %s field.", f.Name, f.Name)
+		p("func (b *%s) With%s(%s %s) *%s {", m.Builder, f.Name, f.Param, f.Type, m.Builder)
+		p("\tb.value.%s = %s", f.Name, f.Param)
+		if f.Required {
+			p("\tb.set[%q] = true", f.Name)
+		}
+		p("\treturn b")
+		p("}")
+		p("")
+	}
+
+	p("// Build returns a copy of the built %s.", m.Type)
+	p("// It reports all the missing required fields and validation errors together.")
+	p("func (b *%s) Build() (*%s, error) {", m.Builder, m.Type)
+	p("\tvar errs []error")
+	for _, f := range m.Fields {
+		if f.Required {
+			p("\tif !b.set[%q] {", f.Name)
+			p("\t\terrs = append(errs, errors.New(%q))", m.Type+"."+f.Name+" is required")
+			p("\t}")
+		}
+	}
+	p("\tv := b.value")
+	if m.Validate {
+		p("\tif err := v.Validate(); err != nil {")
+		p("\t\terrs = append(errs, err)")
+		p("\t}")
+	}
+	p("\tif err := errors.Join(errs...); err != nil {")
+	p("\t\treturn nil, err")
+	p("\t}")
+	p("\treturn &v, nil")
+	p("}")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid generated code: %w\n%s", err, b.Bytes())
+	}
+	return src, nil
+}
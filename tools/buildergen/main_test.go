@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tools/internal/golden"
+)
+
+func TestGenerateGolden(t *testing.T) {
+	got, err := Generate("testdata/order", "Order", "OrderBuilder", "order_builder.go", "buildergen -type=Order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden.Assert(t, "order_builder", got)
+}
+
+// The generated builders in the guide module must be up to date with their structs.
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	for _, tc := range []struct{ dir, typ, builder, file, command string }{
+		{"../../guide/gof/creational", "Person", "GeneratedPersonBuilder", "person_builder.go", "buildergen -type=Person -name=GeneratedPersonBuilder"},
+		{"../../guide/patterns", "Server", "ServerBuilder", "server_builder.go", "buildergen -type=Server"},
+	} {
+		want, err := os.ReadFile(filepath.Join(tc.dir, tc.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Generate(tc.dir, tc.typ, tc.builder, tc.file, tc.command)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is outdated; run go generate in %s", tc.file, tc.dir)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	for _, tc := range []struct{ src, want string }{
+		{"type T int", "T is not a struct type"},
+		{"type U struct{}", "type T not found"},
+		{"type T struct { A int `builder:\"optional\"` }", `T.A: unknown builder option "optional"`},
+		{"type T struct { A int `builder:\"required,default=1\"` }", "T.A: required fields cannot have a default value"},
+		{"type T struct { A int `builder:\"default=1+\"` }", `T.A: invalid default value "1+"`},
+		{"type T[X any] struct { A X }", "generic types are not supported"},
+	} {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "t.go"), []byte("package p\n"+tc.src+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Generate(dir, "T", "TBuilder", "t_builder.go", "buildergen -type=T")
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Generate(%q) = %v; expected %q", tc.src, err, tc.want)
+		}
+	}
+}
+
+func TestParam(t *testing.T) {
+	for field, want := range map[string]string{
+		"ID":       "id",
+		"Name":     "name",
+		"HTTPPort": "httpPort",
+		"Type":     "typeValue",
+		"B":        "bValue",
+		"URL":      "url",
+	} {
+		if got := param(field); got != want {
+			t.Errorf("param(%q) = %q; expected %q", field, got, want)
+		}
+	}
+}
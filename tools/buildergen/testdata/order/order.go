@@ -0,0 +1,20 @@
+package order
+
+import (
+	"net/url"
+	"time"
+)
+
+type Order struct {
+	ID       int           `json:"id" builder:"required"`
+	Customer string        `builder:"required"`
+	Status   string        `builder:"default=pending"`
+	Timeout  time.Duration `builder:"default=5 * time.Second"`
+	Tags     []string
+	Type     string
+	HTTPURL  *url.URL
+	Internal string `builder:"-"`
+	note     string
+}
+
+func (o Order) Validate() error { return nil }
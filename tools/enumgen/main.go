@@ -0,0 +1,413 @@
+// Enumgen
+// Enumgen is a tool to automate the creation of methods for enumerations (iota-based integer types).
+// It extends the idea of the stringer tool: given the name of an integer type T that has constants defined,
+// enumgen creates a new self-contained Go source file with:
+//   - String() string, returning the name of the constant (unless -string=false);
+//   - ParseT(string) (T, error), the inverse of String;
+//   - TValues() []T, with all the constants in declaration order;
+//   - IsValid() bool, reporting whether the value is one of the constants;
+//   - MarshalText/UnmarshalText and MarshalJSON/UnmarshalJSON, so the names are used in JSON, XML, etc;
+//   - Scan/Value, so the names are stored in SQL databases (only with -sql).
+//
+// The names can be changed with -trimprefix, or with -linecomment, which uses the line comment of each constant.
+// The file is created in the same package and directory as the package that defines T.
+
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Setup
+// The tool can be installed using the command below, and used with the //go:generate directive.
+var _ = `
+  go install ./enumgen
+  //go:generate enumgen -type=Color
+`
+
+// Flags
+// The flags below configure the generated code.
+var (
+	typeName    = flag.String("type", "", "integer type name; must be set")
+	output      = flag.String("output", "", "output file name; default srcdir/<type>_enum.go")
+	trimPrefix  = flag.String("trimprefix", "", "trim the prefix from the generated constant names")
+	lineComment = flag.Bool("linecomment", false, "use line comment text as printed text when present")
+	withString  = flag.Bool("string", true, "generate the String method; disable when it is generated by stringer")
+	withText    = flag.Bool("text", true, "generate the MarshalText and UnmarshalText methods")
+	withJSON    = flag.Bool("json", true, "generate the MarshalJSON and UnmarshalJSON methods")
+	withSQL     = flag.Bool("sql", false, "generate the Scan and Value methods for database/sql")
+)
+
+// Usage
+// The function below replaces the default usage message.
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of enumgen:\n")
+	fmt.Fprintf(os.Stderr, "\tenumgen [flags] -type T [directory]\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("enumgen: ")
+	flag.Usage = usage
+	flag.Parse()
+	if *typeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+	out := *output
+	if out == "" {
+		out = filepath.Join(dir, strings.ToLower(*typeName)+"_enum.go")
+	}
+	opts := Options{
+		TrimPrefix:  *trimPrefix,
+		LineComment: *lineComment,
+		String:      *withString,
+		Text:        *withText,
+		JSON:        *withJSON,
+		SQL:         *withSQL,
+	}
+	src, err := Generate(dir, *typeName, filepath.Base(out), "enumgen "+strings.Join(os.Args[1:], " "), opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Options
+// The struct below holds the options of the generator, so it can be used without the flags (e.g., in tests).
+type Options struct {
+	TrimPrefix  string
+	LineComment bool
+	String      bool
+	Text        bool
+	JSON        bool
+	SQL         bool
+}
+
+// Value
+// The struct below describes a constant of the enumeration.
+type Value struct {
+	Ident string // Name of the constant in the source code
+	Name  string // Printed name
+	Value constant.Value
+}
+
+// Enum
+// The struct below describes the enumeration, as read from the source code.
+type Enum struct {
+	Package  string
+	Type     string
+	Unsigned bool
+	Values   []Value
+}
+
+// Generate
+// The function below loads the enumeration from the package in the directory, and returns the generated code.
+// The skip file (usually the output file) is not parsed, so an outdated file does not affect the new one.
+func Generate(dir, typ, skip, command string, opts Options) ([]byte, error) {
+	e, err := Load(dir, typ, skip, opts)
+	if err != nil {
+		return nil, err
+	}
+	return Render(e, command, opts)
+}
+
+// Load
+// The function below parses and type-checks the package, so the values of the constants (iota) are known.
+// Type errors are ignored, since the package may be incomplete before the code is generated
+// (e.g., it may call ParseColor, which does not exist yet).
+// The imported packages are not loaded, which makes the tool fast, and independent of the build environment.
+// The constants of an enumeration rarely depend on other packages, and when they do, an error is reported.
+func Load(dir, typ, skip string, opts Options) (*Enum, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == skip {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	info := &types.Info{Defs: map[*ast.Ident]types.Object{}}
+	conf := types.Config{Importer: emptyImporter{}, Error: func(error) {}}
+	pkg, _ := conf.Check(files[0].Name.Name, fset, files, info)
+
+	obj, ok := pkg.Scope().Lookup(typ).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in %s", typ, dir)
+	}
+	basic, ok := obj.Type().Underlying().(*types.Basic)
+	if !ok || basic.Info()&types.IsInteger == 0 {
+		return nil, fmt.Errorf("%s is not an integer type", typ)
+	}
+	e := &Enum{Package: pkg.Name(), Type: typ, Unsigned: basic.Info()&types.IsUnsigned != 0}
+
+	seen := map[string]bool{}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for _, id := range vs.Names {
+					c, ok := info.Defs[id].(*types.Const)
+					if !ok || c.Type() != obj.Type() || id.Name == "_" {
+						continue
+					}
+					// Aliases (constants with the same value) use the name of the first constant.
+					key := c.Val().ExactString()
+					if seen[key] {
+						continue
+					}
+					seen[key] = true
+					name := strings.TrimPrefix(id.Name, opts.TrimPrefix)
+					if opts.LineComment && vs.Comment != nil && len(vs.Names) == 1 {
+						name = strings.TrimSpace(vs.Comment.Text())
+					}
+					e.Values = append(e.Values, Value{Ident: id.Name, Name: name, Value: c.Val()})
+				}
+			}
+		}
+	}
+	for _, v := range e.Values {
+		if v.Value.Kind() != constant.Int {
+			return nil, fmt.Errorf("cannot evaluate the value of %s", v.Ident)
+		}
+	}
+	if len(e.Values) == 0 {
+		return nil, fmt.Errorf("no values defined for type %s", typ)
+	}
+	names := map[string]string{}
+	for _, v := range e.Values {
+		if prev, ok := names[v.Name]; ok {
+			return nil, fmt.Errorf("%s and %s have the same name %q", prev, v.Ident, v.Name)
+		}
+		names[v.Name] = v.Ident
+	}
+	return e, nil
+}
+
+// Empty Importer
+// The importer below returns empty packages, so the references to imported packages are type errors.
+type emptyImporter struct{}
+
+func (emptyImporter) Import(path string) (*types.Package, error) {
+	pkg := types.NewPackage(path, path[strings.LastIndex(path, "/")+1:])
+	pkg.MarkComplete()
+	return pkg, nil
+}
+
+// Render
+// The function below writes the source code, and formats it with gofmt.
+func Render(e *Enum, command string, opts Options) ([]byte, error) {
+	var b bytes.Buffer
+	p := func(format string, args ...any) { fmt.Fprintf(&b, format+"\n", args...) }
+	T := e.Type
+
+	imports := []string{"fmt"}
+	if opts.String {
+		imports = append(imports, "strconv")
+	}
+	if opts.JSON {
+		imports = append(imports, "encoding/json")
+	}
+	if opts.SQL {
+		imports = append(imports, "database/sql/driver")
+	}
+	slices.SortFunc(imports, cmp.Compare)
+
+	p("// Code generated by %q; DO NOT EDIT.", command)
+	p("")
+	p("package %s", e.Package)
+	p("")
+	p("import (")
+	for _, imp := range imports {
+		p("\t%q", imp)
+	}
+	p(")")
+	p("")
+
+	// The function below fails to compile when the values of the constants change, like in the stringer tool.
+	p("func _() {")
+	p("\t// An \"invalid array index\" compiler error signifies that the constant values have changed.")
+	p("\t// Re-run the enumgen command to generate them again.")
+	p("\tvar x [1]struct{}")
+	for _, v := range e.Values {
+		value := v.Value.ExactString()
+		if strings.HasPrefix(value, "-") {
+			value = "(" + value + ")"
+		}
+		p("\t_ = x[%s-%s]", v.Ident, value)
+	}
+	p("}")
+	p("")
+
+	idents := make([]string, len(e.Values))
+	for i, v := range e.Values {
+		idents[i] = v.Ident
+	}
+	p("var _%sValues = []%s{%s}", T, T, strings.Join(idents, ", "))
+	p("")
+
+	if opts.String {
+		format := "strconv.FormatInt(int64(i), 10)"
+		if e.Unsigned {
+			format = "strconv.FormatUint(uint64(i), 10)"
+		}
+		p("// String returns the name of the %s value.", T)
+		p("func (i %s) String() string {", T)
+		p("\tswitch i {")
+		for _, v := range e.Values {
+			p("\tcase %s:", v.Ident)
+			p("\t\treturn %q", v.Name)
+		}
+		p("\t}")
+		p("\treturn %q + %s + \")\"", T+"(", format)
+		p("}")
+		p("")
+	}
+
+	p("// Parse%s returns the %s value with the name.", T, T)
+	p("func Parse%s(s string) (%s, error) {", T, T)
+	p("\tswitch s {")
+	for _, v := range e.Values {
+		p("\tcase %q:", v.Name)
+		p("\t\treturn %s, nil", v.Ident)
+	}
+	p("\t}")
+	p("\treturn 0, fmt.Errorf(\"invalid %s: %%q\", s)", T)
+	p("}")
+	p("")
+
+	p("// %sValues returns all the %s values, in declaration order.", T, T)
+	p("func %sValues() []%s {", T, T)
+	p("\treturn append([]%s(nil), _%sValues...)", T, T)
+	p("}")
+	p("")
+
+	p("// IsValid reports whether the value is one of the %s constants.", T)
+	p("func (i %s) IsValid() bool {", T)
+	p("\tswitch i {")
+	p("\tcase %s:", strings.Join(idents, ", "))
+	p("\t\treturn true")
+	p("\t}")
+	p("\treturn false")
+	p("}")
+	p("")
+
+	if opts.Text {
+		p("// MarshalText implements the encoding.TextMarshaler interface.")
+		p("func (i %s) MarshalText() ([]byte, error) {", T)
+		p("\tif !i.IsValid() {")
+		p("\t\treturn nil, fmt.Errorf(\"invalid %s: %%d\", i)", T)
+		p("\t}")
+		p("\treturn []byte(i.String()), nil")
+		p("}")
+		p("")
+		p("// UnmarshalText implements the encoding.TextUnmarshaler interface.")
+		p("func (i *%s) UnmarshalText(text []byte) error {", T)
+		p("\tv, err := Parse%s(string(text))", T)
+		p("\tif err != nil {")
+		p("\t\treturn err")
+		p("\t}")
+		p("\t*i = v")
+		p("\treturn nil")
+		p("}")
+		p("")
+	}
+
+	if opts.JSON {
+		p("// MarshalJSON implements the json.Marshaler interface.")
+		p("func (i %s) MarshalJSON() ([]byte, error) {", T)
+		p("\tif !i.IsValid() {")
+		p("\t\treturn nil, fmt.Errorf(\"invalid %s: %%d\", i)", T)
+		p("\t}")
+		p("\treturn json.Marshal(i.String())")
+		p("}")
+		p("")
+		p("// UnmarshalJSON implements the json.Unmarshaler interface.")
+		p("func (i *%s) UnmarshalJSON(data []byte) error {", T)
+		p("\tvar s string")
+		p("\tif err := json.Unmarshal(data, &s); err != nil {")
+		p("\t\treturn fmt.Errorf(\"%s should be a string, got %%s\", data)", T)
+		p("\t}")
+		p("\tv, err := Parse%s(s)", T)
+		p("\tif err != nil {")
+		p("\t\treturn err")
+		p("\t}")
+		p("\t*i = v")
+		p("\treturn nil")
+		p("}")
+		p("")
+	}
+
+	if opts.SQL {
+		p("// Value implements the driver.Valuer interface, so the name is stored in the database.")
+		p("func (i %s) Value() (driver.Value, error) {", T)
+		p("\tif !i.IsValid() {")
+		p("\t\treturn nil, fmt.Errorf(\"invalid %s: %%d\", i)", T)
+		p("\t}")
+		p("\treturn i.String(), nil")
+		p("}")
+		p("")
+		p("// Scan implements the sql.Scanner interface.")
+		p("func (i *%s) Scan(value any) error {", T)
+		p("\tvar s string")
+		p("\tswitch v := value.(type) {")
+		p("\tcase string:")
+		p("\t\ts = v")
+		p("\tcase []byte:")
+		p("\t\ts = string(v)")
+		p("\tdefault:")
+		p("\t\treturn fmt.Errorf(\"cannot scan %%T into %s\", value)", T)
+		p("\t}")
+		p("\tv, err := Parse%s(s)", T)
+		p("\tif err != nil {")
+		p("\t\treturn err")
+		p("\t}")
+		p("\t*i = v")
+		p("\treturn nil")
+		p("}")
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid generated code: %w\n%s", err, b.Bytes())
+	}
+	return src, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tools/internal/golden"
+)
+
+var allMethods = Options{String: true, Text: true, JSON: true, SQL: true}
+
+func TestGenerateGolden(t *testing.T) {
+	opts := allMethods
+	opts.TrimPrefix, opts.LineComment = "Day", true
+	got, err := Generate("testdata/weekday", "Weekday", "", "enumgen -type=Weekday -trimprefix=Day -linecomment -sql", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden.Assert(t, "weekday_enum", got)
+
+	got, err = Generate("testdata/weekday", "Level", "", "enumgen -type=Level -json=false -text=false", Options{String: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden.Assert(t, "level_enum", got)
+}
+
+// The generated files in the repository must be up to date with their enumerations.
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	for _, tc := range []struct {
+		dir, file, command string
+		opts               Options
+	}{
+		{"../../guide/patterns", "color_enum.go", "enumgen -type=Color", Options{String: true, Text: true, JSON: true}},
+		{"../stringer", "color_enum.go", "enumgen -type=Color -string=false -sql", Options{Text: true, JSON: true, SQL: true}},
+	} {
+		want, err := os.ReadFile(filepath.Join(tc.dir, tc.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Generate(tc.dir, "Color", tc.file, tc.command, tc.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is outdated; run go generate in %s", tc.file, tc.dir)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	for _, tc := range []struct{ src, want string }{
+		{"type T string\nconst A T = \"a\"", "T is not an integer type"},
+		{"type U int", "type T not found"},
+		{"type T int", "no values defined for type T"},
+		{"type T int\nconst (\nA T = iota // x\nB // x\n)", `A and B have the same name "x"`},
+		{"import \"time\"\ntype T int\nconst A T = T(time.Second)", "cannot evaluate the value of A"},
+	} {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "t.go"), []byte("package p\n"+tc.src+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Generate(dir, "T", "", "enumgen -type=T", Options{String: true, LineComment: true})
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Generate(%q) = %v; expected %q", tc.src, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package weekday
+
+type Weekday uint8
+
+const (
+	DaySunday  Weekday = iota // sun
+	DayMonday                 // mon
+	DayTuesday                // tue
+	DayWednesday
+	DayFirst = DaySunday // Alias, skipped
+)
+
+const DaySaturday Weekday = 6 // sat
+
+type Level int
+
+const (
+	LevelDebug Level = iota - 1
+	LevelInfo
+	LevelWarn
+)
+
+var _ = undefinedFunction() // Type errors are ignored
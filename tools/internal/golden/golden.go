@@ -0,0 +1,38 @@
+// Golden Files
+// The package below compares the output of a test with a golden file in the testdata folder of the package.
+// It is shared by the tests of the module, so the -update flag is defined only once.
+// To update the files after an intended change, run: "go test -run <Test> -update".
+
+package golden
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// Assert
+// The function below compares got with the file "testdata/<name>.golden".
+// With the -update flag, the file is written before the comparison.
+func Assert(t testing.TB, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s mismatch (run with -update):\n--- got ---\n%s--- want ---\n%s", path, got, want)
+	}
+}
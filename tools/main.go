@@ -23,3 +23,13 @@ var _ = `
 go clean -i <module-path>
 go clean -i golang.org/x/tools/cmd/stringer
 `
+
+// Local Tools
+// Tools can also be part of our own module, in a directory with a main package.
+// This module has two code generators, which are used with the //go:generate directive:
+// - buildergen: generates builders for structs (see buildergen/main.go);
+// - enumgen: generates parsing, validation and marshaling methods for enumerations (see enumgen/main.go).
+var _ = `
+go install ./buildergen ./enumgen
+go generate ./...
+`
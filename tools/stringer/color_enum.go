@@ -0,0 +1,114 @@
+// Code generated by "enumgen -type=Color -string=false -sql"; DO NOT EDIT.
+
+package stringer
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the enumgen command to generate them again.
+	var x [1]struct{}
+	_ = x[Red-0]
+	_ = x[Green-1]
+	_ = x[Blue-2]
+}
+
+var _ColorValues = []Color{Red, Green, Blue}
+
+// ParseColor returns the Color value with the name.
+func ParseColor(s string) (Color, error) {
+	switch s {
+	case "Red":
+		return Red, nil
+	case "Green":
+		return Green, nil
+	case "Blue":
+		return Blue, nil
+	}
+	return 0, fmt.Errorf("invalid Color: %q", s)
+}
+
+// ColorValues returns all the Color values, in declaration order.
+func ColorValues() []Color {
+	return append([]Color(nil), _ColorValues...)
+}
+
+// IsValid reports whether the value is one of the Color constants.
+func (i Color) IsValid() bool {
+	switch i {
+	case Red, Green, Blue:
+		return true
+	}
+	return false
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (i Color) MarshalText() ([]byte, error) {
+	if !i.IsValid() {
+		return nil, fmt.Errorf("invalid Color: %d", i)
+	}
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (i *Color) UnmarshalText(text []byte) error {
+	v, err := ParseColor(string(text))
+	if err != nil {
+		return err
+	}
+	*i = v
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (i Color) MarshalJSON() ([]byte, error) {
+	if !i.IsValid() {
+		return nil, fmt.Errorf("invalid Color: %d", i)
+	}
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (i *Color) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("Color should be a string, got %s", data)
+	}
+	v, err := ParseColor(s)
+	if err != nil {
+		return err
+	}
+	*i = v
+	return nil
+}
+
+// Value implements the driver.Valuer interface, so the name is stored in the database.
+func (i Color) Value() (driver.Value, error) {
+	if !i.IsValid() {
+		return nil, fmt.Errorf("invalid Color: %d", i)
+	}
+	return i.String(), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (i *Color) Scan(value any) error {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Color", value)
+	}
+	v, err := ParseColor(s)
+	if err != nil {
+		return err
+	}
+	*i = v
+	return nil
+}
@@ -0,0 +1,62 @@
+package stringer
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+)
+
+func TestColorRoundTrip(t *testing.T) {
+	for _, c := range ColorValues() {
+		parsed, err := ParseColor(c.String())
+		if err != nil || parsed != c {
+			t.Errorf("ParseColor(%q) = %v, %v; expected %v", c.String(), parsed, err, c)
+		}
+		text, err := c.MarshalText()
+		if err != nil || string(text) != c.String() {
+			t.Errorf("MarshalText(%v) = %q, %v", c, text, err)
+		}
+	}
+	if got := ColorValues(); len(got) != 3 || got[0] != Red || got[2] != Blue {
+		t.Errorf("ColorValues() = %v", got)
+	}
+}
+
+func TestColorSQL(t *testing.T) {
+	v, err := Green.Value()
+	if err != nil || v != driver.Value("Green") {
+		t.Errorf("Value() = %v, %v; expected Green", v, err)
+	}
+	for _, src := range []any{"Blue", []byte("Blue")} {
+		var c Color
+		if err := c.Scan(src); err != nil || c != Blue {
+			t.Errorf("Scan(%#v) = %v, %v; expected Blue", src, c, err)
+		}
+	}
+	var c Color
+	for _, src := range []any{nil, int64(1), "Purple"} {
+		if err := c.Scan(src); err == nil {
+			t.Errorf("Scan(%#v) must fail", src)
+		}
+	}
+	if _, err := Color(5).Value(); err == nil {
+		t.Errorf("Value() of an invalid color must fail")
+	}
+}
+
+func TestColorJSON(t *testing.T) {
+	data, err := json.Marshal([]Color{Red, Blue})
+	if err != nil || string(data) != `["Red","Blue"]` {
+		t.Errorf("Marshal = %s, %v", data, err)
+	}
+	var colors []Color
+	if err := json.Unmarshal(data, &colors); err != nil || len(colors) != 2 || colors[1] != Blue {
+		t.Errorf("Unmarshal = %v, %v", colors, err)
+	}
+	if err := json.Unmarshal([]byte(`[1]`), &colors); err == nil {
+		t.Errorf("Unmarshal of numbers must fail")
+	}
+	if _, err := json.Marshal(Color(-1)); err == nil {
+		t.Errorf("Marshal of an invalid color must fail")
+	}
+}
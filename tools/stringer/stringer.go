@@ -40,3 +40,11 @@ go generate stringer -type=Color
 // We can also use the //go:generate directive to automate the code generation process.
 //
 //go:generate stringer -type=Color
+
+// Enumgen
+// The stringer tool generates only the String method.
+// The enumgen tool (see tools/enumgen) generates the other methods needed by enumerations:
+// parsing, validation, text and JSON marshaling, and SQL scanning.
+// Since the String method is already generated by stringer, it is disabled with the -string=false flag.
+//
+//go:generate enumgen -type=Color -string=false -sql